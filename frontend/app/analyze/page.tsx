@@ -1,9 +1,10 @@
 // frontend/app/analyze/page.tsx
 "use client";
 
-import { FormEvent, useState } from "react";
+import { FormEvent, useEffect, useRef, useState } from "react";
 import { useRouter } from "next/navigation";
-import { createDSAJob } from "@/lib/api";
+import { createDSAJob, fetchAnalysisDefaults } from "@/lib/api";
+import { randomUUID } from "@/lib/utils";
 import type { AnalysisParams } from "@/types/dsa";
 
 export default function AnalyzePage() {
@@ -21,6 +22,25 @@ export default function AnalyzePage() {
 
   const [isSubmitting, setIsSubmitting] = useState(false);
   const [error, setError] = useState<string | null>(null);
+  // 同じ内容の送信には同じ Idempotency-Key を使い、二重送信でジョブが重複しないようにする
+  const submission = useRef<{ body: string; key: string } | null>(null);
+
+  // サーバーの既定値でフォームを初期化する（取得できなければ上の初期値のまま）
+  useEffect(() => {
+    fetchAnalysisDefaults()
+      .then((defaults) => {
+        setMethod(defaults.method);
+        setSeqRatio(defaults.seq_ratio);
+        setCisThreshold(defaults.cis_threshold);
+        setExportCsv(defaults.export);
+        setHeatmap(defaults.heatmap);
+        setProcCis(defaults.proc_cis);
+        setOverwrite(defaults.overwrite);
+      })
+      .catch((e) => {
+        console.warn("Could not load server defaults, using the built-in values:", e);
+      });
+  }, []);
 
   async function handleSubmit(e: FormEvent) {
     e.preventDefault();
@@ -70,7 +90,11 @@ export default function AnalyzePage() {
         JSON.stringify(params, null, 2)
       );
 
-      const jobsResponse = await createDSAJob(params);
+      const body = JSON.stringify(params);
+      if (submission.current?.body !== body) {
+        submission.current = { body, key: randomUUID() };
+      }
+      const jobsResponse = await createDSAJob(params, submission.current.key);
 
       // 複数のジョブが作成された場合は比較ページに遷移、1つの場合は通常の結果ページに遷移
       if (jobsResponse.jobs.length === 1) {
@@ -1,17 +1,32 @@
 // frontend/lib/api.ts
 
 import type {
+  AnalysisDefaults,
   AnalysisParams,
   JobResponse,
   JobsResponse,
   JobStatus,
   NotebookDSAResult,
+  ErrorCode,
   ErrorResponse,
 } from "@/types/dsa";
 
 const API_BASE_URL =
   process.env.NEXT_PUBLIC_DSA_API_URL ?? "http://localhost:8080";
 
+// ApiError は API のエラー応答（code で種類を判別できる）
+export class ApiError extends Error {
+  constructor(
+    message: string,
+    readonly status: number,
+    readonly code?: ErrorCode,
+    readonly details?: ErrorResponse["details"]
+  ) {
+    super(message);
+    this.name = "ApiError";
+  }
+}
+
 async function handleResponse<T>(res: Response): Promise<T> {
   if (!res.ok) {
     let message = `Request failed with status ${res.status}`;
@@ -32,15 +47,17 @@ async function handleResponse<T>(res: Response): Promise<T> {
       // ignore json parse error
     }
     console.error("[DEBUG] handleResponse - Throwing error:", message);
-    throw new Error(message);
+    throw new ApiError(message, res.status, errorData?.code, errorData?.details);
   }
   const jsonData = await res.json();
   console.log("[DEBUG] handleResponse - Success response:", jsonData);
   return jsonData as T;
 }
 
+// idempotencyKey を渡すと、同じキー・同じ params の再送（二重クリックや再試行）では既存のジョブが返る
 export async function createDSAJob(
-  params: AnalysisParams
+  params: AnalysisParams,
+  idempotencyKey?: string
 ): Promise<JobsResponse> {
   // デバッグ: 送信するパラメータをログ出力
   console.log(
@@ -59,6 +76,7 @@ export async function createDSAJob(
     method: "POST",
     headers: {
       "Content-Type": "application/json",
+      ...(idempotencyKey ? { "Idempotency-Key": idempotencyKey } : {}),
     },
     body: requestBody,
   });
@@ -69,6 +87,14 @@ export async function createDSAJob(
   return handleResponse<JobsResponse>(res);
 }
 
+// fetchAnalysisDefaults はサーバーの既定値（フォームの初期値に使う）を取得
+export async function fetchAnalysisDefaults(): Promise<AnalysisDefaults> {
+  const res = await fetch(`${API_BASE_URL}/api/dsa/defaults`, {
+    method: "GET",
+  });
+  return handleResponse<AnalysisDefaults>(res);
+}
+
 export async function fetchJobStatus(jobId: string): Promise<JobStatus> {
   const res = await fetch(`${API_BASE_URL}/api/dsa/status/${jobId}`, {
     method: "GET",
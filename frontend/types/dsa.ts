@@ -5,11 +5,25 @@ export interface AnalysisParams {
   method?: string; // "X-ray", "NMR", "EM"
   seq_ratio?: number; // 0.0-1.0
   negative_pdbid?: string; // 除外するPDB ID（スペースまたはカンマ区切り）
+  pdb_ids?: string[]; // 解析するPDB IDを明示する（UniProt IDが1つのときのみ。UniProtに無いIDは除外）
   cis_threshold?: number; // cis判定の距離閾値
   export?: boolean; // CSV出力するか
   heatmap?: boolean; // ヒートマップを生成するか
   proc_cis?: boolean; // cis解析を行うか
   overwrite?: boolean; // 上書きするか
+  reference_offset?: number; // residue_number を UniProt 上の位置に合わせるずれ（residue_number = index + 1 + reference_offset）
+  priority?: "low" | "normal" | "high"; // 既定 normal。high はサーバーが許可した API キーのみ
+}
+
+// GET /api/dsa/defaults（AnalysisParams を省略したときにサーバーが使う値）
+export interface AnalysisDefaults {
+  method: string;
+  seq_ratio: number;
+  cis_threshold: number;
+  export: boolean;
+  heatmap: boolean;
+  proc_cis: boolean;
+  overwrite: boolean;
 }
 
 export interface JobResponse {
@@ -30,6 +44,100 @@ export interface JobStatus {
   message: string;
   created_at: string;
   updated_at: string;
+  failure_reason?: "timeout" | "invalid_input" | "no_structures" | "engine_error" | "internal_error"; // failed のときのみ
+  reason?: "no_suitable_structures" | "input_too_large"; // 失敗理由を判別できた場合のみ
+  duration_seconds?: number; // pending では省略
+  queue_position?: number; // ワーカー待ちの順番（1 が次）。キューで待っている間のみ
+  completed_at?: string; // 終了状態のときのみ
+}
+
+// 結果取得系エンドポイントが未完了のジョブに返す 202 の本文（Retry-After ヘッダーも付く）
+export interface JobNotCompleted {
+  error: string;
+  code: "job_not_completed";
+  status: JobStatus["status"];
+  progress: number;
+  estimated_remaining_seconds?: number; // 実行時間の実績が無ければ省略
+  retry_after_seconds: number;
+}
+
+// GET /api/dsa/jobs/:job_id/ws のフレーム
+export interface JobSocketMessage {
+  type: "status" | "error";
+  status?: JobStatus;
+  error?: string;
+}
+
+export interface JobSocketCommand {
+  action: "cancel" | "auth";
+  token?: string; // auth のみ。API キーを設定したサーバーでは接続直後に送る（ブラウザは X-API-Key を付けられない）
+}
+
+// GET /api/dsa/admin/status
+export interface AdminStatus {
+  running_jobs: RunningJob[]; // 開始が古い順
+  queue_depth: number;
+  max_concurrency: number;
+  storage_used_bytes: number;
+  storage_free_bytes: number;
+  storage_total_bytes: number;
+}
+
+export interface RunningJob {
+  job_id: string;
+  started_at: string; // ISO string
+  elapsed_seconds: number;
+}
+
+// GET /api/dsa/admin/health-detailed（degraded なら 503）
+export interface HealthDetail {
+  status: "ok" | "degraded";
+  problems: string[]; // degraded の理由
+  window: number; // 集計する終了済みジョブの件数の上限（?n=）
+  jobs: number; // 集計した終了済みジョブの件数
+  completed: number;
+  failed: number; // interrupted を含む
+  cancelled: number;
+  success_rate: number | null; // completed / (completed + failed)
+  average_duration_seconds: number | null; // completed のジョブの作成から完了までの平均
+  job_timeout_seconds: number;
+  stuck_jobs: StuckJob[]; // 制限時間を超えて processing のままのジョブ
+}
+
+export interface StuckJob {
+  job_id: string;
+  started_at: string; // ISO string（orphaned なら最後にステータスが更新された時刻）
+  elapsed_seconds: number;
+  orphaned: boolean; // サーバーで実行されていない
+}
+
+export interface JobFile {
+  name: string; // ジョブ配下の相対パス
+  size: number; // バイト数
+  type: string; // 拡張子から判定した Content-Type
+}
+
+export interface StructureDetail {
+  pdb_id: string;
+  method: string;
+  resolution: number | null; // Å（NMR・不明なら null）
+  num_chains: number; // 不明なら 0
+  chains_used: number; // seq_ratio の絞り込み後
+  mutation?: "normal" | "substitution" | "chimera" | "delins";
+  included: boolean;
+  reason?: "negative_pdbid" | "error" | "chimera" | "delins" | "unclassified" | "seq_ratio" | "not_in_uniprot";
+}
+
+export interface ExcludedStructure {
+  pdb_id: string;
+  reason?: StructureDetail["reason"]; // 理由を記録していないジョブでは省略
+}
+
+export interface JobStructures {
+  job_id: string;
+  uniprot_id: string;
+  source: "engine" | "derived"; // derived: 古いジョブ（解析に使ったエントリのみ）
+  structures: StructureDetail[];
 }
 
 // ---- NotebookDSAResult ----
@@ -45,7 +153,7 @@ export interface PairScore {
 
 export interface PerResidueScore {
   index: number; // 0-based
-  residue_number: number; // 1-based
+  residue_number: number; // 1-based（UniProt 上の位置、reference_offset を含む）
   residue_name: string;
   score: number;
 }
@@ -53,6 +161,41 @@ export interface PerResidueScore {
 export interface Heatmap {
   size: number;
   values: (number | null)[][]; // NaN は null として受ける前提
+  // heatmap.json で vmin / vmax / scale を指定した場合のみ
+  vmin?: number;
+  vmax?: number;
+  scale?: "clamp" | "normalize";
+}
+
+// GET /api/dsa/jobs/:job_id/heatmap.json?format=sparse
+export interface SparseHeatmap {
+  size: number;
+  cells: HeatmapCell[]; // null のセルは含まない
+  vmin?: number; // Heatmap と同じ
+  vmax?: number;
+  scale?: "clamp" | "normalize";
+}
+
+export interface HeatmapCell {
+  i: number; // 0-based
+  j: number; // 0-based
+  value: number;
+}
+
+// GET /api/dsa/jobs/:job_id/distance-score.json?max_points=N
+export interface DistanceScorePoints {
+  job_id: string;
+  total: number; // 間引く前の点数
+  downsampled: boolean; // max_points を超えたので間引いたか
+  points: DistanceScorePoint[]; // score 降順
+}
+
+export interface DistanceScorePoint {
+  i: number; // 1-based
+  j: number; // 1-based
+  residue_pair: string; // ツールチップ用 "ALA-123, GLY-145"
+  mean_distance: number; // x 軸
+  score: number; // y 軸
 }
 
 export interface CisInfo {
@@ -65,13 +208,31 @@ export interface CisInfo {
   threshold: number;
 }
 
+// GET /api/dsa/jobs/:job_id/cis
+export interface JobCisInfo {
+  job_id: string;
+  uniprot_id: string;
+  cis_info: CisInfo;
+  pairs: CisPair[];
+}
+
+export interface CisPair {
+  i: number; // 1-based
+  j: number; // 1-based
+  residue_i?: string; // 3文字コード（例: "PRO"）
+  residue_j?: string;
+}
+
 export interface NotebookDSAResult {
+  schema_version: number; // result.json の形式のバージョン
+
   // メタデータ
   uniprot_id: string;
   num_structures: number;
   num_residues: number;
   pdb_ids: string[];
   excluded_pdbs: string[];
+  excluded_structures: ExcludedStructure[]; // excluded_pdbs と同じ順
   seq_ratio: number;
   method: string;
 
@@ -93,13 +254,50 @@ export interface NotebookDSAResult {
   per_residue_scores: PerResidueScore[];
 
   // ヒートマップ
-  heatmap: Heatmap | null;
+  heatmap: Heatmap | null; // 残基数がサーバーの上限を超える場合は null
 
   // Cis 統計
   cis_info: CisInfo;
+
+  // 失敗・キャンセルしたジョブの途中の出力から組み立てた結果（?partial=true の場合のみ）
+  partial?: boolean;
 }
 
+// エラー応答（全エンドポイント共通）。判別には文言ではなく code を使う
+export type ErrorCode =
+  | "invalid_request"
+  | "invalid_params"
+  | "invalid_job_id"
+  | "invalid_cursor"
+  | "unauthorized"
+  | "priority_not_allowed"
+  | "rate_limited"
+  | "payload_too_large"
+  | "not_found"
+  | "job_not_found"
+  | "batch_not_found"
+  | "job_not_completed"
+  | "no_job_result"
+  | "job_finished"
+  | "job_in_progress"
+  | "no_partial_result"
+  | "no_job_logs"
+  | "no_job_command"
+  | "idempotency_key_reused"
+  | "idempotency_key_in_progress"
+  | "internal_error";
+
 export interface ErrorResponse {
-  error: string;
+  error: string; // 人が読むためのメッセージ
+  code: ErrorCode;
+  details?: {
+    reason?: string; // invalid_request（本文の解釈に失敗した理由）
+    fields?: Record<string, string>; // invalid_request（項目名 → メッセージ。項目ごとに分かる場合のみ）
+    invalid_uniprot_ids?: string[]; // invalid_params
+    invalid_pdb_ids?: string[]; // invalid_params
+    retry_after?: number; // rate_limited
+    status?: "failed" | "cancelled" | "interrupted"; // no_job_result（結果取得系で 409）
+    failure_reason?: JobStatus["failure_reason"]; // no_job_result（failed のときのみ）
+  };
   partial_result?: unknown;
 }
@@ -1,64 +1,345 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/yourusername/flex-api/internal/handlers"
 	"github.com/yourusername/flex-api/internal/services"
 )
 
+// shutdownHTTPExtra はジョブの猶予期間後、HTTP 接続の終了を追加で待つ時間
+const shutdownHTTPExtra = 5 * time.Second
+
+// main は API サーバーを起動する
+//
+//	@title						Flex API
+//	@version					1.0.0
+//	@description				Notebook DSA analysis API. Routes under /api/dsa require X-API-Key when the server is started with -api-keys or -api-keys-file.
+//	@BasePath					/
+//	@securityDefinitions.apikey	ApiKey
+//	@in							header
+//	@name						X-API-Key
 func main() {
 	// コマンドラインフラグ
 	port := flag.String("port", "8080", "Server port")
-	storageDir := flag.String("storage", "./storage", "Storage directory for jobs")
+	storageDir := flag.String("storage", "./storage", "Storage directory for jobs (local work directory when -storage-backend=s3)")
+	storageShard := flag.Bool("storage-shard", true, "Keep job directories under a subdirectory named after the first two characters of the job ID; existing flat directories are moved at startup")
+	storageBackend := flag.String("storage-backend", "local", "Where job artifacts are kept: local or s3. Run a single server even with s3: the queue, cancellation, batch runs and Idempotency-Keys live in process memory")
+	s3Bucket := flag.String("s3-bucket", os.Getenv("S3_BUCKET"), "S3 bucket for -storage-backend=s3 (default: $S3_BUCKET)")
+	s3Prefix := flag.String("s3-prefix", "", "Key prefix for job artifacts in the S3 bucket")
+	s3Region := flag.String("s3-region", defaultS3Region(), "S3 region (default: $AWS_REGION or us-east-1)")
+	s3Endpoint := flag.String("s3-endpoint", os.Getenv("S3_ENDPOINT"), "S3-compatible endpoint URL such as MinIO, using path-style requests (default: AWS)")
 	pythonBin := flag.String("python", "python3", "Python binary path")
+	pythonEngineDir := flag.String("python-engine-dir", os.Getenv("PYTHON_ENGINE_DIR"), "python-engine directory used as the Python CLI working directory (default: $PYTHON_ENGINE_DIR)")
+	pythonEnv := flag.String("python-env", "", "Comma-separated KEY=VALUE environment variables added to every Python CLI run, e.g. a PDB cache directory (overrides PYTHONPATH=./src if set)")
+	jobIDFormat := flag.String("job-id-format", services.JobIDFormatUUID, "Job ID format: uuid or short")
+	maxConcurrent := flag.Int("max-concurrent", 4, "Max Python analyses running at once")
+	batchConcurrency := flag.Int("batch-concurrency", 4, "Max jobs of a single batch running at once")
+	subprocessNice := flag.Int("subprocess-nice", 0, "Niceness for Python subprocesses (-20 to 19, 0 = unchanged)")
+	subprocessCPUs := flag.String("subprocess-cpus", "", "CPU list to pin Python subprocesses to, e.g. 0-3 (requires taskset)")
+	maxUploadBytes := flag.Int64("max-upload-bytes", 64<<20, "Max size in bytes of request bodies and uploaded files (larger requests get 413)")
+	resultCacheSize := flag.Int("result-cache-size", 32, "Number of parsed results kept in memory (0 disables the cache)")
+	resultTopPairs := flag.Int("result-top-pairs", 50000, "Max pair scores returned by GET /api/dsa/result, highest scores first (?top= overrides; 0 returns all)")
+	maxResidues := flag.Int("max-residues", 3000, "Reject UniProt entries longer than this many residues, and omit the heatmap from larger results (0 disables)")
+	maxStructures := flag.Int("max-structures", 300, "Reject UniProt entries with more PDB entries than this (0 disables)")
+	jobTimeout := flag.Duration("job-timeout", 30*time.Minute, "Kill a Python analysis that runs longer than this; jobs still processing past it are reported as stuck")
+	defaultsFile := flag.String("defaults-file", os.Getenv("ANALYSIS_DEFAULTS_FILE"), "JSON file overriding the defaults of unset analysis parameters, e.g. {\"seq_ratio\": 0.3} (default: $ANALYSIS_DEFAULTS_FILE)")
+	keepPDB := flag.Bool("keep-pdb", false, "Keep the downloaded structure files (pdb_files) of finished jobs for debugging; by default they are deleted when the job ends")
+	jobTTL := flag.Duration("job-ttl", 0, "Delete finished jobs this long after their last update, e.g. 72h (0 keeps them forever)")
+	idempotencyGrace := flag.Duration("idempotency-grace", 24*time.Hour, "Keep Idempotency-Key mappings this long after all of their jobs have finished")
+	downloadRetries := flag.Int("download-retries", 2, "Re-run the Python CLI this many times when a structure download fails transiently")
+	downloadRetryDelay := flag.Duration("download-retry-delay", 10*time.Second, "Wait before the first download retry (doubles on each retry)")
+	shutdownGrace := flag.Duration("shutdown-grace", 30*time.Second, "How long to wait for running jobs on SIGINT/SIGTERM before killing them")
+	corsOrigins := flag.String("cors-origins", defaultCORSOrigins(), "Comma-separated allowed CORS and WebSocket origins, or * for any (default: $CORS_ORIGINS or the local dev servers)")
+	apiKeys := flag.String("api-keys", os.Getenv("API_KEYS"), "Comma-separated API keys accepted in X-API-Key (default: $API_KEYS; empty leaves the API open)")
+	apiKeysFile := flag.String("api-keys-file", "", "File with one API key per line (# starts a comment), added to -api-keys")
+	priorityAPIKeys := flag.String("priority-api-keys", os.Getenv("PRIORITY_API_KEYS"), "Comma-separated API keys allowed to create priority=high jobs (default: $PRIORITY_API_KEYS; empty allows any -api-keys key)")
+	analyzeRate := flag.Float64("analyze-rate", 10, "Job-creating requests (analyze, retry) allowed per minute per API key or client IP (0 = unlimited)")
+	analyzeBurst := flag.Int("analyze-burst", 5, "Job-creating requests a client may send back to back before -analyze-rate applies")
+	readRate := flag.Float64("read-rate", 0, "Read requests (status, results, artifacts) allowed per minute per API key or client IP (0 = unlimited)")
+	readBurst := flag.Int("read-burst", 60, "Read requests a client may send back to back before -read-rate applies")
+	trustedProxies := flag.String("trusted-proxies", "", "Comma-separated proxy IPs or CIDRs whose X-Forwarded-For is used as the client IP for rate limits and logs (default: none; the peer address is used)")
+	logLevel := flag.String("log-level", "info", "Log level: debug, info, warn, error")
 	flag.Parse()
 
+	// ロガー設定
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		log.Fatalf("Invalid -log-level: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(logger)
+
 	// ストレージディレクトリ作成
 	if err := os.MkdirAll(*storageDir, 0755); err != nil {
 		log.Fatalf("Failed to create storage directory: %v", err)
 	}
 
+	// Python エンジンディレクトリ確認
+	if *pythonEngineDir == "" {
+		log.Fatalf("Python engine directory is not set: use -python-engine-dir or PYTHON_ENGINE_DIR")
+	}
+	engineDir, err := filepath.Abs(*pythonEngineDir)
+	if err != nil {
+		log.Fatalf("Failed to resolve python engine directory %s: %v", *pythonEngineDir, err)
+	}
+	if info, err := os.Stat(engineDir); err != nil || !info.IsDir() {
+		log.Fatalf("Python engine directory does not exist: %s", engineDir)
+	}
+
 	// サービス初期化
-	jobService := services.NewJobService(*storageDir, *pythonBin)
+	jobService := services.NewJobService(*storageDir, *pythonBin, engineDir, services.ExecRunner{}, logger)
+	if err := jobService.SetStorageSharding(*storageShard); err != nil {
+		log.Fatalf("Failed to shard storage directory: %v", err)
+	}
+	if err := jobService.SetJobIDFormat(*jobIDFormat); err != nil {
+		log.Fatalf("Invalid -job-id-format: %v", err)
+	}
+	if err := jobService.SetSubprocessPriority(*subprocessNice, *subprocessCPUs); err != nil {
+		log.Fatalf("Invalid subprocess priority: %v", err)
+	}
+	if err := jobService.SetMaxConcurrent(*maxConcurrent); err != nil {
+		log.Fatalf("Invalid -max-concurrent: %v", err)
+	}
+	if err := jobService.SetBatchConcurrencyCap(*batchConcurrency); err != nil {
+		log.Fatalf("Invalid -batch-concurrency: %v", err)
+	}
+	if err := jobService.SetResultCacheSize(*resultCacheSize); err != nil {
+		log.Fatalf("Invalid -result-cache-size: %v", err)
+	}
+	if err := jobService.SetResultTopPairs(*resultTopPairs); err != nil {
+		log.Fatalf("Invalid -result-top-pairs: %v", err)
+	}
+	if err := jobService.SetInputLimits(*maxResidues, *maxStructures); err != nil {
+		log.Fatalf("Invalid -max-residues/-max-structures: %v", err)
+	}
+	if err := jobService.SetJobTimeout(*jobTimeout); err != nil {
+		log.Fatalf("Invalid -job-timeout: %v", err)
+	}
+	jobService.SetKeepPDBFiles(*keepPDB)
+	if *defaultsFile != "" {
+		defaults, err := services.LoadAnalysisDefaults(*defaultsFile)
+		if err != nil {
+			log.Fatalf("Failed to read -defaults-file: %v", err)
+		}
+		if err := jobService.SetAnalysisDefaults(defaults); err != nil {
+			log.Fatalf("Invalid -defaults-file: %v", err)
+		}
+	}
+	if err := jobService.SetJobTTL(*jobTTL); err != nil {
+		log.Fatalf("Invalid -job-ttl: %v", err)
+	}
+	if err := jobService.SetIdempotencyGrace(*idempotencyGrace); err != nil {
+		log.Fatalf("Invalid -idempotency-grace: %v", err)
+	}
+	if err := jobService.RegisterMetrics(prometheus.DefaultRegisterer); err != nil {
+		log.Fatalf("Failed to register metrics: %v", err)
+	}
+	switch *storageBackend {
+	case "local":
+	case "s3":
+		// 成果物を S3 に置いてもサーバーは 1 台で動かす（待ち行列・キャンセル・バッチ・冪等キーはプロセスのメモリにある）
+		// 認証情報は AWS CLI/SDK と同じ環境変数から読む
+		storage, err := services.NewS3Storage(services.S3Config{
+			Bucket:          *s3Bucket,
+			Prefix:          *s3Prefix,
+			Region:          *s3Region,
+			Endpoint:        *s3Endpoint,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		})
+		if err != nil {
+			log.Fatalf("Invalid S3 storage settings: %v", err)
+		}
+		if err := jobService.SetStorage(storage); err != nil {
+			log.Fatalf("Failed to set storage: %v", err)
+		}
+	default:
+		log.Fatalf("Invalid -storage-backend: %s (want local or s3)", *storageBackend)
+	}
+	if err := jobService.SetDownloadRetries(*downloadRetries, *downloadRetryDelay); err != nil {
+		log.Fatalf("Invalid download retry settings: %v", err)
+	}
+
+	if err := jobService.SetPythonEnv(splitCommaList(*pythonEnv)); err != nil {
+		log.Fatalf("Invalid -python-env: %v", err)
+	}
+
+	// Python 環境の事前確認（flex_analyzer を import できるバイナリを選ぶ）
+	preflightCtx, cancelPreflight := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := jobService.ResolvePython(preflightCtx); err != nil {
+		log.Fatalf("Python preflight failed: %v (install the engine with `pip install -e python-engine` or point -python at an interpreter that has it)", err)
+	}
+	cancelPreflight()
 
 	// ハンドラー初期化
-	h := handlers.NewHandler(jobService)
+	h := handlers.NewHandler(jobService, logger)
+	if err := h.SetMaxUploadBytes(*maxUploadBytes); err != nil {
+		log.Fatalf("Invalid -max-upload-bytes: %v", err)
+	}
+	keys := splitCommaList(*apiKeys)
+	if *apiKeysFile != "" {
+		fileKeys, err := readAPIKeysFile(*apiKeysFile)
+		if err != nil {
+			log.Fatalf("Failed to read -api-keys-file: %v", err)
+		}
+		keys = append(keys, fileKeys...)
+	}
+	if err := h.SetAPIKeys(keys); err != nil {
+		log.Fatalf("Invalid API keys: %v", err)
+	}
+	if err := h.SetPriorityAPIKeys(splitCommaList(*priorityAPIKeys)); err != nil {
+		log.Fatalf("Invalid -priority-api-keys: %v", err)
+	}
+	if err := h.SetAnalyzeRateLimit(*analyzeRate, *analyzeBurst); err != nil {
+		log.Fatalf("Invalid -analyze-rate/-analyze-burst: %v", err)
+	}
+	if err := h.SetReadRateLimit(*readRate, *readBurst); err != nil {
+		log.Fatalf("Invalid -read-rate/-read-burst: %v", err)
+	}
 
 	// Ginルーター設定
 	router := gin.Default()
+	// 信頼するプロキシ以外からの X-Forwarded-For は無視する（偽装してレート制限を逃れられないように）
+	if err := router.SetTrustedProxies(splitCommaList(*trustedProxies)); err != nil {
+		log.Fatalf("Invalid -trusted-proxies %q: %v", *trustedProxies, err)
+	}
 
 	// CORS設定
 	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{"http://localhost:3000", "http://localhost:3001"}
 	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	config.AllowCredentials = true
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", handlers.RequestIDHeader, handlers.APIKeyHeader, handlers.IdempotencyKeyHeader}
+	config.ExposeHeaders = []string{handlers.RequestIDHeader, handlers.IdempotentReplayedHeader}
+	origins := splitCommaList(*corsOrigins)
+	if len(origins) == 1 && origins[0] == "*" {
+		// ブラウザは資格情報付きのワイルドカードを拒否するので credentials は無効にする
+		config.AllowAllOrigins = true
+		config.AllowCredentials = false
+	} else {
+		config.AllowOrigins = origins
+		config.AllowCredentials = true
+	}
+	if err := config.Validate(); err != nil {
+		log.Fatalf("Invalid -cors-origins %q: %v", *corsOrigins, err)
+	}
+	if err := h.SetSocketOrigins(origins); err != nil {
+		log.Fatalf("Invalid -cors-origins %q: %v", *corsOrigins, err)
+	}
 	router.Use(cors.New(config))
+	router.Use(handlers.RequestID())
 
-	// ルート設定
-	router.GET("/health", h.HealthCheck)
-
-	api := router.Group("/api/dsa")
-	{
-		api.POST("/analyze", h.CreateAnalysis)
-		api.GET("/status/:job_id", h.GetStatus)
-		api.GET("/result/:job_id", h.GetResult)
-		api.GET("/jobs/:job_id/heatmap", h.GetHeatmap)
-		api.GET("/jobs/:job_id/distance-score", h.GetDistanceScore)
-	}
+	h.RegisterRoutes(router)
 
 	// サーバー起動
 	addr := ":" + *port
 	log.Printf("Server starting on %s", addr)
 	log.Printf("Storage directory: %s", *storageDir)
-	log.Printf("Python binary: %s", *pythonBin)
+	if *storageBackend == "s3" {
+		log.Printf("Storage backend: s3://%s/%s", *s3Bucket, *s3Prefix)
+	}
+	log.Printf("Python binary: %s", jobService.PythonBin())
+	log.Printf("Python engine directory: %s", engineDir)
+	if len(keys) == 0 {
+		log.Printf("API key authentication is disabled: set -api-keys or -api-keys-file before exposing the server beyond localhost")
+	}
+
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// SIGINT/SIGTERM を受けたら新規リクエストの受付を止め、実行中のジョブを猶予期間まで待つ
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 期限切れジョブの定期削除（-job-ttl が 0 なら何もしない）
+	jobService.StartCleanup(ctx)
+
+	// /ready 用の flex_analyzer import 確認を定期的に更新
+	jobService.StartReadinessChecks(ctx)
+
+	<-ctx.Done()
+	stop()
+	log.Printf("Shutting down (grace period %s)", *shutdownGrace)
+
+	// リスナーはすぐ閉じる。SSE 接続はジョブが終了状態になった時点で閉じられる
+	httpCtx, cancelHTTP := context.WithTimeout(context.Background(), *shutdownGrace+shutdownHTTPExtra)
+	defer cancelHTTP()
+	httpDone := make(chan error, 1)
+	go func() {
+		httpDone <- srv.Shutdown(httpCtx)
+	}()
 
-	if err := router.Run(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	jobsCtx, cancelJobs := context.WithTimeout(context.Background(), *shutdownGrace)
+	defer cancelJobs()
+	if err := jobService.Shutdown(jobsCtx); err != nil {
+		log.Printf("Running jobs were interrupted after the grace period: %v", err)
+	}
+
+	if err := <-httpDone; err != nil {
+		log.Printf("HTTP server shutdown: %v", err)
+	}
+	log.Printf("Server stopped")
+}
+
+// defaultCORSOrigins は CORS_ORIGINS、未設定ならローカル開発用のフロントエンド
+func defaultCORSOrigins() string {
+	if origins := os.Getenv("CORS_ORIGINS"); origins != "" {
+		return origins
+	}
+	return "http://localhost:3000,http://localhost:3001"
+}
+
+// defaultS3Region は AWS_REGION、未設定なら us-east-1
+func defaultS3Region() string {
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		return region
+	}
+	return "us-east-1"
+}
+
+// splitCommaList はカンマ区切りの一覧を分割（空要素は除く）
+func splitCommaList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// readAPIKeysFile は1行1キーのファイルを読む（空行と # で始まる行は無視）
+func readAPIKeysFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			keys = append(keys, line)
+		}
 	}
+	return keys, nil
 }
@@ -0,0 +1,4152 @@
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/api/dsa/admin/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Cancel all jobs with a status",
+                "operationId": "cancelJobs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "processing"
+                        ],
+                        "type": "string",
+                        "description": "Status of the jobs to cancel",
+                        "name": "status",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cancelled job IDs",
+                        "schema": {
+                            "$ref": "#/definitions/models.CancelJobsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/admin/cleanup": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete finished jobs older than the TTL",
+                "operationId": "cleanupJobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Retention to apply instead of the server -job-ttl (e.g. 72h)",
+                        "name": "ttl",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Removed job IDs",
+                        "schema": {
+                            "$ref": "#/definitions/models.CleanupJobsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/admin/health-detailed": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Success rate and average duration of recent jobs, and jobs stuck past the timeout",
+                "operationId": "getHealthDetailed",
+                "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of most recent finished jobs to count",
+                        "name": "n",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Healthy",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Degraded: recent jobs mostly failed or a job is stuck",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthDetail"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/admin/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Running jobs with elapsed time, queue depth, worker capacity and storage usage",
+                "operationId": "getAdminStatus",
+                "responses": {
+                    "200": {
+                        "description": "Current state",
+                        "schema": {
+                            "$ref": "#/definitions/models.AdminStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/analyze": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Create one job per UniProt ID",
+                "operationId": "createAnalysis",
+                "parameters": [
+                    {
+                        "description": "Analysis parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AnalysisParams"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Validate and return the jobs that would be created (a DryRunResponse) without creating them",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "With dry_run, also look up the PDB entries for each UniProt ID (no downloads)",
+                        "name": "check_structures",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-chosen key; repeating the request with the same key and body returns the jobs created by the first request instead of starting new ones, until the grace period after they all finish (-idempotency-grace)",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Jobs created, or with dry_run=true a models.DryRunResponse",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobsResponse"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true when the jobs of an earlier request with the same Idempotency-Key were returned"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "priority=high is not allowed for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The first request with this Idempotency-Key is still creating its jobs",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large (-max-upload-bytes)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "The Idempotency-Key was already used with a different body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/analyze-batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Create one job per UniProt ID from an array",
+                "operationId": "createBatchAnalysis",
+                "parameters": [
+                    {
+                        "description": "uniprot_ids is an array; the other parameters apply to every job",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchAnalysisParams"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-chosen key; repeating the request with the same key and body returns the jobs created by the first request instead of starting new ones, until the grace period after they all finish (-idempotency-grace)",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Jobs created; jobs maps each uniprot_id to its job_id",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobsResponse"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true when the jobs of an earlier request with the same Idempotency-Key were returned"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "priority=high is not allowed for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The first request with this Idempotency-Key is still creating its jobs",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large (-max-upload-bytes)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "The Idempotency-Key was already used with a different body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/batches/{batch_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Status of every job in a batch",
+                "operationId": "getBatchJobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch ID returned by job creation",
+                        "name": "batch_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Job statuses in creation order",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchJobs"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/batches/{batch_id}/progress": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Progress of a batch",
+                "operationId": "getBatchProgress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch ID returned by job creation",
+                        "name": "batch_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchProgress"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/batches/{batch_id}/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Aggregate status of a batch",
+                "operationId": "getBatchStatus",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch ID returned by job creation",
+                        "name": "batch_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Counts per status and each job's status",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/defaults": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Defaults applied to unset analysis parameters",
+                "operationId": "getAnalysisDefaults",
+                "responses": {
+                    "200": {
+                        "description": "Current defaults, e.g. to prefill a form",
+                        "schema": {
+                            "$ref": "#/definitions/models.AnalysisDefaults"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List jobs, newest first",
+                "operationId": "listJobs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "processing",
+                            "completed",
+                            "failed",
+                            "cancelled",
+                            "interrupted"
+                        ],
+                        "type": "string",
+                        "description": "Only jobs with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only jobs for this UniProt ID (case-insensitive)",
+                        "name": "uniprot_id",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Page size (0 = all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One page of jobs",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobListPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Cancel a pending or running job",
+                "operationId": "cancelJob",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/models.CancelJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job already finished",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/cis": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "cis peptide statistics with residue names for each cis pair",
+                "operationId": "getCisInfo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "cis information",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobCisInfo"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/command": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Python CLI invocation that ran the job",
+                "operationId": "getJobCommand",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recorded command",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobCommand"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found or not run yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/distance-score": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Distance–score plot PNG",
+                "operationId": "getDistanceScore",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response; returns 304 when it still matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plot image",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "private, max-age=31536000, immutable for completed jobs, no-cache otherwise"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Derived from the file's modification time and size"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "private, max-age=31536000, immutable for completed jobs, no-cache otherwise"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Derived from the file's modification time and size"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plot not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/distance-score.json": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Distance–score plot points as JSON",
+                "operationId": "getDistanceScoreJSON",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10000,
+                        "description": "Evenly sample down to this many points in score order (use pair-scores for every pair)",
+                        "name": "max_points",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Points of the distance–score plot, built from the pair scores",
+                        "schema": {
+                            "$ref": "#/definitions/models.DistanceScorePoints"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Download all artifacts as a ZIP",
+                "operationId": "downloadJob",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ZIP archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Stream status changes as Server-Sent Events",
+                "operationId": "streamEvents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of status events carrying JobStatus JSON; closes on a terminal status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/files": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Output files of a job",
+                "operationId": "listJobFiles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Files sorted by name",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.JobFile"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/heatmap": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Heatmap PNG",
+                "operationId": "getHeatmap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response; returns 304 when it still matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Heatmap image",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "private, max-age=31536000, immutable for completed jobs, no-cache otherwise"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Derived from the file's modification time and size"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "private, max-age=31536000, immutable for completed jobs, no-cache otherwise"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Derived from the file's modification time and size"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Heatmap not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/heatmap.json": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Heatmap matrix as JSON (NaN as null)",
+                "operationId": "getHeatmapJSON",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Average N×N blocks into one cell (dense format only)",
+                        "name": "downsample",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "dense",
+                            "sparse"
+                        ],
+                        "type": "string",
+                        "default": "dense",
+                        "description": "sparse returns {i, j, value} cells (a models.SparseHeatmap) built from the pair scores without the N×N matrix, also for results whose dense heatmap was omitted",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Clamp values below this bound: a number, or pN for the N-th percentile of all cells before downsampling (default: the minimum)",
+                        "name": "vmin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Clamp values above this bound, same format as vmin (default: the maximum)",
+                        "name": "vmax",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "clamp",
+                            "normalize"
+                        ],
+                        "type": "string",
+                        "default": "clamp",
+                        "description": "normalize maps [vmin, vmax] to [0, 1] after clamping",
+                        "name": "scale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Heatmap matrix, or with format=sparse only the cells that have a value",
+                        "schema": {
+                            "$ref": "#/definitions/models.Heatmap"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/heatmap/thumbnail": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Heatmap thumbnail PNG (longest side 128px)",
+                "operationId": "getHeatmapThumbnail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response; returns 304 when it still matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Thumbnail image, generated on first request and cached as heatmap_thumb.png",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "private, max-age=31536000, immutable for completed jobs, no-cache otherwise"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Derived from the file's modification time and size"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "private, max-age=31536000, immutable for completed jobs, no-cache otherwise"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Derived from the file's modification time and size"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Heatmap not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Python stdout/stderr of the job",
+                "operationId": "getJobLogs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Only the last N lines",
+                        "name": "tail",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Captured output",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found or not run yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/pair-scores": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Page through pair scores, highest first",
+                "operationId": "getPairScores",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Pairs to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only pairs with score \u003e= min_score",
+                        "name": "min_score",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of pair scores",
+                        "schema": {
+                            "$ref": "#/definitions/models.PairScoresPage"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/per-residue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Per-residue scores for 3D coloring",
+                "operationId": "getPerResidueScores",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-residue scores",
+                        "schema": {
+                            "$ref": "#/definitions/models.PerResidueScores"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/result": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Full analysis result",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Return only the N highest pair scores (default: server -result-top-pairs, 0 = all)",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include all summary.csv columns as raw_summary",
+                        "name": "include_raw_summary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "For failed, cancelled or interrupted jobs, build the result from the CSV output left behind and mark it partial instead of returning 409",
+                        "name": "partial",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return the result in this older schema version (v1 or later; the fields added since are dropped). Unknown or future versions get 400",
+                        "name": "schema",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result; pair_scores is trimmed to the top scores (see pair_scores_total)",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotebookDSAResult"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found, or with partial=true no usable output was left",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/result.csv": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Export residue or pair scores as CSV",
+                "operationId": "getResultCSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "residues",
+                            "pairs"
+                        ],
+                        "type": "string",
+                        "default": "residues",
+                        "description": "Which table to export",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Re-run a finished job with the same parameters",
+                "operationId": "retryJob",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New job created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RetryJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid job_id, or the job is still running",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/storage": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Delete all files of a finished job",
+                "operationId": "deleteJobStorage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deleted",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteJobStorageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job is still running",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/structures": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "PDB entries considered for the job, with method, resolution and whether each was used",
+                "operationId": "getJobStructures",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Structures",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobStructures"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/summary": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Scalar result values only",
+                "operationId": "getResultSummary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result summary",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResultSummary"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/jobs/{job_id}/ws": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "WebSocket for status updates and job control",
+                "operationId": "jobSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Upgraded. The server sends JobSocketMessage frames, starting with the current status, and closes after a terminal status. The client may send JobSocketCommand frames ({\\\"action\\\":\\\"cancel\\\"})",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobSocketMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Wrong X-API-Key header. Browsers cannot send X-API-Key, so when the server has API keys they send {\\\"action\\\":\\\"auth\\\",\\\"token\\\":\\\"...\\\"} first (within 10 s); a wrong token gets an error frame and the connection is closed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The Origin header is not one of -cors-origins",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/result/{job_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Full analysis result",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Return only the N highest pair scores (default: server -result-top-pairs, 0 = all)",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include all summary.csv columns as raw_summary",
+                        "name": "include_raw_summary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "For failed, cancelled or interrupted jobs, build the result from the CSV output left behind and mark it partial instead of returning 409",
+                        "name": "partial",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return the result in this older schema version (v1 or later; the fields added since are dropped). Unknown or future versions get 400",
+                        "name": "schema",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result; pair_scores is trimmed to the top scores (see pair_scores_total)",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotebookDSAResult"
+                        }
+                    },
+                    "202": {
+                        "description": "Job has not completed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JobNotCompletedResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found, or with partial=true no usable output was left",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/status/{job_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Job status",
+                "operationId": "getStatus",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID or 13-character short job ID; other values get 400",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Current status",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid path or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dsa/validate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Validate an externally produced result.json",
+                "operationId": "validateResult",
+                "parameters": [
+                    {
+                        "description": "result.json to validate",
+                        "name": "result",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Validation report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to read the request body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid X-API-Key (only when the server has API keys configured)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Upload too large (-max-upload-bytes)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Per-client rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next request is allowed"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness check",
+                "operationId": "healthCheck",
+                "responses": {
+                    "200": {
+                        "description": "Server is up (status, queue_depth)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/health/load": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Load status for autoscaling",
+                "operationId": "healthLoad",
+                "responses": {
+                    "200": {
+                        "description": "Accepting work",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoadStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "Server-side failure",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Overloaded",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoadStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Prometheus metrics",
+                "operationId": "metrics",
+                "responses": {
+                    "200": {
+                        "description": "Metrics in Prometheus text format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/ready": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check (storage writable, Python engine importable)",
+                "operationId": "ready",
+                "responses": {
+                    "200": {
+                        "description": "Ready",
+                        "schema": {
+                            "$ref": "#/definitions/models.Readiness"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "$ref": "#/definitions/models.Readiness"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {
+                    "description": "コードごとの追加情報",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.JobNotCompletedResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {
+                    "description": "コードごとの追加情報",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "error": {
+                    "type": "string"
+                },
+                "estimated_remaining_seconds": {
+                    "description": "完了したジョブの平均実行時間から見積もった残り秒数（実績が無ければ省略）",
+                    "type": "integer"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "retry_after_seconds": {
+                    "description": "Retry-After ヘッダーと同じ値",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdminStatus": {
+            "type": "object",
+            "properties": {
+                "max_concurrency": {
+                    "type": "integer"
+                },
+                "queue_depth": {
+                    "description": "実行枠の空き待ちのジョブ数",
+                    "type": "integer"
+                },
+                "running_jobs": {
+                    "description": "開始が古い順",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RunningJob"
+                    }
+                },
+                "storage_free_bytes": {
+                    "type": "integer"
+                },
+                "storage_total_bytes": {
+                    "type": "integer"
+                },
+                "storage_used_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AnalysisDefaults": {
+            "type": "object",
+            "properties": {
+                "cis_threshold": {
+                    "type": "number"
+                },
+                "export": {
+                    "type": "boolean"
+                },
+                "heatmap": {
+                    "type": "boolean"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "overwrite": {
+                    "type": "boolean"
+                },
+                "proc_cis": {
+                    "type": "boolean"
+                },
+                "seq_ratio": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.AnalysisParams": {
+            "type": "object",
+            "required": [
+                "uniprot_ids"
+            ],
+            "properties": {
+                "callback_url": {
+                    "description": "ジョブ終了時に通知する http(s) URL",
+                    "type": "string"
+                },
+                "cis_threshold": {
+                    "description": "cis判定の距離閾値 (デフォルト: 3.3)",
+                    "type": "number"
+                },
+                "concurrency": {
+                    "description": "バッチ内の同時実行数 (デフォルト: サーバー上限)",
+                    "type": "integer"
+                },
+                "export": {
+                    "description": "CSV出力するか (デフォルト: true)",
+                    "type": "boolean"
+                },
+                "heatmap": {
+                    "description": "ヒートマップを生成するか (デフォルト: true)",
+                    "type": "boolean"
+                },
+                "method": {
+                    "description": "\"X-ray\", \"NMR\", \"EM\" (デフォルト: \"X-ray\")",
+                    "type": "string"
+                },
+                "negative_pdbid": {
+                    "description": "除外するPDB ID（スペースまたはカンマ区切り）",
+                    "type": "string"
+                },
+                "overwrite": {
+                    "description": "上書きするか (デフォルト: true)",
+                    "type": "boolean"
+                },
+                "pdb_ids": {
+                    "description": "解析するPDB ID（指定するとUniProtからの自動選択をしない。UniProt IDは1つのみ）",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "description": "実行枠を待つ順番: \"low\", \"normal\", \"high\" (デフォルト: \"normal\"。high は許可された API キーのみ)",
+                    "type": "string"
+                },
+                "proc_cis": {
+                    "description": "cis解析を行うか (デフォルト: true)",
+                    "type": "boolean"
+                },
+                "reference_offset": {
+                    "description": "構造が配列の途中から始まる場合に、残基番号（per_residue_scores の residue_number）を\nUniProt 上の位置に合わせるためのずれ（residue_number = index + 1 + reference_offset、デフォルト: 0）",
+                    "type": "integer"
+                },
+                "seq_ratio": {
+                    "description": "0.0-1.0 (デフォルト: 0.2)",
+                    "type": "number"
+                },
+                "uniprot_ids": {
+                    "description": "複数対応（カンマまたはスペース区切り）",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchAnalysisParams": {
+            "type": "object",
+            "required": [
+                "uniprot_ids"
+            ],
+            "properties": {
+                "callback_url": {
+                    "description": "ジョブ終了時に通知する http(s) URL",
+                    "type": "string"
+                },
+                "cis_threshold": {
+                    "description": "cis判定の距離閾値 (デフォルト: 3.3)",
+                    "type": "number"
+                },
+                "concurrency": {
+                    "description": "バッチ内の同時実行数 (デフォルト: サーバー上限)",
+                    "type": "integer"
+                },
+                "export": {
+                    "description": "CSV出力するか (デフォルト: true)",
+                    "type": "boolean"
+                },
+                "heatmap": {
+                    "description": "ヒートマップを生成するか (デフォルト: true)",
+                    "type": "boolean"
+                },
+                "method": {
+                    "description": "\"X-ray\", \"NMR\", \"EM\" (デフォルト: \"X-ray\")",
+                    "type": "string"
+                },
+                "negative_pdbid": {
+                    "description": "除外するPDB ID（スペースまたはカンマ区切り）",
+                    "type": "string"
+                },
+                "overwrite": {
+                    "description": "上書きするか (デフォルト: true)",
+                    "type": "boolean"
+                },
+                "pdb_ids": {
+                    "description": "解析するPDB ID（指定するとUniProtからの自動選択をしない。UniProt IDは1つのみ）",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "description": "実行枠を待つ順番: \"low\", \"normal\", \"high\" (デフォルト: \"normal\"。high は許可された API キーのみ)",
+                    "type": "string"
+                },
+                "proc_cis": {
+                    "description": "cis解析を行うか (デフォルト: true)",
+                    "type": "boolean"
+                },
+                "reference_offset": {
+                    "description": "構造が配列の途中から始まる場合に、残基番号（per_residue_scores の residue_number）を\nUniProt 上の位置に合わせるためのずれ（residue_number = index + 1 + reference_offset、デフォルト: 0）",
+                    "type": "integer"
+                },
+                "seq_ratio": {
+                    "description": "0.0-1.0 (デフォルト: 0.2)",
+                    "type": "number"
+                },
+                "uniprot_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.BatchJobStatus": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "終了状態になった日時（終了前は省略）",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "description": "以下は保存せず、ステータスを返すときに計算する",
+                    "type": "integer"
+                },
+                "failure_reason": {
+                    "description": "failed のときのみ。FailureReason は大分類（\"timeout\" | \"invalid_input\" | \"no_structures\" | \"engine_error\" | \"internal_error\"）、\nReason はより細かい識別子（\"no_suitable_structures\" など、判別できた場合のみ）",
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "queue_position": {
+                    "description": "実行枠の空き待ちの間のみ。1 なら次に実行される",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"pending\" | \"processing\" | \"completed\" | \"failed\" | \"cancelled\" | \"interrupted\"",
+                    "type": "string"
+                },
+                "uniprot_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchJobs": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchJobStatus"
+                    }
+                }
+            }
+        },
+        "models.BatchProgress": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "done": {
+                    "description": "終了したジョブ（削除済みを含む）",
+                    "type": "integer"
+                },
+                "queued": {
+                    "description": "まだ開始していない",
+                    "type": "integer"
+                },
+                "running": {
+                    "description": "processing",
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BatchStatus": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "cancelled": {
+                    "type": "integer"
+                },
+                "completed": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchJobStatus"
+                    }
+                },
+                "missing": {
+                    "description": "削除済みでステータスを読めないジョブ",
+                    "type": "integer"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "running": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CancelJobResponse": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "常に \"cancelled\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CancelJobsResponse": {
+            "type": "object",
+            "properties": {
+                "cancelled": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CisInfo": {
+            "type": "object",
+            "properties": {
+                "cis_dist_mean": {
+                    "type": "number"
+                },
+                "cis_dist_std": {
+                    "type": "number"
+                },
+                "cis_num": {
+                    "description": "全構造で常にcisのペア数",
+                    "type": "integer"
+                },
+                "cis_pairs": {
+                    "description": "[\"1, 2\", \"3, 4\", ...]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cis_score_mean": {
+                    "type": "number"
+                },
+                "mix": {
+                    "description": "cis/trans混在ペア数",
+                    "type": "integer"
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CisPair": {
+            "type": "object",
+            "properties": {
+                "i": {
+                    "description": "1-based",
+                    "type": "integer"
+                },
+                "j": {
+                    "description": "1-based",
+                    "type": "integer"
+                },
+                "residue_i": {
+                    "description": "3文字コード（例: \"PRO\"、trimsequence が無ければ省略）",
+                    "type": "string"
+                },
+                "residue_j": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CleanupJobsResponse": {
+            "type": "object",
+            "properties": {
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.DeleteJobStorageResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "boolean"
+                },
+                "job_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DistanceScorePoint": {
+            "type": "object",
+            "properties": {
+                "i": {
+                    "description": "1-based",
+                    "type": "integer"
+                },
+                "j": {
+                    "description": "1-based",
+                    "type": "integer"
+                },
+                "mean_distance": {
+                    "description": "x 軸: PairScore.DistanceMean",
+                    "type": "number"
+                },
+                "residue_pair": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "y 軸",
+                    "type": "number"
+                }
+            }
+        },
+        "models.DistanceScorePoints": {
+            "type": "object",
+            "properties": {
+                "downsampled": {
+                    "description": "max_points を超えたので間引いたか",
+                    "type": "boolean"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DistanceScorePoint"
+                    }
+                },
+                "total": {
+                    "description": "間引く前の点数（距離・スコアが NaN/Inf のペアは含まない）",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExcludedStructure": {
+            "type": "object",
+            "properties": {
+                "pdb_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "StructureDetail.Reason と同じ値",
+                    "type": "string"
+                }
+            }
+        },
+        "models.HealthDetail": {
+            "type": "object",
+            "properties": {
+                "average_duration_seconds": {
+                    "description": "completed のジョブの作成から完了までの平均。対象が無ければ null",
+                    "type": "number"
+                },
+                "cancelled": {
+                    "type": "integer"
+                },
+                "completed": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "interrupted を含む",
+                    "type": "integer"
+                },
+                "job_timeout_seconds": {
+                    "type": "number"
+                },
+                "jobs": {
+                    "description": "集計した終了済みジョブの件数（作成が新しい順）",
+                    "type": "integer"
+                },
+                "problems": {
+                    "description": "degraded の理由（ok なら空）",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "description": "\"ok\" | \"degraded\"",
+                    "type": "string"
+                },
+                "stuck_jobs": {
+                    "description": "制限時間を超えて processing のままのジョブ",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StuckJob"
+                    }
+                },
+                "success_rate": {
+                    "description": "completed / (completed + failed)。対象が無ければ null",
+                    "type": "number"
+                },
+                "window": {
+                    "description": "集計する終了済みジョブの件数の上限（?n=）",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Heatmap": {
+            "type": "object",
+            "properties": {
+                "scale": {
+                    "description": "\"clamp\" | \"normalize\"（0〜1 に写した）",
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "values": {
+                    "description": "NaN は null として表現（*float64 の nil）",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "vmax": {
+                    "type": "number"
+                },
+                "vmin": {
+                    "description": "heatmap.json で ?vmin= / ?vmax= / ?scale= を指定した場合のみ。Values はこの範囲に収めた値",
+                    "type": "number"
+                }
+            }
+        },
+        "models.JobCisInfo": {
+            "type": "object",
+            "properties": {
+                "cis_info": {
+                    "$ref": "#/definitions/models.CisInfo"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "pairs": {
+                    "description": "CisInfo.CisPairs を残基番号に分け、残基名を付けたもの",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CisPair"
+                    }
+                },
+                "uniprot_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobCommand": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "description": "nice/taskset を含む実際の argv",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dir": {
+                    "description": "作業ディレクトリ（絶対パス）",
+                    "type": "string"
+                },
+                "env": {
+                    "description": "実行結果に影響する環境変数のみ",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.JobFile": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "ジョブ配下の相対パス（\"/\" 区切り）",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "バイト数",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "拡張子から判定した Content-Type",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobListPage": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JobStatus"
+                    }
+                },
+                "next_cursor": {
+                    "description": "次のページの ?cursor=（最後のページでは省略）",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "uniprot_id": {
+                    "description": "バッチ作成時、このジョブが解析する UniProt ID",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobSocketMessage": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "type": {
+                    "description": "\"status\" | \"error\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobStatus": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "終了状態になった日時（終了前は省略）",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "description": "以下は保存せず、ステータスを返すときに計算する",
+                    "type": "integer"
+                },
+                "failure_reason": {
+                    "description": "failed のときのみ。FailureReason は大分類（\"timeout\" | \"invalid_input\" | \"no_structures\" | \"engine_error\" | \"internal_error\"）、\nReason はより細かい識別子（\"no_suitable_structures\" など、判別できた場合のみ）",
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "queue_position": {
+                    "description": "実行枠の空き待ちの間のみ。1 なら次に実行される",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"pending\" | \"processing\" | \"completed\" | \"failed\" | \"cancelled\" | \"interrupted\"",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobStructures": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "\"engine\"（Python の出力）| \"derived\"（古いジョブ: 解析に使ったエントリのみ推定）",
+                    "type": "string"
+                },
+                "structures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StructureDetail"
+                    }
+                },
+                "uniprot_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobsResponse": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JobResponse"
+                    }
+                }
+            }
+        },
+        "models.LoadStatus": {
+            "type": "object",
+            "properties": {
+                "accepting_new_jobs": {
+                    "type": "boolean"
+                },
+                "max_concurrency": {
+                    "type": "integer"
+                },
+                "queue_depth": {
+                    "type": "integer"
+                },
+                "running_jobs": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "\"ok\" | \"degraded\" | \"overloaded\"",
+                    "type": "string"
+                },
+                "storage_free_bytes": {
+                    "type": "integer"
+                },
+                "storage_total_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NotebookDSAResult": {
+            "type": "object",
+            "properties": {
+                "cis_info": {
+                    "description": "Cis 統計",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CisInfo"
+                        }
+                    ]
+                },
+                "excluded_pdbs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "excluded_structures": {
+                    "description": "除外した PDB エントリとその理由（ExcludedPDBs と同じ順。理由が分からないジョブでは reason を省略）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExcludedStructure"
+                    }
+                },
+                "full_sequence_length": {
+                    "description": "追加メタデータ",
+                    "type": "integer"
+                },
+                "heatmap": {
+                    "description": "ヒートマップ（N×N 行列）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Heatmap"
+                        }
+                    ]
+                },
+                "method": {
+                    "type": "string"
+                },
+                "num_chains": {
+                    "type": "integer"
+                },
+                "num_residues": {
+                    "type": "integer"
+                },
+                "num_structures": {
+                    "type": "integer"
+                },
+                "pair_score_mean": {
+                    "type": "number"
+                },
+                "pair_score_std": {
+                    "type": "number"
+                },
+                "pair_scores": {
+                    "description": "ペアごとの詳細",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PairScore"
+                    }
+                },
+                "pair_scores_total": {
+                    "description": "PairScores を Score 上位に絞った場合の元の件数（絞っていなければ省略）",
+                    "type": "integer"
+                },
+                "partial": {
+                    "description": "完了せずに終わったジョブの途中の出力から組み立てた結果か（?partial=true の場合のみ true）",
+                    "type": "boolean"
+                },
+                "pdb_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "per_residue_scores": {
+                    "description": "Per-residue スコア（3D 可視化用）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PerResidueScore"
+                    }
+                },
+                "raw_summary": {
+                    "description": "summary.csv の全列（ヘッダー→値、未パースの文字列のまま）\n?include_raw_summary=true の場合のみ含める",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "residue_coverage_percent": {
+                    "type": "number"
+                },
+                "schema_version": {
+                    "description": "result.json の形式のバージョン（導入前のファイルには無く 0 になる）",
+                    "type": "integer"
+                },
+                "seq_ratio": {
+                    "type": "number"
+                },
+                "top5_resolution_mean": {
+                    "description": "null 可能",
+                    "type": "number"
+                },
+                "umf": {
+                    "description": "グローバル指標",
+                    "type": "number"
+                },
+                "uniprot_id": {
+                    "description": "メタデータ",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PairScore": {
+            "type": "object",
+            "properties": {
+                "distance_mean": {
+                    "type": "number"
+                },
+                "distance_std": {
+                    "type": "number"
+                },
+                "i": {
+                    "description": "1-based",
+                    "type": "integer"
+                },
+                "j": {
+                    "description": "1-based",
+                    "type": "integer"
+                },
+                "residue_pair": {
+                    "description": "\"ALA-123, GLY-145\"",
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PairScoresPage": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "pair_scores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PairScore"
+                    }
+                },
+                "total": {
+                    "description": "min_score 適用後の総件数",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PerResidueScore": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "description": "0-based",
+                    "type": "integer"
+                },
+                "residue_name": {
+                    "type": "string"
+                },
+                "residue_number": {
+                    "description": "1-based (UniProt、reference_offset を含む)",
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PerResidueScores": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "per_residue_scores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PerResidueScore"
+                    }
+                },
+                "score_max": {
+                    "type": "number"
+                },
+                "score_min": {
+                    "description": "有限値のみで計算（該当なしは 0）",
+                    "type": "number"
+                }
+            }
+        },
+        "models.Readiness": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReadinessCheck"
+                    }
+                },
+                "ready": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ReadinessCheck": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "\"storage\" | \"python\"",
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ResultSummary": {
+            "type": "object",
+            "properties": {
+                "cis_mix": {
+                    "description": "cis/trans混在ペア数",
+                    "type": "integer"
+                },
+                "cis_num": {
+                    "description": "全構造で常にcisのペア数",
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "num_chains": {
+                    "type": "integer"
+                },
+                "num_residues": {
+                    "type": "integer"
+                },
+                "num_structures": {
+                    "type": "integer"
+                },
+                "pair_score_mean": {
+                    "type": "number"
+                },
+                "pair_score_std": {
+                    "type": "number"
+                },
+                "residue_coverage_percent": {
+                    "type": "number"
+                },
+                "seq_ratio": {
+                    "type": "number"
+                },
+                "umf": {
+                    "type": "number"
+                },
+                "uniprot_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RetryJobResponse": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "description": "新しいジョブ",
+                    "type": "string"
+                },
+                "retried_from": {
+                    "description": "再実行したジョブ",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RunningJob": {
+            "type": "object",
+            "properties": {
+                "elapsed_seconds": {
+                    "type": "number"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StructureDetail": {
+            "type": "object",
+            "properties": {
+                "chains_used": {
+                    "description": "seq_ratio の絞り込み後に解析に使ったチェーン数",
+                    "type": "integer"
+                },
+                "included": {
+                    "type": "boolean"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "mutation": {
+                    "description": "\"normal\" | \"substitution\" | \"chimera\" | \"delins\"",
+                    "type": "string"
+                },
+                "num_chains": {
+                    "description": "UniProt 配列に対応するチェーン数（不明なら 0）",
+                    "type": "integer"
+                },
+                "pdb_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "除外理由: \"negative_pdbid\" | \"error\" | \"chimera\" | \"delins\" | \"unclassified\" | \"seq_ratio\" | \"not_in_uniprot\"",
+                    "type": "string"
+                },
+                "resolution": {
+                    "description": "Å（NMR など値が無い・不明な場合は null）",
+                    "type": "number"
+                }
+            }
+        },
+        "models.StuckJob": {
+            "type": "object",
+            "properties": {
+                "elapsed_seconds": {
+                    "type": "number"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "orphaned": {
+                    "description": "このサーバーで実行していない（プロセスの異常終了などで取り残された）",
+                    "type": "boolean"
+                },
+                "started_at": {
+                    "description": "実行開始時刻（orphaned なら最後にステータスが更新された時刻）",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ValidationIssue": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "例: \"pair_scores[3]\"、JSON 全体の場合は \"\"",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ValidationReport": {
+            "type": "object",
+            "properties": {
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ValidationIssue"
+                    }
+                },
+                "truncated": {
+                    "description": "問題が多すぎて省略した場合 true",
+                    "type": "boolean"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKey": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        }
+    }
+}`
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0.0",
+	Host:             "",
+	BasePath:         "/",
+	Schemes:          []string{},
+	Title:            "Flex API",
+	Description:      "Notebook DSA analysis API. Routes under /api/dsa require X-API-Key when the server is started with -api-keys or -api-keys-file.",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// bindingErrorDetails は本文のバインドに失敗したエラーを ErrorResponse.Details にする
+// 項目ごとに分かる場合（binding タグの検証・型の不一致）は details.fields に JSON の項目名 → メッセージを入れ、
+// reason にはそれをつないだ文を入れる（項目が分からない JSON の構文エラーなどは reason のみ）
+// target はバインド先（JSON の項目名を struct タグから引くのに使う）
+func bindingErrorDetails(err error, target any) map[string]any {
+	fields := bindingFieldErrors(err, target)
+	if len(fields) == 0 {
+		return map[string]any{"reason": err.Error()}
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	messages := make([]string, len(names))
+	for i, name := range names {
+		messages[i] = fields[name]
+	}
+	return map[string]any{"reason": strings.Join(messages, "; "), "fields": fields}
+}
+
+// bindingFieldErrors は err を JSON の項目名 → メッセージにする（項目が分からなければ nil）
+func bindingFieldErrors(err error, target any) map[string]string {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make(map[string]string, len(validationErrs))
+		for _, fe := range validationErrs {
+			name := jsonFieldPath(reflect.TypeOf(target), fe.StructNamespace())
+			fields[name] = validationMessage(name, fe)
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return map[string]string{typeErr.Field: fmt.Sprintf("%s must be %s", typeErr.Field, jsonTypeName(typeErr.Type))}
+	}
+	return nil
+}
+
+// validationMessage は binding タグの検証に失敗した項目のメッセージ
+func validationMessage(name string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return name + " is required"
+	case "min", "gte":
+		return fmt.Sprintf("%s must be at least %s", name, fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("%s must be at most %s", name, fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", name, strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("%s is invalid (%s)", name, fe.Tag())
+	}
+}
+
+// jsonFieldPath は validator の StructNamespace（"AnalysisParams.UniProtIDs"）を JSON の項目名（"uniprot_ids"）にする
+// 埋め込み struct の項目は JSON と同じく親の階層に出し、タグが引けない項目は Go の名前のまま残す
+func jsonFieldPath(t reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 1 {
+		parts = parts[1:] // 先頭は型名
+	}
+
+	var path []string
+	for _, part := range parts {
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			path = append(path, part)
+			t = nil
+			continue
+		}
+		field, ok := t.FieldByName(part)
+		if !ok {
+			path = append(path, part)
+			t = nil
+			continue
+		}
+		t = field.Type
+		if field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			name = part
+		}
+		path = append(path, name)
+	}
+	return strings.Join(path, ".")
+}
+
+// jsonTypeName は Go の型を JSON の型の名前にする（型の不一致のメッセージ用）
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return "a " + t.String()
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/flex-api/internal/models"
+	"github.com/yourusername/flex-api/internal/services"
+)
+
+// ErrorResponse は全エンドポイント共通のエラー応答
+// Error は人が読むためのメッセージ（文言は変わりうる）、Code はクライアントが判別に使う値（変えない）
+type ErrorResponse struct {
+	Error   string         `json:"error"`
+	Code    string         `json:"code"`
+	Details map[string]any `json:"details,omitempty"` // コードごとの追加情報
+}
+
+// JobNotCompletedResponse は結果取得系が実行中のジョブに返す 202 の本文（Retry-After ヘッダーも付く）
+type JobNotCompletedResponse struct {
+	ErrorResponse
+	models.PollHint
+}
+
+// ErrorResponse.Code の値
+const (
+	CodeInvalidRequest           = "invalid_request"             // クエリ・本文の形式が不正
+	CodeInvalidParams            = "invalid_params"              // 解析パラメータの検証エラー（details に不正な ID）
+	CodeInvalidJobID             = "invalid_job_id"              // job_id の形式が不正
+	CodeInvalidCursor            = "invalid_cursor"              // ジョブ一覧の cursor が不正
+	CodeUnauthorized             = "unauthorized"                // API キーが無い・違う
+	CodePriorityNotAllowed       = "priority_not_allowed"        // priority=high を使えない API キー
+	CodeRateLimited              = "rate_limited"                // クライアントごとの上限を超えた（details.retry_after）
+	CodePayloadTooLarge          = "payload_too_large"           // アップロードが上限を超えた
+	CodeNotFound                 = "not_found"                   // ルートや成果物のファイルが無い
+	CodeJobNotFound              = "job_not_found"               // ジョブが存在しない
+	CodeBatchNotFound            = "batch_not_found"             // バッチが存在しない
+	CodeJobNotCompleted          = "job_not_completed"           // 実行中で結果がまだ無い（202）
+	CodeNoJobResult              = "no_job_result"               // 失敗・キャンセル・中断したので結果が無い（409、details.status）
+	CodeJobFinished              = "job_finished"                // 終了済みのジョブはキャンセルできない
+	CodeJobInProgress            = "job_in_progress"             // 実行中のジョブは削除・再実行できない
+	CodeNoPartialResult          = "no_partial_result"           // partial=true でも使える出力が残っていない
+	CodeNoJobLogs                = "no_job_logs"                 // ログがまだ無い
+	CodeNoJobCommand             = "no_job_command"              // 実行したコマンドがまだ記録されていない
+	CodeIdempotencyKeyReused     = "idempotency_key_reused"      // 同じ Idempotency-Key で別の内容が送られた
+	CodeIdempotencyKeyInProgress = "idempotency_key_in_progress" // 同じ Idempotency-Key の最初のリクエストが処理中
+	CodeInternal                 = "internal_error"              // サーバー側の失敗
+)
+
+// serviceErrorCodes は services のエラーと Code の対応（上から順に判定する）
+var serviceErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{services.ErrJobNotFound, CodeJobNotFound},
+	{services.ErrBatchNotFound, CodeBatchNotFound},
+	{services.ErrJobNotCompleted, CodeJobNotCompleted},
+	{services.ErrJobFinished, CodeJobFinished},
+	{services.ErrJobInProgress, CodeJobInProgress},
+	{services.ErrNoPartialResult, CodeNoPartialResult},
+	{services.ErrNoJobLogs, CodeNoJobLogs},
+	{services.ErrNoJobCommand, CodeNoJobCommand},
+	{services.ErrInvalidCursor, CodeInvalidCursor},
+	{services.ErrIdempotencyKeyReused, CodeIdempotencyKeyReused},
+	{services.ErrIdempotencyKeyInProgress, CodeIdempotencyKeyInProgress},
+}
+
+// respondError は status と共通の形のエラーを返す（以降のハンドラーは実行しない）
+func respondError(c *gin.Context, status int, code, message string) {
+	respondErrorDetails(c, status, code, message, nil)
+}
+
+// respondErrorDetails は details 付きのエラーを返す
+func respondErrorDetails(c *gin.Context, status int, code, message string, details map[string]any) {
+	c.AbortWithStatusJSON(status, ErrorResponse{Error: message, Code: code, Details: details})
+}
+
+// respondServiceError は services から返ったエラーを status で返す
+// Code はエラーの種類から決め、対応が無ければ status から決める
+func respondServiceError(c *gin.Context, status int, err error) {
+	respondError(c, status, serviceErrorCode(err, status), err.Error())
+}
+
+// serviceErrorCode は err に対応する Code を返す
+func serviceErrorCode(err error, status int) string {
+	for _, m := range serviceErrorCodes {
+		if errors.Is(err, m.err) {
+			return m.code
+		}
+	}
+	switch {
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status >= http.StatusInternalServerError:
+		return CodeInternal
+	default:
+		return CodeInvalidRequest
+	}
+}
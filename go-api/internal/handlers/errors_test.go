@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/flex-api/internal/services"
+)
+
+func TestErrorResponsesHaveCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(services.NewJobService(t.TempDir(), "python3", "", nil, nil), nil)
+	router := gin.New()
+	api := router.Group("/api/dsa", ValidateJobID())
+	api.POST("/analyze", h.CreateAnalysis)
+	api.GET("/status/:job_id", h.GetStatus)
+	api.GET("/jobs", h.ListJobs)
+
+	for _, tc := range []struct {
+		method, path, body string
+		wantStatus         int
+		wantCode           string
+		wantDetail         string
+	}{
+		{http.MethodGet, "/api/dsa/status/not-a-job", "", http.StatusBadRequest, CodeInvalidJobID, ""},
+		{http.MethodGet, "/api/dsa/status/" + testJobID, "", http.StatusNotFound, CodeJobNotFound, ""},
+		{http.MethodGet, "/api/dsa/jobs?limit=-1", "", http.StatusBadRequest, CodeInvalidRequest, ""},
+		{http.MethodGet, "/api/dsa/jobs?cursor=!!", "", http.StatusBadRequest, CodeInvalidCursor, ""},
+		{http.MethodPost, "/api/dsa/analyze", "{", http.StatusBadRequest, CodeInvalidRequest, "reason"},
+		{http.MethodPost, "/api/dsa/analyze", `{"uniprot_ids":"P12345 nope"}`, http.StatusBadRequest, CodeInvalidParams, "invalid_uniprot_ids"},
+		{http.MethodPost, "/api/dsa/analyze?dry_run=true", `{"uniprot_ids":"P12345 nope"}`, http.StatusBadRequest, CodeInvalidParams, "invalid_uniprot_ids"},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: %v", tc.method, tc.path, err)
+		}
+		if w.Code != tc.wantStatus || resp.Code != tc.wantCode || resp.Error == "" {
+			t.Errorf("%s %s: got %d %+v, want %d with code %s", tc.method, tc.path, w.Code, resp, tc.wantStatus, tc.wantCode)
+		}
+		if _, ok := resp.Details[tc.wantDetail]; tc.wantDetail != "" && !ok {
+			t.Errorf("%s %s: details %v should have %s", tc.method, tc.path, resp.Details, tc.wantDetail)
+		}
+	}
+}
+
+func TestBindingErrorsAreListedByField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(services.NewJobService(t.TempDir(), "python3", "", nil, nil), nil)
+	router := gin.New()
+	router.POST("/analyze", h.CreateAnalysis)
+	router.POST("/analyze-batch", h.CreateBatchAnalysis)
+
+	for _, tc := range []struct {
+		path, body  string
+		field, want string
+	}{
+		{"/analyze", `{}`, "uniprot_ids", "uniprot_ids is required"},
+		{"/analyze", `{"uniprot_ids":5}`, "uniprot_ids", "uniprot_ids must be a string"},
+		{"/analyze", `{"uniprot_ids":"P12345","seq_ratio":"high"}`, "seq_ratio", "seq_ratio must be a number"},
+		{"/analyze-batch", `{"uniprot_ids":"P12345"}`, "uniprot_ids", "uniprot_ids must be an array"},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body)))
+
+		var resp struct {
+			Code    string `json:"code"`
+			Details struct {
+				Reason string            `json:"reason"`
+				Fields map[string]string `json:"fields"`
+			} `json:"details"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: %v", tc.path, tc.body, err)
+		}
+		if w.Code != http.StatusBadRequest || resp.Code != CodeInvalidRequest {
+			t.Errorf("%s %s: got %d %s, want 400 %s", tc.path, tc.body, w.Code, resp.Code, CodeInvalidRequest)
+		}
+		if got := resp.Details.Fields[tc.field]; got != tc.want || resp.Details.Reason != tc.want {
+			t.Errorf("%s %s: got fields %v, reason %q, want %s: %q", tc.path, tc.body, resp.Details.Fields, resp.Details.Reason, tc.field, tc.want)
+		}
+	}
+}
+
+func TestHighPriorityRequiresAllowedAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(services.NewJobService(t.TempDir(), "python3", "", nil, nil), nil)
+	if err := h.SetPriorityAPIKeys([]string{"secret"}); err != nil {
+		t.Fatalf("SetPriorityAPIKeys: %v", err)
+	}
+	router := gin.New()
+	router.POST("/analyze", h.CreateAnalysis)
+
+	for _, tc := range []struct {
+		priority, key string
+		want          int
+	}{
+		{"high", "", http.StatusForbidden},
+		{"high", "other", http.StatusForbidden},
+		{"high", "secret", http.StatusOK},
+		{"low", "", http.StatusOK},
+	} {
+		body := `{"uniprot_ids":"P12345","priority":"` + tc.priority + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/analyze?dry_run=true", strings.NewReader(body))
+		if tc.key != "" {
+			req.Header.Set(APIKeyHeader, tc.key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s with key %q: got %d, want %d: %s", tc.priority, tc.key, w.Code, tc.want, w.Body.String())
+		}
+		if tc.want == http.StatusForbidden && !strings.Contains(w.Body.String(), CodePriorityNotAllowed) {
+			t.Errorf("%s with key %q: missing %s: %s", tc.priority, tc.key, CodePriorityNotAllowed, w.Body.String())
+		}
+	}
+}
+
+func TestOversizedRequestBodyIsRejected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(services.NewJobService(t.TempDir(), "python3", "", nil, nil), nil)
+	if err := h.SetMaxUploadBytes(64); err != nil {
+		t.Fatalf("SetMaxUploadBytes: %v", err)
+	}
+	router := gin.New()
+	router.POST("/analyze", h.CreateAnalysis)
+	router.POST("/analyze-batch", h.CreateBatchAnalysis)
+
+	for path, body := range map[string]string{
+		"/analyze":       `{"uniprot_ids":"` + strings.Repeat("P12345 ", 20) + `"}`,
+		"/analyze-batch": `{"uniprot_ids":["` + strings.Repeat("P12345", 20) + `"]}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, path+"?dry_run=true", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), CodePayloadTooLarge) {
+			t.Errorf("%s: got %d %s, want 413 %s", path, w.Code, w.Body.String(), CodePayloadTooLarge)
+		}
+	}
+}
@@ -3,12 +3,15 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
-	"log"
+	"io/fs"
+	"log/slog"
 	"net/http"
-	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/flex-api/internal/models"
@@ -16,240 +19,1817 @@ import (
 )
 
 type Handler struct {
-	jobService *services.JobService
+	jobService     *services.JobService
+	logger         *slog.Logger
+	maxUploadBytes int64
+	apiKeys        []string     // 空なら認証しない
+	priorityKeys   []string     // priority=high を使える API キー（空なら apiKeys のどれでも）
+	socketOrigins  []string     // WebSocket を開ける Origin（"*" はすべて、空ならサーバーと同じホストのみ）
+	analyzeLimiter *rateLimiter // nil なら無制限
+	readLimiter    *rateLimiter // nil なら無制限
 }
 
-func NewHandler(jobService *services.JobService) *Handler {
+// IdempotencyKeyHeader はジョブ作成の二重送信を防ぐキーを渡すヘッダー
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader は同じ Idempotency-Key の既存のジョブを返したことを示すヘッダー
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength は受け付ける Idempotency-Key の長さの上限
+const maxIdempotencyKeyLength = 255
+
+// defaultMaxUploadBytes はアップロードされるファイルのデフォルト上限（64 MiB）
+const defaultMaxUploadBytes int64 = 64 << 20
+
+func NewHandler(jobService *services.JobService, logger *slog.Logger) *Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
 	return &Handler{
-		jobService: jobService,
+		jobService:     jobService,
+		logger:         logger,
+		maxUploadBytes: defaultMaxUploadBytes,
+	}
+}
+
+// SetMaxUploadBytes はアップロードされるファイルのサイズ上限を設定
+func (h *Handler) SetMaxUploadBytes(n int64) error {
+	if n <= 0 {
+		return fmt.Errorf("max upload bytes must be positive: %d", n)
+	}
+	h.maxUploadBytes = n
+	return nil
+}
+
+// SetAPIKeys は X-API-Key として受け付けるキーを設定（空なら認証なしで公開）
+func (h *Handler) SetAPIKeys(keys []string) error {
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("API keys must not be empty")
+		}
+	}
+	h.apiKeys = keys
+	return nil
+}
+
+// SetSocketOrigins は WebSocket の接続を受け付ける Origin を設定（-cors-origins と同じ値、"*" はすべて）
+// 別サイトのページからブラウザの資格情報で接続される（Cross-Site WebSocket Hijacking）のを防ぐ
+func (h *Handler) SetSocketOrigins(origins []string) error {
+	for _, origin := range origins {
+		if origin == "" {
+			return fmt.Errorf("WebSocket origins must not be empty")
+		}
 	}
+	h.socketOrigins = origins
+	return nil
+}
+
+// SetPriorityAPIKeys は priority=high のジョブを作成できる API キーを設定
+// 空なら X-API-Key として受け付けるキーのどれでもよい（キーを設定していないサーバーでは high を使えない）
+func (h *Handler) SetPriorityAPIKeys(keys []string) error {
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("priority API keys must not be empty")
+		}
+	}
+	h.priorityKeys = keys
+	return nil
+}
+
+// checkPriority は priority=high を許可されていないリクエストを 403 で拒否する（拒否したら false）
+// 誰でも high にできると待ち行列の順番に意味が無くなるので、high は API キーで制限する
+func (h *Handler) checkPriority(c *gin.Context, params models.AnalysisParams) bool {
+	if params.Priority == nil || *params.Priority != models.PriorityHigh {
+		return true
+	}
+	keys := h.priorityKeys
+	if len(keys) == 0 {
+		keys = h.apiKeys
+	}
+	if len(keys) > 0 && matchAPIKey(c.GetHeader(APIKeyHeader), keys) {
+		return true
+	}
+	h.log(c).Warn("checkPriority: rejected high priority", "client_ip", c.ClientIP())
+	respondError(c, http.StatusForbidden, CodePriorityNotAllowed, "priority high requires an API key allowed to use it")
+	return false
+}
+
+// GetAnalysisDefaults は未指定のパラメータに入れる値を返す（フロントエンドのフォームの初期値用）
+// GET /api/dsa/defaults
+//
+//	@ID			getAnalysisDefaults
+//	@Summary	Defaults applied to unset analysis parameters
+//	@Tags		jobs
+//	@Produce	json
+//	@Success	200	{object}	models.AnalysisDefaults	"Current defaults, e.g. to prefill a form"
+//	@Failure	401	{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429	{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429	{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Security	ApiKey
+//	@Router		/api/dsa/defaults [get]
+func (h *Handler) GetAnalysisDefaults(c *gin.Context) {
+	c.JSON(http.StatusOK, h.jobService.AnalysisDefaults())
 }
 
 // CreateAnalysis は解析ジョブを作成
 // POST /api/dsa/analyze
+// ?dry_run=true ならジョブを作らずに検証だけ行い、&check_structures=true で PDB エントリも照会する
+//
+//	@ID			createAnalysis
+//	@Summary	Create one job per UniProt ID
+//	@Tags		jobs
+//	@Accept		json
+//	@Produce	json
+//	@Param		params				body		models.AnalysisParams	true	"Analysis parameters"
+//	@Param		dry_run				query		bool					false	"Validate and return the jobs that would be created (a DryRunResponse) without creating them"	default(false)
+//	@Param		check_structures	query		bool					false	"With dry_run, also look up the PDB entries for each UniProt ID (no downloads)"					default(false)
+//	@Param		Idempotency-Key		header		string					false	"Client-chosen key; repeating the request with the same key and body returns the jobs created by the first request instead of starting new ones, until the grace period after they all finish (-idempotency-grace)"
+//	@Success	200					{object}	models.JobsResponse		"Jobs created, or with dry_run=true a models.DryRunResponse"
+//	@Header		200					{string}	Idempotent-Replayed		"true when the jobs of an earlier request with the same Idempotency-Key were returned"
+//	@Failure	400					{object}	ErrorResponse			"Invalid parameters"
+//	@Failure	403					{object}	ErrorResponse			"priority=high is not allowed for this API key"
+//	@Failure	409					{object}	ErrorResponse			"The first request with this Idempotency-Key is still creating its jobs"
+//	@Failure	422					{object}	ErrorResponse			"The Idempotency-Key was already used with a different body"
+//	@Failure	413					{object}	ErrorResponse			"Request body too large (-max-upload-bytes)"
+//	@Failure	401					{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429					{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429					{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	500					{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/analyze [post]
 func (h *Handler) CreateAnalysis(c *gin.Context) {
 	// デバッグ: リクエストボディを読み取り
-	bodyBytes, err := io.ReadAll(c.Request.Body)
-	if err != nil {
-		log.Printf("[DEBUG] CreateAnalysis - Failed to read request body: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+	bodyBytes, ok := h.readBody(c)
+	if !ok {
 		return
 	}
 	
-	// リクエストボディをログ出力
-	log.Printf("[DEBUG] CreateAnalysis - Request body (raw): %s", string(bodyBytes))
+	// リクエストボディ（生データ）はデバッグレベルでのみ出力
+	h.log(c).Debug("CreateAnalysis: request body", "body", string(bodyBytes))
 	
 	// リクエストボディを再度設定（ShouldBindJSONで使用するため）
 	c.Request.Body = io.NopCloser(io.Reader(bytes.NewReader(bodyBytes)))
 	
-	// JSONをパースしてログ出力
-	var rawParams map[string]interface{}
-	if err := json.Unmarshal(bodyBytes, &rawParams); err == nil {
-		log.Printf("[DEBUG] CreateAnalysis - Parsed JSON: %+v", rawParams)
-	} else {
-		log.Printf("[DEBUG] CreateAnalysis - Failed to parse JSON: %v", err)
-	}
-	
 	var params models.AnalysisParams
 	if err := c.ShouldBindJSON(&params); err != nil {
-		log.Printf("[DEBUG] CreateAnalysis - Binding error: %v", err)
-		log.Printf("[DEBUG] CreateAnalysis - Binding error type: %T", err)
+		h.log(c).Debug("CreateAnalysis: binding error", "error", err, "error_type", fmt.Sprintf("%T", err))
 		
-		// エラーの詳細を取得
-		if validationErr, ok := err.(*gin.Error); ok {
-			log.Printf("[DEBUG] CreateAnalysis - Validation error details: %+v", validationErr)
+		respondErrorDetails(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body", bindingErrorDetails(err, params))
+		return
+	}
+
+	h.log(c).Debug("CreateAnalysis: parsed params", "params", params)
+
+	if !h.checkPriority(c, params) {
+		return
+	}
+
+	// ?dry_run=true なら検証結果と作成されるはずのジョブだけを返す（ジョブディレクトリは作らない）
+	if c.Query("dry_run") == "true" {
+		response, err := h.jobService.DryRun(c.Request.Context(), params, c.Query("check_structures") == "true")
+		if err != nil {
+			respondValidationError(c, err)
+			return
 		}
-		
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request body",
-			"details": err.Error(),
-		})
+		c.JSON(http.StatusOK, response)
 		return
 	}
 
-	// デバッグ: パースされたパラメータをログ出力
-	log.Printf("[DEBUG] CreateAnalysis - Parsed params:")
-	log.Printf("  UniProtIDs: %s", params.UniProtIDs)
-	if params.Method != nil {
-		log.Printf("  Method: %s", *params.Method)
-	} else {
-		log.Printf("  Method: nil")
+	// 複数のUniProt IDを分割してそれぞれ別のジョブとして作成
+	response, ok := h.createJobs(c, params)
+	if !ok {
+		return
 	}
-	if params.SeqRatio != nil {
-		log.Printf("  SeqRatio: %f", *params.SeqRatio)
-	} else {
-		log.Printf("  SeqRatio: nil")
+
+	h.log(c).Info("CreateAnalysis: jobs created", "batch_id", response.BatchID, "jobs", len(response.Jobs), "uniprot_ids", params.UniProtIDs)
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateBatchAnalysis は UniProt ID の配列を受け取り、1 ID につき 1 ジョブを同じバッチとして作成
+// 他のパラメータは /analyze と同じで全ジョブに共通。レスポンスの jobs に uniprot_id と job_id の対応を返す
+// POST /api/dsa/analyze-batch
+//
+//	@ID			createBatchAnalysis
+//	@Summary	Create one job per UniProt ID from an array
+//	@Tags		jobs
+//	@Accept		json
+//	@Produce	json
+//	@Param		params			body		models.BatchAnalysisParams	true	"uniprot_ids is an array; the other parameters apply to every job"
+//	@Param		Idempotency-Key	header		string						false	"Client-chosen key; repeating the request with the same key and body returns the jobs created by the first request instead of starting new ones, until the grace period after they all finish (-idempotency-grace)"
+//	@Success	200				{object}	models.JobsResponse			"Jobs created; jobs maps each uniprot_id to its job_id"
+//	@Header		200				{string}	Idempotent-Replayed			"true when the jobs of an earlier request with the same Idempotency-Key were returned"
+//	@Failure	400				{object}	ErrorResponse				"Invalid parameters"
+//	@Failure	403				{object}	ErrorResponse				"priority=high is not allowed for this API key"
+//	@Failure	409				{object}	ErrorResponse				"The first request with this Idempotency-Key is still creating its jobs"
+//	@Failure	422				{object}	ErrorResponse				"The Idempotency-Key was already used with a different body"
+//	@Failure	413				{object}	ErrorResponse				"Request body too large (-max-upload-bytes)"
+//	@Failure	401				{object}	ErrorResponse				"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429				{object}	ErrorResponse				"Per-client rate limit exceeded"
+//	@Header		429				{integer}	Retry-After					"Seconds until the next request is allowed"
+//	@Failure	500				{object}	ErrorResponse				"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/analyze-batch [post]
+func (h *Handler) CreateBatchAnalysis(c *gin.Context) {
+	// 埋め込んだ AnalysisParams の binding:"required" に引っかからないよう、gin のバインドを通さずに読む
+	body, ok := h.readBody(c)
+	if !ok {
+		return
 	}
-	if params.NegativePDBID != nil {
-		log.Printf("  NegativePDBID: %s", *params.NegativePDBID)
-	} else {
-		log.Printf("  NegativePDBID: nil")
+	var req models.BatchAnalysisParams
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&req); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body", bindingErrorDetails(err, req))
+		return
 	}
-	if params.CisThreshold != nil {
-		log.Printf("  CisThreshold: %f", *params.CisThreshold)
-	} else {
-		log.Printf("  CisThreshold: nil")
+	if len(req.UniProtIDs) == 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "uniprot_ids must be a non-empty array")
+		return
 	}
-	if params.Export != nil {
-		log.Printf("  Export: %t", *params.Export)
-	} else {
-		log.Printf("  Export: nil")
+
+	params := req.AnalysisParams
+	params.UniProtIDs = strings.Join(req.UniProtIDs, " ")
+	if !h.checkPriority(c, params) {
+		return
 	}
-	if params.Heatmap != nil {
-		log.Printf("  Heatmap: %t", *params.Heatmap)
-	} else {
-		log.Printf("  Heatmap: nil")
+
+	response, ok := h.createJobs(c, params)
+	if !ok {
+		return
 	}
-	if params.ProcCis != nil {
-		log.Printf("  ProcCis: %t", *params.ProcCis)
-	} else {
-		log.Printf("  ProcCis: nil")
+
+	h.log(c).Info("CreateBatchAnalysis: jobs created", "batch_id", response.BatchID, "jobs", len(response.Jobs), "uniprot_ids", params.UniProtIDs)
+	c.JSON(http.StatusOK, response)
+}
+
+// createJobs は CreateJobs でジョブを作成する（失敗したらエラーを返して false）
+// Idempotency-Key ヘッダーがあれば、同じクライアントが同じキーで送り直しても既存のジョブを返す
+func (h *Handler) createJobs(c *gin.Context, params models.AnalysisParams) (*models.JobsResponse, bool) {
+	key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+	if key == "" {
+		response, err := h.jobService.CreateJobs(c.Request.Context(), params)
+		if errors.Is(err, services.ErrInvalidParams) {
+			respondValidationError(c, err)
+			return nil, false
+		}
+		if err != nil {
+			h.log(c).Error("createJobs: CreateJobs failed", "error", err)
+			respondServiceError(c, http.StatusInternalServerError, err)
+			return nil, false
+		}
+		return response, true
 	}
-	if params.Overwrite != nil {
-		log.Printf("  Overwrite: %t", *params.Overwrite)
-	} else {
-		log.Printf("  Overwrite: nil")
+
+	if len(key) > maxIdempotencyKeyLength {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength))
+		return nil, false
+	}
+	response, replayed, err := h.jobService.CreateJobsIdempotent(c.Request.Context(), h.clientKey(c)+" "+key, params)
+	switch {
+	case errors.Is(err, services.ErrIdempotencyKeyReused):
+		respondServiceError(c, http.StatusUnprocessableEntity, err)
+		return nil, false
+	case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+		respondServiceError(c, http.StatusConflict, err)
+		return nil, false
+	case errors.Is(err, services.ErrInvalidParams):
+		respondValidationError(c, err)
+		return nil, false
+	case err != nil:
+		h.log(c).Error("createJobs: CreateJobsIdempotent failed", "error", err)
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return nil, false
 	}
+	if replayed {
+		c.Header(IdempotentReplayedHeader, "true")
+	}
+	return response, true
+}
 
-	// 複数のUniProt IDを分割してそれぞれ別のジョブとして作成
-	response, err := h.jobService.CreateJobs(params)
+// respondValidationError はパラメータ検証エラーを 400 で返す（不正な ID は details に一覧で返す）
+func respondValidationError(c *gin.Context, err error) {
+	var details map[string]any
+	var invalidIDs *models.InvalidUniProtIDsError
+	if errors.As(err, &invalidIDs) {
+		details = map[string]any{"invalid_uniprot_ids": invalidIDs.IDs}
+	}
+	var invalidPDBIDs *models.InvalidPDBIDsError
+	if errors.As(err, &invalidPDBIDs) {
+		if details == nil {
+			details = map[string]any{}
+		}
+		details["invalid_pdb_ids"] = invalidPDBIDs.IDs
+	}
+	respondErrorDetails(c, http.StatusBadRequest, CodeInvalidParams, err.Error(), details)
+}
+
+// ListJobs はジョブ一覧を作成日時の降順で取得
+// GET /api/dsa/jobs?status=completed&uniprot_id=P12345&limit=50&cursor=...
+// 続きはレスポンスの next_cursor を ?cursor= に渡して取得する
+//
+//	@ID			listJobs
+//	@Summary	List jobs, newest first
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		status		query		string				false	"Only jobs with this status"	Enums(pending, processing, completed, failed, cancelled, interrupted)
+//	@Param		uniprot_id	query		string				false	"Only jobs for this UniProt ID (case-insensitive)"
+//	@Param		limit		query		int					false	"Page size (0 = all)"	minimum(0)
+//	@Param		cursor		query		string				false	"next_cursor from the previous page"
+//	@Success	200			{object}	models.JobListPage	"One page of jobs"
+//	@Failure	400			{object}	ErrorResponse		"Invalid path or query parameter"
+//	@Failure	401			{object}	ErrorResponse		"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429			{object}	ErrorResponse		"Per-client rate limit exceeded"
+//	@Header		429			{integer}	Retry-After			"Seconds until the next request is allowed"
+//	@Failure	500			{object}	ErrorResponse		"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs [get]
+func (h *Handler) ListJobs(c *gin.Context) {
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 0 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+
+	page, err := h.jobService.ListJobsPage(services.JobListQuery{
+		Status:    c.Query("status"),
+		UniProtID: strings.TrimSpace(c.Query("uniprot_id")),
+		Cursor:    c.Query("cursor"),
+		Limit:     limit,
+	})
 	if err != nil {
-		log.Printf("[DEBUG] CreateAnalysis - CreateJobs error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrInvalidCursor) {
+			respondServiceError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	log.Printf("[DEBUG] CreateAnalysis - Jobs created successfully: %d jobs", len(response.Jobs))
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, page)
 }
 
 // GetStatus はジョブの状態を取得
 // GET /api/dsa/status/:job_id
+//
+//	@ID			getStatus
+//	@Summary	Job status
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		job_id	path		string				true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{object}	models.JobStatus	"Current status"
+//	@Failure	400		{object}	ErrorResponse		"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse		"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse		"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After			"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse		"Job not found"
+//	@Security	ApiKey
+//	@Router		/api/dsa/status/{job_id} [get]
 func (h *Handler) GetStatus(c *gin.Context) {
 	jobID := c.Param("job_id")
 	if jobID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "job_id is required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
 		return
 	}
 
 	status, err := h.jobService.GetJobStatus(jobID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondServiceError(c, http.StatusNotFound, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, status)
 }
 
+// GetBatchProgress はバッチの進捗（done/total）を取得
+// GET /api/dsa/batches/:batch_id/progress
+//
+//	@ID			getBatchProgress
+//	@Summary	Progress of a batch
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		batch_id	path		string					true	"Batch ID returned by job creation"
+//	@Success	200			{object}	models.BatchProgress	"Batch progress"
+//	@Failure	401			{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429			{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429			{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404			{object}	ErrorResponse			"Batch not found"
+//	@Failure	500			{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/batches/{batch_id}/progress [get]
+func (h *Handler) GetBatchProgress(c *gin.Context) {
+	progress, err := h.jobService.BatchProgress(c.Param("batch_id"))
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetBatchJobs はバッチ内の全ジョブのステータスを UniProt ID 付きで取得
+// GET /api/dsa/batches/:batch_id
+//
+//	@ID			getBatchJobs
+//	@Summary	Status of every job in a batch
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		batch_id	path		string				true	"Batch ID returned by job creation"
+//	@Success	200			{object}	models.BatchJobs	"Job statuses in creation order"
+//	@Failure	401			{object}	ErrorResponse		"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429			{object}	ErrorResponse		"Per-client rate limit exceeded"
+//	@Header		429			{integer}	Retry-After			"Seconds until the next request is allowed"
+//	@Failure	404			{object}	ErrorResponse		"Batch not found"
+//	@Failure	500			{object}	ErrorResponse		"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/batches/{batch_id} [get]
+func (h *Handler) GetBatchJobs(c *gin.Context) {
+	jobs, err := h.jobService.BatchJobs(c.Param("batch_id"))
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetBatchStatus はバッチ内のジョブのステータスを集計して取得（completed/failed/running などの件数とジョブごとの状態）
+// GET /api/dsa/batches/:batch_id/status
+//
+//	@ID			getBatchStatus
+//	@Summary	Aggregate status of a batch
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		batch_id	path		string				true	"Batch ID returned by job creation"
+//	@Success	200			{object}	models.BatchStatus	"Counts per status and each job's status"
+//	@Failure	401			{object}	ErrorResponse		"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429			{object}	ErrorResponse		"Per-client rate limit exceeded"
+//	@Header		429			{integer}	Retry-After			"Seconds until the next request is allowed"
+//	@Failure	404			{object}	ErrorResponse		"Batch not found"
+//	@Failure	500			{object}	ErrorResponse		"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/batches/{batch_id}/status [get]
+func (h *Handler) GetBatchStatus(c *gin.Context) {
+	status, err := h.jobService.BatchStatus(c.Param("batch_id"))
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// respondBatchError はバッチ取得時のエラーを、存在しなければ 404、それ以外は 500 で返す
+func respondBatchError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrBatchNotFound) {
+		respondServiceError(c, http.StatusNotFound, err)
+		return
+	}
+	respondServiceError(c, http.StatusInternalServerError, err)
+}
+
+// StreamEvents はジョブのステータス変更を Server-Sent Events で配信
+// 終了状態（completed/failed/cancelled）になったらストリームを閉じる
+// GET /api/dsa/jobs/:job_id/events
+//
+//	@ID			streamEvents
+//	@Summary	Stream status changes as Server-Sent Events
+//	@Tags		jobs
+//	@Produce	text/event-stream
+//	@Param		job_id	path		string			true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{string}	string			"Stream of status events carrying JobStatus JSON; closes on a terminal status"
+//	@Failure	400		{object}	ErrorResponse	"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse	"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse	"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After		"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse	"Job not found"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/events [get]
+func (h *Handler) StreamEvents(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	// 取りこぼしを防ぐため、現在のステータスを読む前に購読する
+	events, unsubscribe := h.jobService.Subscribe(jobID)
+	defer unsubscribe()
+
+	status, err := h.jobService.GetJobStatus(jobID)
+	if err != nil {
+		respondServiceError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	c.SSEvent("status", status)
+	c.Writer.Flush()
+	if services.IsTerminalStatus(status.Status) {
+		return
+	}
+
+	for {
+		select {
+		case st := <-events:
+			c.SSEvent("status", st)
+			c.Writer.Flush()
+			if services.IsTerminalStatus(st.Status) {
+				return
+			}
+		case <-c.Request.Context().Done():
+			// クライアント切断
+			return
+		}
+	}
+}
+
 // GetResult はジョブの結果を取得
-// GET /api/dsa/result/:job_id
+// pair_scores は Score 上位 -result-top-pairs 件に絞る（?top=N で上書き、?top=0 で全件）
+// ?partial=true なら失敗・キャンセルしたジョブでも残った CSV から組み立てる（partial: true 付き）
+// ?schema=v1 なら古いバージョンの形で返す（知らない・新しいバージョンは 400）
+// GET /api/dsa/result/:job_id?top=N、GET /api/dsa/jobs/:job_id/result
+//
+//	@Summary	Full analysis result
+//	@Tags		results
+//	@Produce	json
+//	@Param		job_id				path		string						true	"UUID or 13-character short job ID; other values get 400"
+//	@Param		top					query		int							false	"Return only the N highest pair scores (default: server -result-top-pairs, 0 = all)"	minimum(0)
+//	@Param		include_raw_summary	query		bool						false	"Include all summary.csv columns as raw_summary"
+//	@Param		partial				query		bool						false	"For failed, cancelled or interrupted jobs, build the result from the CSV output left behind and mark it partial instead of returning 409"	default(false)
+//	@Param		schema				query		string						false	"Return the result in this older schema version (v1 or later; the fields added since are dropped). Unknown or future versions get 400"
+//	@Success	200					{object}	models.NotebookDSAResult	"Result; pair_scores is trimmed to the top scores (see pair_scores_total)"
+//	@Success	202					{object}	JobNotCompletedResponse		"Job has not completed yet"
+//	@Header		202					{integer}	Retry-After					"Seconds to wait before polling again"
+//	@Failure	400					{object}	ErrorResponse				"Invalid path or query parameter"
+//	@Failure	401					{object}	ErrorResponse				"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429					{object}	ErrorResponse				"Per-client rate limit exceeded"
+//	@Header		429					{integer}	Retry-After					"Seconds until the next request is allowed"
+//	@Failure	404					{object}	ErrorResponse				"Job not found, or with partial=true no usable output was left"
+//	@Failure	409					{object}	ErrorResponse				"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500					{object}	ErrorResponse				"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/result/{job_id} [get]
+//	@Router		/api/dsa/jobs/{job_id}/result [get]
 func (h *Handler) GetResult(c *gin.Context) {
 	jobID := c.Param("job_id")
 	if jobID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "job_id is required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
 		return
 	}
 
-	result, err := h.jobService.GetResult(jobID)
+	schema := models.ResultSchemaVersion
+	if schemaStr := c.Query("schema"); schemaStr != "" {
+		v, err := services.ParseResultSchemaVersion(schemaStr)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+			return
+		}
+		schema = v
+	}
+
+	proj := services.ResultProjection{TopPairs: h.jobService.ResultTopPairs(), Partial: c.Query("partial") == "true"}
+	if topStr := c.Query("top"); topStr != "" {
+		n, err := strconv.Atoi(topStr)
+		if err != nil || n < 0 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "top must be a non-negative integer")
+			return
+		}
+		proj.TopPairs = n
+	}
+
+	result, err := h.jobService.GetProjectedResult(jobID, proj)
 	if err != nil {
-		// ジョブが未完了の場合
-		if err.Error() == "job not completed: pending" || err.Error() == "job not completed: processing" {
-			c.JSON(http.StatusAccepted, gin.H{"error": "Job not yet completed"})
+		h.respondResultError(c, err)
+		return
+	}
+
+	// summary.csv の生の値を含める（型付きモデルにない列も保持）
+	if c.Query("include_raw_summary") == "true" {
+		raw, err := h.jobService.GetRawSummary(jobID)
+		if err != nil {
+			respondServiceError(c, http.StatusNotFound, err)
+			return
+		}
+		result.RawSummary = raw
+	}
+
+	// ?schema= に古いバージョンを指定されたら、その形に戻して返す（ディスク上の結果はそのまま）
+	if schema != models.ResultSchemaVersion {
+		downgraded, err := services.DowngradeResult(result, schema)
+		if err != nil {
+			respondServiceError(c, http.StatusInternalServerError, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusOK, downgraded)
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
+// defaultPairScoresLimit は pair-scores の limit 未指定時の件数
+const defaultPairScoresLimit = 100
+
+// GetPairScores はペアスコアをページングして取得
+// GET /api/dsa/jobs/:job_id/pair-scores?offset=&limit=&min_score=
+//
+//	@ID			getPairScores
+//	@Summary	Page through pair scores, highest first
+//	@Tags		results
+//	@Produce	json
+//	@Param		job_id		path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Param		offset		query		int						false	"Pairs to skip"	minimum(0)	default(0)
+//	@Param		limit		query		int						false	"Page size"		minimum(1)	default(100)
+//	@Param		min_score	query		number					false	"Only pairs with score >= min_score"
+//	@Success	200			{object}	models.PairScoresPage	"Page of pair scores"
+//	@Success	202			{object}	JobNotCompletedResponse	"Job has not completed yet"
+//	@Header		202			{integer}	Retry-After				"Seconds to wait before polling again"
+//	@Failure	400			{object}	ErrorResponse			"Invalid path or query parameter"
+//	@Failure	401			{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429			{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429			{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404			{object}	ErrorResponse			"Job not found"
+//	@Failure	409			{object}	ErrorResponse			"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500			{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/pair-scores [get]
+func (h *Handler) GetPairScores(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		n, err := strconv.Atoi(offsetStr)
+		if err != nil || n < 0 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "offset must be a non-negative integer")
+			return
+		}
+		offset = n
+	}
+
+	limit := defaultPairScoresLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	var minScore *float64
+	if minStr := c.Query("min_score"); minStr != "" {
+		v, err := strconv.ParseFloat(minStr, 64)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "min_score must be a number")
+			return
+		}
+		minScore = &v
+	}
+
+	page, err := h.jobService.GetPairScores(jobID, offset, limit, minScore)
+	if err != nil {
+		h.respondResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetResultSummary は結果のスカラー値のみを取得（ジョブカード表示用）
+// GET /api/dsa/jobs/:job_id/summary
+//
+//	@ID			getResultSummary
+//	@Summary	Scalar result values only
+//	@Tags		results
+//	@Produce	json
+//	@Param		job_id	path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{object}	models.ResultSummary	"Result summary"
+//	@Success	202		{object}	JobNotCompletedResponse	"Job has not completed yet"
+//	@Header		202		{integer}	Retry-After				"Seconds to wait before polling again"
+//	@Failure	400		{object}	ErrorResponse			"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse			"Job not found"
+//	@Failure	409		{object}	ErrorResponse			"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500		{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/summary [get]
+func (h *Handler) GetResultSummary(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	summary, err := h.jobService.GetResultSummary(jobID)
+	if err != nil {
+		h.respondResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetPerResidueScores は残基ごとのスコアのみを取得（3D ビューアの色付け用）
+// GET /api/dsa/jobs/:job_id/per-residue
+//
+//	@ID			getPerResidueScores
+//	@Summary	Per-residue scores for 3D coloring
+//	@Tags		results
+//	@Produce	json
+//	@Param		job_id	path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{object}	models.PerResidueScores	"Per-residue scores"
+//	@Success	202		{object}	JobNotCompletedResponse	"Job has not completed yet"
+//	@Header		202		{integer}	Retry-After				"Seconds to wait before polling again"
+//	@Failure	400		{object}	ErrorResponse			"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse			"Job not found"
+//	@Failure	409		{object}	ErrorResponse			"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500		{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/per-residue [get]
+func (h *Handler) GetPerResidueScores(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	scores, err := h.jobService.GetPerResidueScores(jobID)
+	if err != nil {
+		h.respondResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scores)
+}
+
+// GetJobLogs は Python の標準出力/エラー出力を text/plain で返す（?tail=N で最後の N 行のみ）
+// GET /api/dsa/jobs/:job_id/logs
+//
+//	@ID			getJobLogs
+//	@Summary	Python stdout/stderr of the job
+//	@Tags		jobs
+//	@Produce	plain
+//	@Param		job_id	path		string			true	"UUID or 13-character short job ID; other values get 400"
+//	@Param		tail	query		int				false	"Only the last N lines"	minimum(1)
+//	@Success	200		{string}	string			"Captured output"
+//	@Failure	400		{object}	ErrorResponse	"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse	"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse	"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After		"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse	"Job not found or not run yet"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/logs [get]
+func (h *Handler) GetJobLogs(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	tail := 0
+	if tailStr := c.Query("tail"); tailStr != "" {
+		n, err := strconv.Atoi(tailStr)
+		if err != nil || n < 1 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "tail must be a positive integer")
+			return
+		}
+		tail = n
+	}
+
+	logs, err := h.jobService.GetJobLogs(jobID, tail)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrJobNotFound), errors.Is(err, services.ErrNoJobLogs):
+			respondServiceError(c, http.StatusNotFound, err)
+		default:
+			h.log(c).Error("GetJobLogs: failed to read logs", "job_id", jobID, "error", err)
+			respondServiceError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", logs)
+}
+
+// GetJobCommand はジョブを実行した Python CLI の呼び出し（argv・作業ディレクトリ・環境変数）を返す
+// GET /api/dsa/jobs/:job_id/command
+//
+//	@ID			getJobCommand
+//	@Summary	Python CLI invocation that ran the job
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		job_id	path		string				true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{object}	models.JobCommand	"Recorded command"
+//	@Failure	400		{object}	ErrorResponse		"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse		"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse		"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After			"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse		"Job not found or not run yet"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/command [get]
+func (h *Handler) GetJobCommand(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	cmd, err := h.jobService.GetJobCommand(jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrJobNotFound), errors.Is(err, services.ErrNoJobCommand):
+			respondServiceError(c, http.StatusNotFound, err)
+		default:
+			respondServiceError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, cmd)
+}
+
+// ListJobFiles はジョブの出力ファイルの一覧（名前・サイズ・種類）を返す
+// GET /api/dsa/jobs/:job_id/files
+//
+//	@ID			listJobFiles
+//	@Summary	Output files of a job
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		job_id	path		string			true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{array}		models.JobFile	"Files sorted by name"
+//	@Failure	400		{object}	ErrorResponse	"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse	"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse	"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After		"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse	"Job not found"
+//	@Failure	500		{object}	ErrorResponse	"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/files [get]
+func (h *Handler) ListJobFiles(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	files, err := h.jobService.ListJobFiles(jobID)
+	if err != nil {
+		if errors.Is(err, services.ErrJobNotFound) {
+			respondServiceError(c, http.StatusNotFound, err)
+			return
+		}
+		h.log(c).Error("ListJobFiles: failed to list files", "job_id", jobID, "error", err)
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, files)
+}
+
+// GetJobStructures は対象になった PDB エントリ（手法・分解能・チェーン数・採否）を取得
+// GET /api/dsa/jobs/:job_id/structures
+//
+//	@ID			getJobStructures
+//	@Summary	PDB entries considered for the job, with method, resolution and whether each was used
+//	@Tags		results
+//	@Produce	json
+//	@Param		job_id	path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{object}	models.JobStructures	"Structures"
+//	@Success	202		{object}	JobNotCompletedResponse	"Job has not completed yet"
+//	@Header		202		{integer}	Retry-After				"Seconds to wait before polling again"
+//	@Failure	400		{object}	ErrorResponse			"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse			"Job not found"
+//	@Failure	409		{object}	ErrorResponse			"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500		{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/structures [get]
+func (h *Handler) GetJobStructures(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	structures, err := h.jobService.GetJobStructures(jobID)
+	if err != nil {
+		h.respondResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, structures)
+}
+
+// GetCisInfo は cis ペプチド結合の統計と、cis ペアごとの残基名を返す
+// GET /api/dsa/jobs/:job_id/cis
+//
+//	@ID			getCisInfo
+//	@Summary	cis peptide statistics with residue names for each cis pair
+//	@Tags		results
+//	@Produce	json
+//	@Param		job_id	path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{object}	models.JobCisInfo		"cis information"
+//	@Success	202		{object}	JobNotCompletedResponse	"Job has not completed yet"
+//	@Header		202		{integer}	Retry-After				"Seconds to wait before polling again"
+//	@Failure	400		{object}	ErrorResponse			"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse			"Job not found"
+//	@Failure	409		{object}	ErrorResponse			"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500		{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/cis [get]
+func (h *Handler) GetCisInfo(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	cis, err := h.jobService.GetCisInfo(jobID)
+	if err != nil {
+		h.respondResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cis)
+}
+
+// GetHeatmapJSON はヒートマップの数値行列を JSON で取得（NaN は null）
+// ?format=sparse なら値のあるセルだけを {i, j, value} の列で返す（downsample とは併用できない）
+// ?vmin= / ?vmax=（値または p5 のようなパーセンタイル）を指定すると値をその範囲に収め、
+// ?scale=normalize なら 0〜1 に写す（再解析せずにコントラストを調整する用）
+// GET /api/dsa/jobs/:job_id/heatmap.json?downsample=N&format=dense|sparse&vmin=&vmax=&scale=clamp|normalize
+//
+//	@ID			getHeatmapJSON
+//	@Summary	Heatmap matrix as JSON (NaN as null)
+//	@Tags		results
+//	@Produce	json
+//	@Param		job_id		path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Param		downsample	query		int						false	"Average N×N blocks into one cell (dense format only)"																												minimum(1)
+//	@Param		format		query		string					false	"sparse returns {i, j, value} cells (a models.SparseHeatmap) built from the pair scores without the N×N matrix, also for results whose dense heatmap was omitted"	Enums(dense, sparse)	default(dense)
+//	@Param		vmin		query		string					false	"Clamp values below this bound: a number, or pN for the N-th percentile of all cells before downsampling (default: the minimum)"
+//	@Param		vmax		query		string					false	"Clamp values above this bound, same format as vmin (default: the maximum)"
+//	@Param		scale		query		string					false	"normalize maps [vmin, vmax] to [0, 1] after clamping"	Enums(clamp, normalize)	default(clamp)
+//	@Success	200			{object}	models.Heatmap			"Heatmap matrix, or with format=sparse only the cells that have a value"
+//	@Success	202			{object}	JobNotCompletedResponse	"Job has not completed yet"
+//	@Header		202			{integer}	Retry-After				"Seconds to wait before polling again"
+//	@Failure	400			{object}	ErrorResponse			"Invalid path or query parameter"
+//	@Failure	401			{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429			{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429			{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404			{object}	ErrorResponse			"Job not found"
+//	@Failure	409			{object}	ErrorResponse			"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500			{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/heatmap.json [get]
+func (h *Handler) GetHeatmapJSON(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	downsample := 0
+	if downsampleStr := c.Query("downsample"); downsampleStr != "" {
+		n, err := strconv.Atoi(downsampleStr)
+		if err != nil || n < 1 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "downsample must be a positive integer")
+			return
+		}
+		downsample = n
+	}
+
+	scale, err := parseHeatmapScale(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	switch c.DefaultQuery("format", "dense") {
+	case "dense":
+	case "sparse":
+		if downsample > 0 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "downsample is not supported with format=sparse")
+			return
+		}
+		h.getSparseHeatmap(c, jobID, scale)
+		return
+	default:
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "format must be dense or sparse")
+		return
+	}
+
+	heatmap, err := h.jobService.GetHeatmapValues(jobID, downsample, scale)
+	if err != nil {
+		h.respondHeatmapError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, heatmap)
+}
+
+// getSparseHeatmap は GetHeatmapJSON の ?format=sparse（値のあるセルのみ）
+func (h *Handler) getSparseHeatmap(c *gin.Context, jobID string, scale *services.HeatmapScale) {
+	heatmap, err := h.jobService.GetSparseHeatmap(jobID, scale)
+	if err != nil {
+		h.respondHeatmapError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, heatmap)
+}
+
+// respondHeatmapError は GetHeatmapJSON のエラーを返す
+func (h *Handler) respondHeatmapError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNoHeatmap):
+		respondServiceError(c, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrInvalidHeatmapScale):
+		respondServiceError(c, http.StatusBadRequest, err)
+	default:
+		h.respondResultError(c, err)
+	}
+}
+
+// parseHeatmapScale は ?vmin= / ?vmax= / ?scale= を解釈する（どれも無ければ nil）
+func parseHeatmapScale(c *gin.Context) (*services.HeatmapScale, error) {
+	vminStr, vmaxStr, scaleStr := c.Query("vmin"), c.Query("vmax"), c.Query("scale")
+	if vminStr == "" && vmaxStr == "" && scaleStr == "" {
+		return nil, nil
+	}
+
+	scale := &services.HeatmapScale{}
+	switch scaleStr {
+	case "", "clamp":
+	case "normalize":
+		scale.Normalize = true
+	default:
+		return nil, errors.New("scale must be clamp or normalize")
+	}
+	var err error
+	if scale.Min, err = optionalHeatmapBound(vminStr); err != nil {
+		return nil, err
+	}
+	if scale.Max, err = optionalHeatmapBound(vmaxStr); err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// optionalHeatmapBound は空なら nil（その端は全セルの最小値・最大値）を返す
+func optionalHeatmapBound(s string) (*services.HeatmapBound, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := services.ParseHeatmapBound(s)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// GetResultCSV は解析結果を CSV でエクスポート
+// GET /api/dsa/jobs/:job_id/result.csv?type=residues|pairs
+//
+//	@ID			getResultCSV
+//	@Summary	Export residue or pair scores as CSV
+//	@Tags		results
+//	@Produce	text/csv
+//	@Param		job_id	path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Param		type	query		string					false	"Which table to export"	Enums(residues, pairs)	default(residues)
+//	@Success	200		{string}	string					"CSV file"
+//	@Success	202		{object}	JobNotCompletedResponse	"Job has not completed yet"
+//	@Header		202		{integer}	Retry-After				"Seconds to wait before polling again"
+//	@Failure	400		{object}	ErrorResponse			"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse			"Job not found"
+//	@Failure	409		{object}	ErrorResponse			"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500		{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/result.csv [get]
+func (h *Handler) GetResultCSV(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	kind := c.DefaultQuery("type", services.ResultCSVResidues)
+	var artifact string
+	switch kind {
+	case services.ResultCSVResidues:
+		artifact = "per_residue_scores"
+	case services.ResultCSVPairs:
+		artifact = "pair_scores"
+	default:
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "type must be residues or pairs")
+		return
+	}
+
+	result, err := h.jobService.GetResult(jobID)
+	if err != nil {
+		h.respondResultError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, h.jobService.ArtifactFilename(jobID, artifact, "csv")))
+	c.Status(http.StatusOK)
+
+	if err := services.WriteResultCSV(c.Writer, result, kind); err != nil {
+		// ヘッダー送信後なのでステータスは変更できない
+		h.log(c).Error("GetResultCSV: failed to write CSV", "job_id", jobID, "error", err)
+	}
+}
+
+// respondResultError は結果取得時のエラーをステータスコードに振り分ける
+// 実行中（未終了）なら Retry-After 付きの 202、存在しない（途中の結果も無い）なら 404、それ以外は 500
+func (h *Handler) respondResultError(c *gin.Context, err error) {
+	var notCompleted *services.JobNotCompletedError
+	switch {
+	case errors.As(err, &notCompleted) && !services.IsTerminalStatus(notCompleted.Status):
+		status, statusErr := h.jobService.GetJobStatus(c.Param("job_id"))
+		if statusErr != nil {
+			// 直前まで存在していたので、読めなければエラーに含まれる状態だけで目安を出す
+			status = &models.JobStatus{JobID: c.Param("job_id"), Status: notCompleted.Status}
+		}
+		h.respondNotCompleted(c, status)
+	case errors.As(err, &notCompleted):
+		status, statusErr := h.jobService.GetJobStatus(c.Param("job_id"))
+		if statusErr != nil {
+			status = &models.JobStatus{JobID: c.Param("job_id"), Status: notCompleted.Status}
+		}
+		respondNoJobResult(c, status)
+	case errors.Is(err, services.ErrJobNotFound), errors.Is(err, services.ErrNoPartialResult):
+		respondServiceError(c, http.StatusNotFound, err)
+	default:
+		respondServiceError(c, http.StatusInternalServerError, err)
+	}
+}
+
+// respondNotCompleted は実行中のジョブについて、進捗と次に問い合わせるまでの目安を Retry-After 付きの 202 で返す
+func (h *Handler) respondNotCompleted(c *gin.Context, status *models.JobStatus) {
+	hint := h.jobService.PollHint(status)
+	c.Header("Retry-After", strconv.Itoa(hint.RetryAfterSeconds))
+	c.JSON(http.StatusAccepted, JobNotCompletedResponse{ErrorResponse{Error: "Job not yet completed", Code: CodeJobNotCompleted}, hint})
+}
+
+// respondNoJobResult は失敗・キャンセル・中断したジョブについて、終了状態と失敗の理由を 409 で返す（待っても結果はできない）
+func respondNoJobResult(c *gin.Context, status *models.JobStatus) {
+	message := "Job " + status.Status
+	if status.Message != "" {
+		message += ": " + status.Message
+	}
+	details := map[string]any{"status": status.Status}
+	if status.FailureReason != "" {
+		details["failure_reason"] = status.FailureReason
+	}
+	respondErrorDetails(c, http.StatusConflict, CodeNoJobResult, message, details)
+}
+
+// CancelJob は実行中のジョブをキャンセル
+// DELETE /api/dsa/jobs/:job_id
+//
+//	@ID			cancelJob
+//	@Summary	Cancel a pending or running job
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		job_id	path		string						true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{object}	models.CancelJobResponse	"Cancelled"
+//	@Failure	400		{object}	ErrorResponse				"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse				"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	404		{object}	ErrorResponse				"Job not found"
+//	@Failure	409		{object}	ErrorResponse				"Job already finished"
+//	@Failure	500		{object}	ErrorResponse				"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id} [delete]
+func (h *Handler) CancelJob(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	if err := h.jobService.CancelJob(jobID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrJobNotFound):
+			respondServiceError(c, http.StatusNotFound, err)
+		case errors.Is(err, services.ErrJobFinished):
+			respondServiceError(c, http.StatusConflict, err)
+		default:
+			respondServiceError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CancelJobResponse{JobID: jobID, Status: "cancelled"})
+}
+
+// DeleteJobStorage は終了済みジョブの全ファイルを直ちに削除
+// DELETE /api/dsa/jobs/:job_id/storage
+//
+//	@ID			deleteJobStorage
+//	@Summary	Delete all files of a finished job
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		job_id	path		string							true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{object}	models.DeleteJobStorageResponse	"Deleted"
+//	@Failure	400		{object}	ErrorResponse					"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse					"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	404		{object}	ErrorResponse					"Job not found"
+//	@Failure	409		{object}	ErrorResponse					"Job is still running"
+//	@Failure	500		{object}	ErrorResponse					"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/storage [delete]
+func (h *Handler) DeleteJobStorage(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	if err := h.jobService.DeleteJob(jobID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrJobNotFound):
+			respondServiceError(c, http.StatusNotFound, err)
+		case errors.Is(err, services.ErrJobInProgress):
+			respondServiceError(c, http.StatusConflict, err)
+		default:
+			respondServiceError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	h.log(c).Warn("audit: delete job", "client_ip", c.ClientIP(), "job_id", jobID)
+	c.JSON(http.StatusOK, models.DeleteJobStorageResponse{JobID: jobID, Deleted: true})
+}
+
+// RetryJob は終了したジョブを同じパラメータで再実行（新しい job_id を返す）
+// POST /api/dsa/jobs/:job_id/retry
+//
+//	@ID			retryJob
+//	@Summary	Re-run a finished job with the same parameters
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		job_id	path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{object}	models.RetryJobResponse	"New job created"
+//	@Failure	400		{object}	ErrorResponse			"Invalid job_id, or the job is still running"
+//	@Failure	401		{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse			"Job not found"
+//	@Failure	500		{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/retry [post]
+func (h *Handler) RetryJob(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	job, err := h.jobService.RetryJob(c.Request.Context(), jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrJobNotFound):
+			respondServiceError(c, http.StatusNotFound, err)
+		case errors.Is(err, services.ErrJobInProgress):
+			respondServiceError(c, http.StatusBadRequest, err)
+		default:
+			respondServiceError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RetryJobResponse{JobID: job.JobID, RetriedFrom: jobID, Status: job.Status})
+}
+
+// AdminStatus は実行中のジョブ・キュー長・実行枠・ストレージ使用量を返す（運用時の調査用）
+// GET /api/dsa/admin/status
+//
+//	@ID			getAdminStatus
+//	@Summary	Running jobs with elapsed time, queue depth, worker capacity and storage usage
+//	@Tags		admin
+//	@Produce	json
+//	@Success	200	{object}	models.AdminStatus	"Current state"
+//	@Failure	401	{object}	ErrorResponse		"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	500	{object}	ErrorResponse		"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/admin/status [get]
+func (h *Handler) AdminStatus(c *gin.Context) {
+	status, err := h.jobService.AdminStatus()
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// maxHealthWindow は HealthDetailed の ?n= の上限
+const maxHealthWindow = 1000
+
+// HealthDetailed は直近 n 件（?n=、デフォルト 50）の終了済みジョブの成功率・平均実行時間と、
+// 制限時間を超えて processing のままのジョブを返す（degraded なら 503）
+// GET /api/dsa/admin/health-detailed
+//
+//	@ID			getHealthDetailed
+//	@Summary	Success rate and average duration of recent jobs, and jobs stuck past the timeout
+//	@Tags		admin
+//	@Produce	json
+//	@Param		n	query		int					false	"Number of most recent finished jobs to count"	minimum(1)	maximum(1000)	default(50)
+//	@Success	200	{object}	models.HealthDetail	"Healthy"
+//	@Failure	400	{object}	ErrorResponse		"Invalid path or query parameter"
+//	@Failure	401	{object}	ErrorResponse		"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	503	{object}	models.HealthDetail	"Degraded: recent jobs mostly failed or a job is stuck"
+//	@Failure	500	{object}	ErrorResponse		"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/admin/health-detailed [get]
+func (h *Handler) HealthDetailed(c *gin.Context) {
+	n := services.DefaultHealthWindow
+	if nStr := c.Query("n"); nStr != "" {
+		v, err := strconv.Atoi(nStr)
+		if err != nil || v < 1 || v > maxHealthWindow {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("n must be an integer between 1 and %d", maxHealthWindow))
+			return
+		}
+		n = v
+	}
+
+	detail, err := h.jobService.HealthDetail(n)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	code := http.StatusOK
+	if detail.Status == "degraded" {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, detail)
+}
+
+// CancelJobs は指定ステータスに一致するジョブを一括キャンセル（緊急停止用）
+// POST /api/dsa/admin/cancel?status=processing
+//
+//	@ID			cancelJobs
+//	@Summary	Cancel all jobs with a status
+//	@Tags		admin
+//	@Produce	json
+//	@Param		status	query		string						true	"Status of the jobs to cancel"	Enums(pending, processing)
+//	@Success	200		{object}	models.CancelJobsResponse	"Cancelled job IDs"
+//	@Failure	400		{object}	ErrorResponse				"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse				"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	500		{object}	ErrorResponse				"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/admin/cancel [post]
+func (h *Handler) CancelJobs(c *gin.Context) {
+	status := c.Query("status")
+	if status != "pending" && status != "processing" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "status filter is required (pending or processing)")
+		return
+	}
+
+	cancelled, err := h.jobService.CancelJobs(status)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.log(c).Warn("audit: bulk cancel", "client_ip", c.ClientIP(), "status", status, "cancelled", len(cancelled), "job_ids", cancelled)
+	c.JSON(http.StatusOK, models.CancelJobsResponse{Cancelled: cancelled})
+}
+
+// CleanupJobs は期限切れの終了済みジョブを削除（?ttl= で保持期間を上書き）
+// POST /api/dsa/admin/cleanup
+//
+//	@ID			cleanupJobs
+//	@Summary	Delete finished jobs older than the TTL
+//	@Tags		admin
+//	@Produce	json
+//	@Param		ttl	query		string						false	"Retention to apply instead of the server -job-ttl (e.g. 72h)"
+//	@Success	200	{object}	models.CleanupJobsResponse	"Removed job IDs"
+//	@Failure	400	{object}	ErrorResponse				"Invalid path or query parameter"
+//	@Failure	401	{object}	ErrorResponse				"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	500	{object}	ErrorResponse				"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/admin/cleanup [post]
+func (h *Handler) CleanupJobs(c *gin.Context) {
+	ttl := h.jobService.JobTTL()
+	if ttlStr := c.Query("ttl"); ttlStr != "" {
+		d, err := time.ParseDuration(ttlStr)
+		if err != nil || d <= 0 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "ttl must be a positive duration (e.g. 72h)")
+			return
+		}
+		ttl = d
+	}
+	if ttl <= 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job TTL is not configured: pass ?ttl= or start the server with -job-ttl")
+		return
+	}
+
+	removed, err := h.jobService.CleanupExpired(ttl)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.log(c).Warn("audit: cleanup", "client_ip", c.ClientIP(), "ttl", ttl.String(), "removed", len(removed), "job_ids", removed)
+	c.JSON(http.StatusOK, models.CleanupJobsResponse{Removed: removed})
+}
+
+// ValidateResult は外部で生成された result.json をスキーマに照らして検証（ジョブは作成しない）
+// POST /api/dsa/validate
+//
+//	@ID			validateResult
+//	@Summary	Validate an externally produced result.json
+//	@Tags		results
+//	@Accept		json
+//	@Produce	json
+//	@Param		result	body		object					true	"result.json to validate"
+//	@Success	200		{object}	models.ValidationReport	"Validation report"
+//	@Failure	400		{object}	ErrorResponse			"Failed to read the request body"
+//	@Failure	413		{object}	ErrorResponse			"Upload too large (-max-upload-bytes)"
+//	@Failure	401		{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Security	ApiKey
+//	@Router		/api/dsa/validate [post]
+func (h *Handler) ValidateResult(c *gin.Context) {
+	body, ok := h.readBody(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, services.ValidateResult(body))
+}
+
+// readBody はリクエストボディを -max-upload-bytes まで読む
+// 上限を超えたら 413、読めなければ 400 を返して false
+func (h *Handler) readBody(c *gin.Context) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, fmt.Sprintf("Upload exceeds the maximum size of %d bytes", tooLarge.Limit))
+			return nil, false
+		}
+		h.log(c).Debug("readBody: failed to read request body", "error", err)
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Failed to read request body")
+		return nil, false
+	}
+	return body, true
+}
+
+// DownloadJob はジョブの全成果物を ZIP でストリーミング
+// 実行中のジョブは 202、失敗・キャンセル・中断したジョブは 409（no_job_result）
+// GET /api/dsa/jobs/:job_id/download
+//
+//	@ID			downloadJob
+//	@Summary	Download all artifacts as a ZIP
+//	@Tags		results
+//	@Produce	application/zip
+//	@Param		job_id	path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	200		{file}		binary					"ZIP archive"
+//	@Success	202		{object}	JobNotCompletedResponse	"Job has not completed yet"
+//	@Header		202		{integer}	Retry-After				"Seconds to wait before polling again"
+//	@Failure	400		{object}	ErrorResponse			"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse			"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429		{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Failure	404		{object}	ErrorResponse			"Job not found"
+//	@Failure	409		{object}	ErrorResponse			"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500		{object}	ErrorResponse			"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/download [get]
+func (h *Handler) DownloadJob(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	status, err := h.jobService.GetJobStatus(jobID)
+	if err != nil {
+		respondServiceError(c, http.StatusNotFound, err)
+		return
+	}
+	if !services.IsTerminalStatus(status.Status) {
+		h.respondNotCompleted(c, status)
+		return
+	}
+	if status.Status != "completed" {
+		respondNoJobResult(c, status)
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, h.jobService.ArtifactFilename(jobID, "artifacts", "zip")))
+	c.Status(http.StatusOK)
+
+	if err := h.jobService.WriteJobArchive(jobID, c.Writer); err != nil {
+		// ヘッダー送信後なのでステータスは変更できない
+		h.log(c).Error("DownloadJob: failed to stream archive", "job_id", jobID, "error", err)
+	}
+}
+
 // HealthCheck はヘルスチェック
 // GET /health
+//
+//	@ID			healthCheck
+//	@Summary	Liveness check
+//	@Tags		health
+//	@Produce	json
+//	@Success	200	{object}	map[string]any	"Server is up (status, queue_depth)"
+//	@Router		/health [get]
 func (h *Handler) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
-		"status": "ok",
-		"time":   gin.H{},
+		"status":      "ok",
+		"time":        gin.H{},
+		"queue_depth": h.jobService.QueueDepth(),
 	})
 }
 
+// Ready はレディネスチェック（ストレージ書き込み・Python エンジンを確認し、失敗時は 503）
+// GET /ready
+//
+//	@ID			ready
+//	@Summary	Readiness check (storage writable, Python engine importable)
+//	@Tags		health
+//	@Produce	json
+//	@Success	200	{object}	models.Readiness	"Ready"
+//	@Failure	503	{object}	models.Readiness	"Not ready"
+//	@Router		/ready [get]
+func (h *Handler) Ready(c *gin.Context) {
+	readiness := h.jobService.Readiness(c.Request.Context())
+	code := http.StatusOK
+	if !readiness.Ready {
+		code = http.StatusServiceUnavailable
+		h.log(c).Warn("Ready: not ready", "checks", readiness.Checks)
+	}
+	c.JSON(code, readiness)
+}
+
+// HealthLoad は負荷状況を返す（オートスケーリング用）
+// GET /health/load
+//
+//	@ID			healthLoad
+//	@Summary	Load status for autoscaling
+//	@Tags		health
+//	@Produce	json
+//	@Success	200	{object}	models.LoadStatus	"Accepting work"
+//	@Failure	503	{object}	models.LoadStatus	"Overloaded"
+//	@Failure	500	{object}	ErrorResponse		"Server-side failure"
+//	@Router		/health/load [get]
+func (h *Handler) HealthLoad(c *gin.Context) {
+	load, err := h.jobService.LoadStatus()
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	code := http.StatusOK
+	if load.Status == "overloaded" {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, load)
+}
+
 // GetHeatmap はジョブのヒートマップ PNG を返す
 // GET /api/dsa/jobs/:job_id/heatmap
+//
+//	@ID			getHeatmap
+//	@Summary	Heatmap PNG
+//	@Tags		results
+//	@Produce	png
+//	@Param		job_id			path	string	true	"UUID or 13-character short job ID; other values get 400"
+//	@Param		If-None-Match	header	string	false	"ETag from a previous response; returns 304 when it still matches"
+//	@Success	200				{file}	binary	"Heatmap image"
+//	@Success	304				"Not modified"
+//	@Header		200,304			{string}	ETag			"Derived from the file's modification time and size"
+//	@Header		200,304			{string}	Cache-Control	"private, max-age=31536000, immutable for completed jobs, no-cache otherwise"
+//	@Failure	400				{object}	ErrorResponse	"Invalid path or query parameter"
+//	@Failure	401				{object}	ErrorResponse	"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429				{object}	ErrorResponse	"Per-client rate limit exceeded"
+//	@Header		429				{integer}	Retry-After		"Seconds until the next request is allowed"
+//	@Failure	404				{object}	ErrorResponse	"Heatmap not found"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/heatmap [get]
 func (h *Handler) GetHeatmap(c *gin.Context) {
 	jobID := c.Param("job_id")
 	if jobID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "job_id is required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
 		return
 	}
 
-	jobDir := filepath.Join(h.jobService.StorageDir(), jobID)
-	
-	// Notebook DSAのヒートマップファイル名パターン: {uniprotid}_{seq_ratio}_heatmap.png
-	// まず、標準のheatmap.pngを確認
-	heatmapPath := filepath.Join(jobDir, "heatmap.png")
-	
-	// 標準のheatmap.pngが存在しない場合は、Notebook DSA形式を検索
-	if _, err := os.Stat(heatmapPath); err != nil {
-		// ディレクトリ内の_heatmap.pngファイルを検索
-		if entries, err := os.ReadDir(jobDir); err == nil {
-			for _, entry := range entries {
-				if !entry.IsDir() && strings.HasSuffix(entry.Name(), "_heatmap.png") {
-					heatmapPath = filepath.Join(jobDir, entry.Name())
-					log.Printf("[DEBUG] GetHeatmap - Found Notebook DSA heatmap: %s", entry.Name())
-					break
-				}
-			}
+	file, info, err := h.jobService.OpenHeatmapImage(jobID)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, services.ErrJobNotFound) {
+			respondError(c, http.StatusNotFound, CodeNotFound, "heatmap not found")
+			return
 		}
+		h.log(c).Error("GetHeatmap: failed to open heatmap", "job_id", jobID, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to stat heatmap")
+		return
 	}
+	defer file.Close()
+
+	setInlineFilename(c, h.jobService.ArtifactFilename(jobID, "heatmap", "png"))
+	h.serveImage(c, jobID, file, info)
+}
 
-	if _, err := os.Stat(heatmapPath); err != nil {
-		if os.IsNotExist(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "heatmap not found"})
+// GetHeatmapThumbnail はヒートマップの縮小版 PNG（長辺 128px）を返す（初回に生成して保存）
+// GET /api/dsa/jobs/:job_id/heatmap/thumbnail
+//
+//	@ID			getHeatmapThumbnail
+//	@Summary	Heatmap thumbnail PNG (longest side 128px)
+//	@Tags		results
+//	@Produce	png
+//	@Param		job_id			path	string	true	"UUID or 13-character short job ID; other values get 400"
+//	@Param		If-None-Match	header	string	false	"ETag from a previous response; returns 304 when it still matches"
+//	@Success	200				{file}	binary	"Thumbnail image, generated on first request and cached as heatmap_thumb.png"
+//	@Success	304				"Not modified"
+//	@Header		200,304			{string}	ETag			"Derived from the file's modification time and size"
+//	@Header		200,304			{string}	Cache-Control	"private, max-age=31536000, immutable for completed jobs, no-cache otherwise"
+//	@Failure	400				{object}	ErrorResponse	"Invalid path or query parameter"
+//	@Failure	401				{object}	ErrorResponse	"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429				{object}	ErrorResponse	"Per-client rate limit exceeded"
+//	@Header		429				{integer}	Retry-After		"Seconds until the next request is allowed"
+//	@Failure	404				{object}	ErrorResponse	"Heatmap not found"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/heatmap/thumbnail [get]
+func (h *Handler) GetHeatmapThumbnail(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	file, info, err := h.jobService.OpenHeatmapThumbnail(jobID)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, services.ErrJobNotFound) {
+			respondError(c, http.StatusNotFound, CodeNotFound, "heatmap not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to stat heatmap"})
+		h.log(c).Error("GetHeatmapThumbnail: failed to create thumbnail", "job_id", jobID, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to create thumbnail")
 		return
 	}
+	defer file.Close()
 
-	c.File(heatmapPath)
+	setInlineFilename(c, h.jobService.ArtifactFilename(jobID, "heatmap_thumb", "png"))
+	h.serveImage(c, jobID, file, info)
 }
 
 // GetDistanceScore は distance–score プロット PNG を返す
 // GET /api/dsa/jobs/:job_id/distance-score
+//
+//	@ID			getDistanceScore
+//	@Summary	Distance–score plot PNG
+//	@Tags		results
+//	@Produce	png
+//	@Param		job_id			path	string	true	"UUID or 13-character short job ID; other values get 400"
+//	@Param		If-None-Match	header	string	false	"ETag from a previous response; returns 304 when it still matches"
+//	@Success	200				{file}	binary	"Plot image"
+//	@Success	304				"Not modified"
+//	@Header		200,304			{string}	ETag			"Derived from the file's modification time and size"
+//	@Header		200,304			{string}	Cache-Control	"private, max-age=31536000, immutable for completed jobs, no-cache otherwise"
+//	@Failure	400				{object}	ErrorResponse	"Invalid path or query parameter"
+//	@Failure	401				{object}	ErrorResponse	"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429				{object}	ErrorResponse	"Per-client rate limit exceeded"
+//	@Header		429				{integer}	Retry-After		"Seconds until the next request is allowed"
+//	@Failure	404				{object}	ErrorResponse	"Plot not found"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/distance-score [get]
 func (h *Handler) GetDistanceScore(c *gin.Context) {
 	jobID := c.Param("job_id")
 	if jobID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "job_id is required"})
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
 		return
 	}
 
-	jobDir := filepath.Join(h.jobService.StorageDir(), jobID)
-	
-	// まず、標準のdistance_score.pngを確認
-	pngPath := filepath.Join(jobDir, "distance_score.png")
-	
-	// 標準のdistance_score.pngが存在しない場合は、Notebook DSA形式を検索
-	if _, err := os.Stat(pngPath); err != nil {
-		// ディレクトリ内のdistance_score.pngファイルを検索
-		if entries, err := os.ReadDir(jobDir); err == nil {
-			for _, entry := range entries {
-				if !entry.IsDir() && entry.Name() == "distance_score.png" {
-					pngPath = filepath.Join(jobDir, entry.Name())
-					log.Printf("[DEBUG] GetDistanceScore - Found distance_score.png: %s", entry.Name())
-					break
-				}
-			}
+	file, info, err := h.jobService.OpenDistanceScorePlot(jobID)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, services.ErrJobNotFound) {
+			respondError(c, http.StatusNotFound, CodeNotFound, "distance_score.png not found")
+			return
 		}
+		h.log(c).Error("GetDistanceScore: failed to open distance_score.png", "job_id", jobID, "error", err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "failed to stat distance_score.png")
+		return
 	}
+	defer file.Close()
+
+	setInlineFilename(c, h.jobService.ArtifactFilename(jobID, "distance_score", "png"))
+	h.serveImage(c, jobID, file, info)
+}
+
+// defaultDistanceScoreMaxPoints は distance-score.json の max_points 未指定時の上限
+const defaultDistanceScoreMaxPoints = 10000
 
-	if _, err := os.Stat(pngPath); err != nil {
-		if os.IsNotExist(err) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "distance_score.png not found"})
+// GetDistanceScoreJSON は distance–score プロットの点（平均距離とスコア）を JSON で返す
+// 点が max_points を超える場合は間引く（全ペアが必要なら pair-scores を使う）
+// GET /api/dsa/jobs/:job_id/distance-score.json?max_points=N
+//
+//	@ID			getDistanceScoreJSON
+//	@Summary	Distance–score plot points as JSON
+//	@Tags		results
+//	@Produce	json
+//	@Param		job_id		path		string						true	"UUID or 13-character short job ID; other values get 400"
+//	@Param		max_points	query		int							false	"Evenly sample down to this many points in score order (use pair-scores for every pair)"	minimum(1)	default(10000)
+//	@Success	200			{object}	models.DistanceScorePoints	"Points of the distance–score plot, built from the pair scores"
+//	@Success	202			{object}	JobNotCompletedResponse		"Job has not completed yet"
+//	@Header		202			{integer}	Retry-After					"Seconds to wait before polling again"
+//	@Failure	400			{object}	ErrorResponse				"Invalid path or query parameter"
+//	@Failure	401			{object}	ErrorResponse				"Missing or invalid X-API-Key (only when the server has API keys configured)"
+//	@Failure	429			{object}	ErrorResponse				"Per-client rate limit exceeded"
+//	@Header		429			{integer}	Retry-After					"Seconds until the next request is allowed"
+//	@Failure	404			{object}	ErrorResponse				"Job not found"
+//	@Failure	409			{object}	ErrorResponse				"Job failed, was cancelled or was interrupted, so it has no result; details.status holds the terminal status"
+//	@Failure	500			{object}	ErrorResponse				"Server-side failure"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/distance-score.json [get]
+func (h *Handler) GetDistanceScoreJSON(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	maxPoints := defaultDistanceScoreMaxPoints
+	if maxStr := c.Query("max_points"); maxStr != "" {
+		n, err := strconv.Atoi(maxStr)
+		if err != nil || n < 1 {
+			respondError(c, http.StatusBadRequest, CodeInvalidRequest, "max_points must be a positive integer")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to stat distance_score.png"})
+		maxPoints = n
+	}
+
+	points, err := h.jobService.GetDistanceScorePoints(jobID, maxPoints)
+	if err != nil {
+		h.respondResultError(c, err)
 		return
 	}
 
-	c.File(pngPath)
+	c.JSON(http.StatusOK, points)
+}
+
+// imageCacheMaxAge は完了済みジョブの画像をブラウザにキャッシュさせる秒数
+const imageCacheMaxAge = 365 * 24 * 60 * 60
+
+// serveImage は PNG をキャッシュ用ヘッダー付きで返す
+// ETag は更新日時とサイズから作り、If-None-Match が一致すれば 304 を返す
+// 完了済みジョブの画像は変わらないので immutable、それ以外は毎回 ETag で再検証させる
+func (h *Handler) serveImage(c *gin.Context, jobID string, file io.Reader, info services.FileInfo) {
+	etag := fmt.Sprintf(`"%x-%x"`, info.ModTime.UnixNano(), info.Size)
+	c.Header("ETag", etag)
+	if status, err := h.jobService.GetJobStatus(jobID); err == nil && status.Status == "completed" {
+		c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d, immutable", imageCacheMaxAge))
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.DataFromReader(http.StatusOK, info.Size, "image/png", file, nil)
+}
+
+// etagMatches は If-None-Match ヘッダーが etag を含むか判定する（"*" と弱い比較に対応）
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// setInlineFilename はブラウザ表示を保ったまま保存時のファイル名を指定
+func setInlineFilename(c *gin.Context, filename string) {
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
 }
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/flex-api/internal/models"
+	"github.com/yourusername/flex-api/internal/services"
+)
+
+const testJobID = "11111111-2222-3333-4444-555555555555"
+
+// newTestRouter は storageDir/{testJobID} に status と files を置いた状態のルーターを作る（API キーは "secret"、WebSocket の Origin は http://localhost:3000）
+func newTestRouter(t *testing.T, status string, files map[string]string) *gin.Engine {
+	t.Helper()
+	dir := t.TempDir()
+	jobDir := filepath.Join(dir, testJobID)
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files["status.json"] = `{"job_id":"` + testJobID + `","status":"` + status + `","progress":0,"message":""}`
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(jobDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(services.NewJobService(dir, "python3", "", nil, nil), nil)
+	if err := h.SetAPIKeys([]string{"secret"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetSocketOrigins([]string{"http://localhost:3000"}); err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	router.GET("/jobs/:job_id/heatmap", h.GetHeatmap)
+	router.GET("/jobs/:job_id/distance-score", h.GetDistanceScore)
+	router.GET("/jobs/:job_id/result", h.GetResult)
+	router.GET("/jobs/:job_id/download", h.DownloadJob)
+	router.GET("/jobs/:job_id/ws", h.JobSocket)
+	router.GET("/admin/health-detailed", h.HealthDetailed)
+	return router
+}
+
+func TestImageCachingHeaders(t *testing.T) {
+	router := newTestRouter(t, "completed", map[string]string{
+		"P12345_0.2_heatmap.png": "heatmap",
+		"distance_score.png":     "plot",
+	})
+
+	for _, path := range []string{"/jobs/" + testJobID + "/heatmap", "/jobs/" + testJobID + "/distance-score"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: got %d, want 200", path, w.Code)
+		}
+		etag := w.Header().Get("ETag")
+		if etag == "" || !strings.Contains(w.Header().Get("Cache-Control"), "immutable") {
+			t.Errorf("%s: missing cache headers: %v", path, w.Header())
+		}
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", `"other", W/`+etag)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Errorf("%s: got %d with %d bytes, want empty 304", path, w.Code, w.Body.Len())
+		}
+	}
+}
+
+func TestImageOfRunningJob(t *testing.T) {
+	router := newTestRouter(t, "processing", map[string]string{"distance_score.png": "partial"})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jobs/"+testJobID+"/distance-score", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("got %d with Cache-Control %q, want 200 no-cache", w.Code, w.Header().Get("Cache-Control"))
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jobs/"+testJobID+"/heatmap", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing heatmap: got %d, want 404", w.Code)
+	}
+}
+
+func TestResultOfRunningJobHasRetryAfter(t *testing.T) {
+	router := newTestRouter(t, "processing", map[string]string{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jobs/"+testJobID+"/result", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("got %d, want 202", w.Code)
+	}
+	// 実行時間の実績が無いので固定の間隔
+	if got := w.Header().Get("Retry-After"); got != "5" {
+		t.Errorf("Retry-After: got %q, want 5", got)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `"retry_after_seconds":5`) || !strings.Contains(body, `"status":"processing"`) || strings.Contains(body, "estimated_remaining_seconds") {
+		t.Errorf("unexpected body: %s", body)
+	}
+}
+
+func TestFinishedJobWithoutResultIsConflict(t *testing.T) {
+	for _, status := range []string{"failed", "cancelled", "interrupted"} {
+		router := newTestRouter(t, status, map[string]string{})
+		for _, path := range []string{"/jobs/" + testJobID + "/download", "/jobs/" + testJobID + "/result"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			var body ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("%s %s: %v", status, path, err)
+			}
+			// 待っても結果はできないので 202 で再試行させない
+			if w.Code != http.StatusConflict || body.Code != CodeNoJobResult || body.Details["status"] != status {
+				t.Errorf("%s %s: got %d %+v, want 409 %s with details.status", status, path, w.Code, body, CodeNoJobResult)
+			}
+		}
+	}
+}
+
+func TestResultSchemaDowngrade(t *testing.T) {
+	router := newTestRouter(t, "completed", map[string]string{
+		"result.json": `{"schema_version":2,"uniprot_id":"P12345","excluded_pdbs":["2B00"],"excluded_structures":[{"pdb_id":"2B00"}]}`,
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jobs/"+testJobID+"/result?schema=v1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("schema=v1: got %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"schema_version":1`) || strings.Contains(body, "excluded_structures") {
+		t.Errorf("schema=v1: unexpected body: %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jobs/"+testJobID+"/result?schema=v99", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("schema=v99: got %d, want 400", w.Code)
+	}
+}
+
+func TestHealthDetailedReportsStuckJob(t *testing.T) {
+	router := newTestRouter(t, "processing", map[string]string{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/health-detailed?n=0", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("n=0: got %d, want 400", w.Code)
+	}
+
+	// どの goroutine も実行していない processing のジョブは止まっているとみなす
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/health-detailed", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("got %d, want 503: %s", w.Code, w.Body.String())
+	}
+	var detail models.HealthDetail
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+		t.Fatal(err)
+	}
+	if detail.Status != "degraded" || len(detail.StuckJobs) != 1 || detail.StuckJobs[0].JobID != testJobID || !detail.StuckJobs[0].Orphaned {
+		t.Errorf("got %+v, want the orphaned job as stuck", detail)
+	}
+}
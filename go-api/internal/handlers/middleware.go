@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/flex-api/internal/services"
+)
+
+// RequestIDHeader はリクエスト ID を受け渡すヘッダー
+const RequestIDHeader = "X-Request-ID"
+
+// APIKeyHeader は API キーを渡すヘッダー
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyVerifiedKey は RequireAPIKey がキーを確認したことを示す gin.Context のキー
+const apiKeyVerifiedKey = "api_key_verified"
+
+// requestIDPattern は受け入れるリクエスト ID（ログを汚さない文字と長さに制限）
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID はクライアントの X-Request-ID を引き継ぐか新しく発行し、
+// レスポンスヘッダーとリクエストの context に載せるミドルウェア
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(services.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// RequireAPIKey は X-API-Key が設定済みのキーのいずれかと一致しないリクエストを 401 で拒否するミドルウェア
+// キーが設定されていなければ何もしない
+func (h *Handler) RequireAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(h.apiKeys) == 0 {
+			c.Next()
+			return
+		}
+
+		given := c.GetHeader(APIKeyHeader)
+		if !matchAPIKey(given, h.apiKeys) {
+			h.log(c).Warn("RequireAPIKey: rejected request", "client_ip", c.ClientIP(), "path", c.Request.URL.Path, "key_present", given != "")
+			respondError(c, http.StatusUnauthorized, CodeUnauthorized, "missing or invalid "+APIKeyHeader)
+			return
+		}
+		c.Set(apiKeyVerifiedKey, true)
+		c.Next()
+	}
+}
+
+// matchAPIKey は given が keys のいずれかと一致するかを返す
+// キーの一致位置から時間差で推測されないよう、全キーと定数時間で比較する
+func matchAPIKey(given string, keys []string) bool {
+	valid := 0
+	for _, key := range keys {
+		valid |= subtle.ConstantTimeCompare([]byte(given), []byte(key))
+	}
+	return valid == 1
+}
+
+// ValidateJobID はパスの :job_id がジョブIDの形式（UUID または short）でなければ 400 で拒否するミドルウェア
+// "../etc" のような値がファイル操作まで届かないよう、ハンドラーより前で弾く（:job_id の無いルートは素通り）
+func ValidateJobID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if jobID := c.Param("job_id"); jobID != "" && !services.IsValidJobID(jobID) {
+			respondError(c, http.StatusBadRequest, CodeInvalidJobID, "invalid job_id: "+jobID)
+			return
+		}
+		c.Next()
+	}
+}
+
+// log は request_id 付きのロガーを返す
+func (h *Handler) log(c *gin.Context) *slog.Logger {
+	if requestID := services.RequestIDFromContext(c.Request.Context()); requestID != "" {
+		return h.logger.With("request_id", requestID)
+	}
+	return h.logger
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/flex-api/internal/services"
+)
+
+func TestValidateJobID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(services.NewJobService(t.TempDir(), "python3", "", nil, nil), nil)
+	router := gin.New()
+	api := router.Group("/api/dsa", ValidateJobID())
+	api.GET("/status/:job_id", h.GetStatus)
+	api.GET("/jobs", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, tc := range []struct {
+		path string
+		want int
+	}{
+		{"/api/dsa/status/%2E%2E", http.StatusBadRequest},
+		{"/api/dsa/status/not-a-job", http.StatusBadRequest},
+		{"/api/dsa/status/11111111-2222-3333-4444-55555555555Z", http.StatusBadRequest},
+		{"/api/dsa/status/" + testJobID, http.StatusNotFound},
+		{"/api/dsa/status/abcdefghijklm", http.StatusNotFound},
+		{"/api/dsa/jobs", http.StatusOK},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.path, w.Code, tc.want)
+		}
+	}
+}
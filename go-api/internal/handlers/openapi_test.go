@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/flex-api/docs"
+	"github.com/yourusername/flex-api/internal/services"
+)
+
+// ginParam は gin のパスパラメータ（:job_id）
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+func TestAPISpecMatchesRoutes(t *testing.T) {
+	doc := docs.SwaggerInfo.ReadDoc()
+	var spec struct {
+		Swagger     string                                `json:"swagger"`
+		Paths       map[string]map[string]json.RawMessage `json:"paths"`
+		Definitions map[string]json.RawMessage            `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		t.Fatalf("docs/swagger.json is not valid JSON: %v", err)
+	}
+	if spec.Swagger != "2.0" {
+		t.Errorf("swagger = %q, want 2.0", spec.Swagger)
+	}
+
+	router := gin.New()
+	NewHandler(services.NewJobService(t.TempDir(), "python3", "", nil, nil), nil).RegisterRoutes(router)
+
+	// 登録した全ルートが定義にあること（Swagger UI 自体は除く）
+	routes := map[string]bool{}
+	for _, r := range router.Routes() {
+		if strings.HasPrefix(r.Path, "/swagger/") {
+			continue
+		}
+		path := ginParam.ReplaceAllString(r.Path, "{$1}")
+		method := strings.ToLower(r.Method)
+		routes[method+" "+path] = true
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("%s %s is not documented (add swag annotations and run swag init)", r.Method, path)
+		}
+	}
+	// 定義にある全ルートが登録されていること
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			if !routes[method+" "+path] {
+				t.Errorf("%s %s is documented but not registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+
+	// 全ての $ref が definitions にあること
+	refs := regexp.MustCompile(`"\$ref": "#/definitions/([^"]+)"`).FindAllStringSubmatch(doc, -1)
+	if len(refs) == 0 {
+		t.Fatal("no $ref found")
+	}
+	for _, ref := range refs {
+		if _, ok := spec.Definitions[ref[1]]; !ok {
+			t.Errorf("unresolved $ref #/definitions/%s", ref[1])
+		}
+	}
+}
+
+func TestSwaggerUIServesBundledAssets(t *testing.T) {
+	router := gin.New()
+	NewHandler(services.NewJobService(t.TempDir(), "python3", "", nil, nil), nil).RegisterRoutes(router)
+
+	// オフライン環境でも表示できるよう、UI のアセットは CDN ではなくサーバーから返す
+	for _, path := range []string{"/swagger/index.html", "/swagger/swagger-ui-bundle.js", "/swagger/doc.json"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s: status %d, want 200", path, w.Code)
+		}
+		if strings.Contains(w.Body.String(), "unpkg.com") {
+			t.Errorf("GET %s loads assets from unpkg.com", path)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimiterPruneInterval は満タンに戻ったバケットを掃除する間隔
+const rateLimiterPruneInterval = time.Minute
+
+// rateLimiter はクライアントごとのトークンバケット
+type rateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter は 1 分あたり perMinute 回、最大 burst 回まで連続で許可するリミッターを作成
+func newRateLimiter(perMinute float64, burst int) (*rateLimiter, error) {
+	if perMinute <= 0 || math.IsInf(perMinute, 0) || math.IsNaN(perMinute) {
+		return nil, fmt.Errorf("rate limit must be a positive number per minute: %v", perMinute)
+	}
+	if burst < 1 {
+		return nil, fmt.Errorf("rate limit burst must be at least 1: %d", burst)
+	}
+	return &rateLimiter{
+		perSecond: perMinute / 60,
+		burst:     float64(burst),
+		buckets:   make(map[string]*tokenBucket),
+		now:       time.Now,
+	}, nil
+}
+
+// allow は key のトークンを 1 つ消費する。足りなければ次の 1 つが溜まるまでの時間を返す
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(l.burst, b.tokens+elapsed*l.perSecond)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.perSecond * float64(time.Second))
+	return false, wait
+}
+
+// prune は満タンまで回復したバケットを捨てる（消えても新規作成時と同じ状態になるだけ）
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < rateLimiterPruneInterval {
+		return
+	}
+	l.lastPrune = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.perSecond >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// SetAnalyzeRateLimit はジョブを作成するエンドポイントのクライアントごとの上限を設定（perMinute が 0 なら無制限）
+func (h *Handler) SetAnalyzeRateLimit(perMinute float64, burst int) error {
+	if perMinute == 0 {
+		h.analyzeLimiter = nil
+		return nil
+	}
+	limiter, err := newRateLimiter(perMinute, burst)
+	if err != nil {
+		return fmt.Errorf("analyze %w", err)
+	}
+	h.analyzeLimiter = limiter
+	return nil
+}
+
+// SetReadRateLimit は参照系エンドポイントのクライアントごとの上限を設定（perMinute が 0 なら無制限）
+func (h *Handler) SetReadRateLimit(perMinute float64, burst int) error {
+	if perMinute == 0 {
+		h.readLimiter = nil
+		return nil
+	}
+	limiter, err := newRateLimiter(perMinute, burst)
+	if err != nil {
+		return fmt.Errorf("read %w", err)
+	}
+	h.readLimiter = limiter
+	return nil
+}
+
+// LimitAnalyze はジョブ作成（解析の実行）を制限するミドルウェア
+func (h *Handler) LimitAnalyze() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.rateLimit(c, h.analyzeLimiter, "analyze")
+	}
+}
+
+// LimitReads は結果・ステータス取得などの参照系を制限するミドルウェア
+func (h *Handler) LimitReads() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.rateLimit(c, h.readLimiter, "read")
+	}
+}
+
+// clientKey はクライアントを区別するキー（RequireAPIKey が確認した API キー、それ以外はクライアント IP）
+// /ws のようにキーの確認より前に制限するルートでは、任意のキーを送って別枠を得られないよう IP で数える
+func (h *Handler) clientKey(c *gin.Context) string {
+	if c.GetBool(apiKeyVerifiedKey) {
+		return "key:" + c.GetHeader(APIKeyHeader)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// rateLimit は API キー（未認証ならクライアント IP）ごとにトークンを消費し、
+// 超過したら Retry-After 付きの 429 を返す
+func (h *Handler) rateLimit(c *gin.Context, limiter *rateLimiter, scope string) {
+	if limiter == nil {
+		c.Next()
+		return
+	}
+
+	ok, wait := limiter.allow(h.clientKey(c))
+	if !ok {
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		h.log(c).Warn("rateLimit: rejected request", "scope", scope, "client_ip", c.ClientIP(), "path", c.Request.URL.Path, "retry_after", retryAfter)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		respondErrorDetails(c, http.StatusTooManyRequests, CodeRateLimited, "rate limit exceeded", map[string]any{"retry_after": retryAfter})
+		return
+	}
+	c.Next()
+}
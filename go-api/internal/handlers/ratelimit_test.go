@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/flex-api/internal/services"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	limiter, err := newRateLimiter(60, 2) // 1 秒に 1 トークン、最大 2
+	if err != nil {
+		t.Fatalf("newRateLimiter: %v", err)
+	}
+	now := time.Unix(1000, 0)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.allow("a"); !ok {
+			t.Fatalf("request %d within burst was rejected", i)
+		}
+	}
+	ok, wait := limiter.allow("a")
+	if ok {
+		t.Fatal("request over burst was allowed")
+	}
+	if wait != time.Second {
+		t.Errorf("wait = %v, want 1s", wait)
+	}
+
+	// 別のクライアントは独立して数える
+	if ok, _ := limiter.allow("b"); !ok {
+		t.Error("other client was rejected")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Error("request after refill was rejected")
+	}
+}
+
+func TestRateLimiterPrune(t *testing.T) {
+	limiter, err := newRateLimiter(60, 1)
+	if err != nil {
+		t.Fatalf("newRateLimiter: %v", err)
+	}
+	now := time.Unix(1000, 0)
+	limiter.now = func() time.Time { return now }
+
+	limiter.allow("a")
+	now = now.Add(2 * time.Minute)
+	limiter.allow("b")
+
+	if _, ok := limiter.buckets["a"]; ok {
+		t.Error("refilled bucket was not pruned")
+	}
+	if _, ok := limiter.buckets["b"]; !ok {
+		t.Error("active bucket was pruned")
+	}
+}
+
+func TestNewRateLimiterRejectsInvalid(t *testing.T) {
+	if _, err := newRateLimiter(-1, 1); err == nil {
+		t.Error("negative rate was accepted")
+	}
+	if _, err := newRateLimiter(10, 0); err == nil {
+		t.Error("zero burst was accepted")
+	}
+}
+
+func TestRateLimitKeysOnVerifiedAPIKeyOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(services.NewJobService(t.TempDir(), "python3", "", nil, nil), nil)
+	if err := h.SetAPIKeys([]string{"key-a", "key-b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetReadRateLimit(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router := gin.New()
+	// /ws と同じく、キーの確認より前に制限するルート
+	router.GET("/open", h.LimitReads(), ok)
+	router.GET("/api", h.RequireAPIKey(), h.LimitReads(), ok)
+
+	get := func(path, key string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 確認済みのキーはキーごとに数える
+	if code := get("/api", "key-a"); code != http.StatusNoContent {
+		t.Fatalf("first request with key-a: got %d", code)
+	}
+	if code := get("/api", "key-b"); code != http.StatusNoContent {
+		t.Errorf("first request with key-b: got %d", code)
+	}
+	if code := get("/api", "key-a"); code != http.StatusTooManyRequests {
+		t.Errorf("second request with key-a: got %d, want 429", code)
+	}
+
+	// 未確認のキーを変えても別枠にはならない（同じ IP で数える）
+	if code := get("/open", "made-up-1"); code != http.StatusNoContent {
+		t.Fatalf("first unverified request: got %d", code)
+	}
+	if code := get("/open", "made-up-2"); code != http.StatusTooManyRequests {
+		t.Errorf("second unverified request with another key: got %d, want 429", code)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+
+	// API 定義（swag init で生成）を /swagger/doc.json に登録する
+	_ "github.com/yourusername/flex-api/docs"
+)
+
+// RegisterRoutes は全エンドポイントを router に登録する
+// ルートを変えたら swag init で docs/ を作り直す（openapi_test.go がずれを検出する）
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	// ルート設定（ヘルスチェック・メトリクス・API 定義は認証なし、/api/dsa 以下は API キーが設定されていれば必須）
+	router.GET("/health", h.HealthCheck)
+	router.GET("/ready", h.Ready)
+	router.GET("/health/load", h.HealthLoad)
+	router.GET("/metrics", Metrics())
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// ジョブ作成は Python を走らせるので厳しく、参照系は安いので別枠（既定は無制限）で制限する
+	limitAnalyze := h.LimitAnalyze()
+	limitReads := h.LimitReads()
+	// WebSocket はブラウザが X-API-Key を付けられないので、API キーは JobSocket が最初のフレームでも確認する
+	router.GET("/api/dsa/jobs/:job_id/ws", ValidateJobID(), limitReads, h.JobSocket)
+	api := router.Group("/api/dsa", h.RequireAPIKey(), ValidateJobID())
+	{
+		api.POST("/analyze", limitAnalyze, h.CreateAnalysis)
+		api.POST("/analyze-batch", limitAnalyze, h.CreateBatchAnalysis)
+		api.POST("/validate", limitReads, h.ValidateResult)
+		api.GET("/defaults", limitReads, h.GetAnalysisDefaults)
+		api.GET("/jobs", limitReads, h.ListJobs)
+		api.DELETE("/jobs/:job_id", h.CancelJob)
+		api.DELETE("/jobs/:job_id/storage", h.DeleteJobStorage)
+		api.POST("/jobs/:job_id/retry", limitAnalyze, h.RetryJob)
+		api.GET("/jobs/:job_id/events", limitReads, h.StreamEvents)
+		api.GET("/jobs/:job_id/download", limitReads, h.DownloadJob)
+		api.GET("/jobs/:job_id/logs", limitReads, h.GetJobLogs)
+		api.GET("/jobs/:job_id/command", limitReads, h.GetJobCommand)
+		api.GET("/jobs/:job_id/files", limitReads, h.ListJobFiles)
+		api.GET("/jobs/:job_id/structures", limitReads, h.GetJobStructures)
+		api.GET("/jobs/:job_id/pair-scores", limitReads, h.GetPairScores)
+		api.GET("/jobs/:job_id/per-residue", limitReads, h.GetPerResidueScores)
+		api.GET("/jobs/:job_id/summary", limitReads, h.GetResultSummary)
+		api.GET("/jobs/:job_id/result.csv", limitReads, h.GetResultCSV)
+		api.GET("/status/:job_id", limitReads, h.GetStatus)
+		api.GET("/result/:job_id", limitReads, h.GetResult)
+		api.GET("/jobs/:job_id/result", limitReads, h.GetResult)
+		api.GET("/batches/:batch_id", limitReads, h.GetBatchJobs)
+		api.GET("/batches/:batch_id/progress", limitReads, h.GetBatchProgress)
+		api.GET("/batches/:batch_id/status", limitReads, h.GetBatchStatus)
+		api.GET("/jobs/:job_id/heatmap", limitReads, h.GetHeatmap)
+		api.GET("/jobs/:job_id/heatmap/thumbnail", limitReads, h.GetHeatmapThumbnail)
+		api.GET("/jobs/:job_id/heatmap.json", limitReads, h.GetHeatmapJSON)
+		api.GET("/jobs/:job_id/cis", limitReads, h.GetCisInfo)
+		api.GET("/jobs/:job_id/distance-score", limitReads, h.GetDistanceScore)
+		api.GET("/jobs/:job_id/distance-score.json", limitReads, h.GetDistanceScoreJSON)
+	}
+
+	admin := router.Group("/api/dsa/admin", h.RequireAPIKey())
+	{
+		admin.GET("/status", h.AdminStatus)
+		admin.GET("/health-detailed", h.HealthDetailed)
+		admin.POST("/cancel", h.CancelJobs)
+		admin.POST("/cleanup", h.CleanupJobs)
+	}
+}
+
+// Metrics は prometheus.DefaultRegisterer に登録したメトリクスを Prometheus のテキスト形式で返す
+// GET /metrics
+//
+//	@ID			metrics
+//	@Summary	Prometheus metrics
+//	@Tags		health
+//	@Produce	plain
+//	@Success	200	{string}	string	"Metrics in Prometheus text format"
+//	@Router		/metrics [get]
+func Metrics() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
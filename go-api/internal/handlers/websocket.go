@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/yourusername/flex-api/internal/models"
+	"github.com/yourusername/flex-api/internal/services"
+)
+
+// socketAuthTimeout は X-API-Key の無い接続が最初の {"action":"auth"} を送るまでの猶予
+const socketAuthTimeout = 10 * time.Second
+
+// JobSocket はステータス変更を WebSocket で送り、同じ接続で {"action":"cancel"} を受け付ける
+// 終了状態になったら最後のステータスを送ってから閉じる
+// Origin は SetSocketOrigins の値（-cors-origins）で確認する。API キーを設定したサーバーでは
+// X-API-Key を付けられないクライアント（ブラウザ）は最初のフレームで {"action":"auth","token":"..."} を送る
+// GET /api/dsa/jobs/:job_id/ws
+//
+//	@ID			jobSocket
+//	@Summary	WebSocket for status updates and job control
+//	@Tags		jobs
+//	@Produce	json
+//	@Param		job_id	path		string					true	"UUID or 13-character short job ID; other values get 400"
+//	@Success	101		{object}	models.JobSocketMessage	"Upgraded. The server sends JobSocketMessage frames, starting with the current status, and closes after a terminal status. The client may send JobSocketCommand frames ({\"action\":\"cancel\"})"
+//	@Failure	400		{object}	ErrorResponse			"Invalid path or query parameter"
+//	@Failure	401		{object}	ErrorResponse			"Wrong X-API-Key header. Browsers cannot send X-API-Key, so when the server has API keys they send {\"action\":\"auth\",\"token\":\"...\"} first (within 10 s); a wrong token gets an error frame and the connection is closed"
+//	@Failure	403		{string}	string					"The Origin header is not one of -cors-origins"
+//	@Failure	404		{object}	ErrorResponse			"Job not found"
+//	@Failure	429		{object}	ErrorResponse			"Per-client rate limit exceeded"
+//	@Header		429		{integer}	Retry-After				"Seconds until the next request is allowed"
+//	@Security	ApiKey
+//	@Router		/api/dsa/jobs/{job_id}/ws [get]
+func (h *Handler) JobSocket(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "job_id is required")
+		return
+	}
+
+	// ヘッダーで認証できるクライアントは接続前に確認する（違うキーなら 401）
+	given := c.GetHeader(APIKeyHeader)
+	authorized := len(h.apiKeys) == 0 || matchAPIKey(given, h.apiKeys)
+	if !authorized && given != "" {
+		h.log(c).Warn("JobSocket: rejected request", "client_ip", c.ClientIP(), "job_id", jobID)
+		respondError(c, http.StatusUnauthorized, CodeUnauthorized, "missing or invalid "+APIKeyHeader)
+		return
+	}
+
+	// 取りこぼしを防ぐため、現在のステータスを読む前に購読する（SSE と同じ）
+	events, unsubscribe := h.jobService.Subscribe(jobID)
+	defer unsubscribe()
+
+	var status *models.JobStatus
+	if authorized {
+		st, err := h.jobService.GetJobStatus(jobID)
+		if err != nil {
+			respondServiceError(c, http.StatusNotFound, err)
+			return
+		}
+		status = st
+	}
+
+	upgrader := websocket.Upgrader{CheckOrigin: h.checkSocketOrigin}
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade が 403 などの応答を書いている
+		h.log(c).Warn("JobSocket: upgrade failed", "job_id", jobID, "origin", c.GetHeader("Origin"), "error", err)
+		return
+	}
+	defer ws.Close()
+
+	if !authorized {
+		if !h.authenticateJobSocket(c, ws) {
+			sendSocketError(ws, "missing or invalid API key")
+			return
+		}
+		st, err := h.jobService.GetJobStatus(jobID)
+		if err != nil {
+			sendSocketError(ws, err.Error())
+			return
+		}
+		status = st
+	}
+
+	h.serveJobSocket(c, ws, jobID, *status, events)
+}
+
+// checkSocketOrigin は接続元のページの Origin を許可するか返す
+// Origin の無いリクエストはブラウザ以外からなので通す（API キーで制限される）
+func (h *Handler) checkSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if len(h.socketOrigins) == 0 {
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+	for _, allowed := range h.socketOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticateJobSocket は最初のフレームの {"action":"auth","token":"..."} を API キーと照合する
+func (h *Handler) authenticateJobSocket(c *gin.Context, ws *websocket.Conn) bool {
+	ws.SetReadDeadline(time.Now().Add(socketAuthTimeout))
+	defer ws.SetReadDeadline(time.Time{})
+
+	var cmd models.JobSocketCommand
+	if err := ws.ReadJSON(&cmd); err != nil {
+		h.log(c).Debug("JobSocket: no auth frame", "error", err)
+		return false
+	}
+	if cmd.Action != "auth" || !matchAPIKey(cmd.Token, h.apiKeys) {
+		h.log(c).Warn("JobSocket: rejected auth frame", "client_ip", c.ClientIP(), "action", cmd.Action)
+		return false
+	}
+	return true
+}
+
+// sendSocketError はエラーフレームを送ってから閉じる理由を伝える
+func sendSocketError(ws *websocket.Conn, message string) {
+	ws.WriteJSON(models.JobSocketMessage{Type: "error", Error: message})
+	ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, ""))
+}
+
+// serveJobSocket は接続ごとの送受信ループ（書き込みはこの goroutine からのみ行う）
+func (h *Handler) serveJobSocket(c *gin.Context, ws *websocket.Conn, jobID string, status models.JobStatus, events <-chan models.JobStatus) {
+	send := func(msg models.JobSocketMessage) bool {
+		if err := ws.WriteJSON(msg); err != nil {
+			h.log(c).Debug("JobSocket: send failed", "job_id", jobID, "error", err)
+			return false
+		}
+		return true
+	}
+	sendStatus := func(st models.JobStatus) bool {
+		return send(models.JobSocketMessage{Type: "status", Status: &st})
+	}
+
+	if !sendStatus(status) || services.IsTerminalStatus(status.Status) {
+		return
+	}
+
+	// 受信は別 goroutine で行い、クライアントが閉じたら commands を閉じて知らせる
+	done := make(chan struct{})
+	defer close(done)
+	commands := make(chan []byte)
+	go func() {
+		defer close(commands)
+		for {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			select {
+			case commands <- data:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case st := <-events:
+			if !sendStatus(st) || services.IsTerminalStatus(st.Status) {
+				return
+			}
+		case data, ok := <-commands:
+			if !ok {
+				return
+			}
+			if errMsg := h.handleJobSocketCommand(c, jobID, data); errMsg != "" {
+				if !send(models.JobSocketMessage{Type: "error", Error: errMsg}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// handleJobSocketCommand はクライアントからのフレームを処理し、失敗したらクライアントに返すメッセージを返す
+// キャンセルが成功した場合の結果は購読しているステータス変更として届く
+func (h *Handler) handleJobSocketCommand(c *gin.Context, jobID string, data []byte) string {
+	var cmd models.JobSocketCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return "invalid message: " + err.Error()
+	}
+
+	switch cmd.Action {
+	case "cancel":
+		if err := h.jobService.CancelJob(jobID); err != nil {
+			if !errors.Is(err, services.ErrJobFinished) && !errors.Is(err, services.ErrJobNotFound) {
+				h.log(c).Error("JobSocket: failed to cancel job", "job_id", jobID, "error", err)
+			}
+			return err.Error()
+		}
+		return ""
+	case "auth":
+		// 認証済み（キー未設定のサーバー・ヘッダーで認証済み）の接続では何もしない
+		return ""
+	default:
+		return "unknown action: " + cmd.Action
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/yourusername/flex-api/internal/models"
+	"github.com/yourusername/flex-api/internal/services"
+)
+
+// blockingRunner はキャンセルされるまで返らない Runner
+type blockingRunner struct{}
+
+func (blockingRunner) Run(ctx context.Context, args []string, dir string, env []string, onLine func(line string)) ([]byte, []byte, error) {
+	<-ctx.Done()
+	return nil, nil, ctx.Err()
+}
+
+func TestJobSocketCancel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := services.NewJobService(t.TempDir(), "python3", "", blockingRunner{}, nil)
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	// キャンセル後のジョブが TempDir の削除より先に終わるよう待つ
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+	}()
+
+	router := gin.New()
+	router.GET("/jobs/:job_id/ws", NewHandler(s, nil).JobSocket)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial(socketURL(server, job.JobID), nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer ws.Close()
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg models.JobSocketMessage
+	if err := ws.ReadJSON(&msg); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if msg.Type != "status" || msg.Status == nil || services.IsTerminalStatus(msg.Status.Status) {
+		t.Fatalf("first frame = %+v, want a non-terminal status", msg)
+	}
+
+	if err := ws.WriteMessage(websocket.TextMessage, []byte("not json")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := ws.ReadJSON(&msg); err != nil || msg.Type != "error" {
+		t.Fatalf("got %+v, %v; want an error frame", msg, err)
+	}
+
+	if err := ws.WriteJSON(models.JobSocketCommand{Action: "cancel"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	// 途中の processing などを読み飛ばし、最後に cancelled が届いてから閉じられる
+	for {
+		msg = models.JobSocketMessage{}
+		if err := ws.ReadJSON(&msg); err != nil {
+			t.Fatalf("connection closed before the cancelled status: %v", err)
+		}
+		if msg.Type == "status" && msg.Status.Status == "cancelled" {
+			break
+		}
+	}
+	if err := ws.ReadJSON(&msg); err == nil {
+		t.Errorf("connection still open after terminal status, got %+v", msg)
+	}
+}
+
+// socketURL は httptest のサーバーでジョブの WebSocket を開く URL
+func socketURL(server *httptest.Server, jobID string) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/jobs/" + jobID + "/ws"
+}
+
+func TestJobSocketChecksOriginAndAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newTestRouter(t, "completed", map[string]string{})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	dial := func(header http.Header) (*websocket.Conn, int, error) {
+		t.Helper()
+		ws, resp, err := websocket.DefaultDialer.Dial(socketURL(server, testJobID), header)
+		code := 0
+		if resp != nil {
+			code = resp.StatusCode
+		}
+		return ws, code, err
+	}
+
+	// -cors-origins に無いサイトのページからは開けない
+	if _, code, err := dial(http.Header{"Origin": {"https://evil.example"}}); err == nil || code != http.StatusForbidden {
+		t.Errorf("foreign origin: got %d, %v; want 403", code, err)
+	}
+	// ヘッダーで違うキーを渡したら接続前に 401
+	if _, code, err := dial(http.Header{APIKeyHeader: {"wrong"}}); err == nil || code != http.StatusUnauthorized {
+		t.Errorf("wrong X-API-Key: got %d, %v; want 401", code, err)
+	}
+
+	// ブラウザはヘッダーを付けられないので最初のフレームで認証する
+	for token, wantType := range map[string]string{"secret": "status", "wrong": "error"} {
+		ws, _, err := dial(http.Header{"Origin": {"http://localhost:3000"}})
+		if err != nil {
+			t.Fatalf("Dial: %v", err)
+		}
+		ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if err := ws.WriteJSON(models.JobSocketCommand{Action: "auth", Token: token}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		var msg models.JobSocketMessage
+		if err := ws.ReadJSON(&msg); err != nil || msg.Type != wantType {
+			t.Errorf("token %q: got %+v, %v; want a %s frame", token, msg, err, wantType)
+		}
+		ws.Close()
+	}
+}
+
+func TestJobSocketUnknownJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/jobs/:job_id/ws", NewHandler(services.NewJobService(t.TempDir(), "python3", "", nil, nil), nil).JobSocket)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jobs/"+testJobID+"/ws", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got %d, want 404", w.Code)
+	}
+}
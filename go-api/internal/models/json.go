@@ -0,0 +1,165 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// encoding/json は NaN/Inf の float64 をエンコードできない（1 つでもあるとレスポンス全体が 500 になる）
+// スコア・距離を持つ型は MarshalJSON で NaN/Inf を null として出力し、UnmarshalJSON で null を NaN に戻す
+
+// finiteOrNull は NaN/Inf なら nil（JSON の null）、それ以外は値へのポインタを返す
+func finiteOrNull(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+// nullToNaN は JSON の null（nil）を NaN に戻す
+// 呼び出し側は事前に既存値へのポインタを入れておき、フィールドが無い場合は既存値を保つ
+func nullToNaN(p *float64) float64 {
+	if p == nil {
+		return math.NaN()
+	}
+	return *p
+}
+
+// MarshalJSON は NaN/Inf の距離・スコアを null として出力する
+func (p PairScore) MarshalJSON() ([]byte, error) {
+	type pairScore PairScore
+	return json.Marshal(struct {
+		pairScore
+		DistanceMean *float64 `json:"distance_mean"`
+		DistanceStd  *float64 `json:"distance_std"`
+		Score        *float64 `json:"score"`
+	}{pairScore(p), finiteOrNull(p.DistanceMean), finiteOrNull(p.DistanceStd), finiteOrNull(p.Score)})
+}
+
+// UnmarshalJSON は null の距離・スコアを NaN として読み込む
+func (p *PairScore) UnmarshalJSON(data []byte) error {
+	type pairScore PairScore
+	aux := struct {
+		*pairScore
+		DistanceMean *float64 `json:"distance_mean"`
+		DistanceStd  *float64 `json:"distance_std"`
+		Score        *float64 `json:"score"`
+	}{(*pairScore)(p), &p.DistanceMean, &p.DistanceStd, &p.Score}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.DistanceMean, p.DistanceStd, p.Score = nullToNaN(aux.DistanceMean), nullToNaN(aux.DistanceStd), nullToNaN(aux.Score)
+	return nil
+}
+
+// MarshalJSON は NaN/Inf のスコアを null として出力する
+func (r PerResidueScore) MarshalJSON() ([]byte, error) {
+	type perResidueScore PerResidueScore
+	return json.Marshal(struct {
+		perResidueScore
+		Score *float64 `json:"score"`
+	}{perResidueScore(r), finiteOrNull(r.Score)})
+}
+
+// UnmarshalJSON は null のスコアを NaN として読み込む
+func (r *PerResidueScore) UnmarshalJSON(data []byte) error {
+	type perResidueScore PerResidueScore
+	aux := struct {
+		*perResidueScore
+		Score *float64 `json:"score"`
+	}{(*perResidueScore)(r), &r.Score}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.Score = nullToNaN(aux.Score)
+	return nil
+}
+
+// MarshalJSON は cis ペアが無いときなどの NaN の統計値を null として出力する
+func (c CisInfo) MarshalJSON() ([]byte, error) {
+	type cisInfo CisInfo
+	return json.Marshal(struct {
+		cisInfo
+		CisDistMean  *float64 `json:"cis_dist_mean"`
+		CisDistStd   *float64 `json:"cis_dist_std"`
+		CisScoreMean *float64 `json:"cis_score_mean"`
+	}{cisInfo(c), finiteOrNull(c.CisDistMean), finiteOrNull(c.CisDistStd), finiteOrNull(c.CisScoreMean)})
+}
+
+// UnmarshalJSON は null の統計値を NaN として読み込む
+func (c *CisInfo) UnmarshalJSON(data []byte) error {
+	type cisInfo CisInfo
+	aux := struct {
+		*cisInfo
+		CisDistMean  *float64 `json:"cis_dist_mean"`
+		CisDistStd   *float64 `json:"cis_dist_std"`
+		CisScoreMean *float64 `json:"cis_score_mean"`
+	}{(*cisInfo)(c), &c.CisDistMean, &c.CisDistStd, &c.CisScoreMean}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	c.CisDistMean, c.CisDistStd, c.CisScoreMean = nullToNaN(aux.CisDistMean), nullToNaN(aux.CisDistStd), nullToNaN(aux.CisScoreMean)
+	return nil
+}
+
+// MarshalJSON は NaN/Inf のグローバル指標を null として出力する
+func (r NotebookDSAResult) MarshalJSON() ([]byte, error) {
+	type notebookDSAResult NotebookDSAResult
+	return json.Marshal(struct {
+		notebookDSAResult
+		SeqRatio               *float64 `json:"seq_ratio"`
+		ResidueCoveragePercent *float64 `json:"residue_coverage_percent"`
+		UMF                    *float64 `json:"umf"`
+		PairScoreMean          *float64 `json:"pair_score_mean"`
+		PairScoreStd           *float64 `json:"pair_score_std"`
+	}{
+		notebookDSAResult(r),
+		finiteOrNull(r.SeqRatio),
+		finiteOrNull(r.ResidueCoveragePercent),
+		finiteOrNull(r.UMF),
+		finiteOrNull(r.PairScoreMean),
+		finiteOrNull(r.PairScoreStd),
+	})
+}
+
+// UnmarshalJSON は null のグローバル指標を NaN として読み込む
+func (r *NotebookDSAResult) UnmarshalJSON(data []byte) error {
+	type notebookDSAResult NotebookDSAResult
+	aux := struct {
+		*notebookDSAResult
+		SeqRatio               *float64 `json:"seq_ratio"`
+		ResidueCoveragePercent *float64 `json:"residue_coverage_percent"`
+		UMF                    *float64 `json:"umf"`
+		PairScoreMean          *float64 `json:"pair_score_mean"`
+		PairScoreStd           *float64 `json:"pair_score_std"`
+	}{(*notebookDSAResult)(r), &r.SeqRatio, &r.ResidueCoveragePercent, &r.UMF, &r.PairScoreMean, &r.PairScoreStd}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.SeqRatio = nullToNaN(aux.SeqRatio)
+	r.ResidueCoveragePercent = nullToNaN(aux.ResidueCoveragePercent)
+	r.UMF = nullToNaN(aux.UMF)
+	r.PairScoreMean = nullToNaN(aux.PairScoreMean)
+	r.PairScoreStd = nullToNaN(aux.PairScoreStd)
+	return nil
+}
+
+// MarshalJSON は NaN/Inf のグローバル指標を null として出力する
+func (s ResultSummary) MarshalJSON() ([]byte, error) {
+	type resultSummary ResultSummary
+	return json.Marshal(struct {
+		resultSummary
+		SeqRatio               *float64 `json:"seq_ratio"`
+		UMF                    *float64 `json:"umf"`
+		PairScoreMean          *float64 `json:"pair_score_mean"`
+		PairScoreStd           *float64 `json:"pair_score_std"`
+		ResidueCoveragePercent *float64 `json:"residue_coverage_percent"`
+	}{
+		resultSummary(s),
+		finiteOrNull(s.SeqRatio),
+		finiteOrNull(s.UMF),
+		finiteOrNull(s.PairScoreMean),
+		finiteOrNull(s.PairScoreStd),
+		finiteOrNull(s.ResidueCoveragePercent),
+	})
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestResultMarshalsNonFiniteAsNull(t *testing.T) {
+	result := NotebookDSAResult{
+		UniProtID: "P12345",
+		UMF:       math.NaN(),
+		PairScores: []PairScore{
+			{I: 1, J: 2, DistanceMean: 3.8, DistanceStd: 0, Score: math.Inf(1)},
+		},
+		PerResidueScores: []PerResidueScore{{Index: 0, ResidueNumber: 1, ResidueName: "ALA", Score: math.NaN()}},
+		CisInfo:          CisInfo{CisDistMean: math.NaN(), Threshold: 3.3},
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, want := range []string{`"umf":null`, `"score":null`, `"cis_dist_mean":null`, `"distance_mean":3.8`, `"uniprot_id":"P12345"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %s in %s", want, data)
+		}
+	}
+
+	var decoded NotebookDSAResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !math.IsNaN(decoded.UMF) || !math.IsNaN(decoded.PairScores[0].Score) || !math.IsNaN(decoded.PerResidueScores[0].Score) || !math.IsNaN(decoded.CisInfo.CisDistMean) {
+		t.Errorf("null values were not decoded as NaN: %+v", decoded)
+	}
+	if decoded.PairScores[0].DistanceMean != 3.8 || decoded.CisInfo.Threshold != 3.3 || decoded.UniProtID != "P12345" {
+		t.Errorf("finite values were not preserved: %+v", decoded)
+	}
+}
+
+func TestPairScoreUnmarshalKeepsMissingFields(t *testing.T) {
+	var ps PairScore
+	if err := json.Unmarshal([]byte(`{"i":1,"j":2,"score":null}`), &ps); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if ps.I != 1 || ps.J != 2 || ps.DistanceMean != 0 || !math.IsNaN(ps.Score) {
+		t.Errorf("got %+v, want missing distance_mean as 0 and null score as NaN", ps)
+	}
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"log/slog"
+	"net/url"
+	"time"
+)
 
 // AnalysisParams は解析リクエストのパラメータ（Notebook DSA対応）
 type AnalysisParams struct {
@@ -8,44 +12,363 @@ type AnalysisParams struct {
 	Method        *string  `json:"method,omitempty"`                 // "X-ray", "NMR", "EM" (デフォルト: "X-ray")
 	SeqRatio      *float64 `json:"seq_ratio,omitempty"`              // 0.0-1.0 (デフォルト: 0.2)
 	NegativePDBID *string  `json:"negative_pdbid,omitempty"`         // 除外するPDB ID（スペースまたはカンマ区切り）
+	PDBIDs        []string `json:"pdb_ids,omitempty"`                // 解析するPDB ID（指定するとUniProtからの自動選択をしない。UniProt IDは1つのみ）
+	Priority      *string  `json:"priority,omitempty"`               // 実行枠を待つ順番: "low", "normal", "high" (デフォルト: "normal"。high は許可された API キーのみ)
 	CisThreshold  *float64 `json:"cis_threshold,omitempty"`          // cis判定の距離閾値 (デフォルト: 3.3)
 	Export        *bool    `json:"export,omitempty"`                 // CSV出力するか (デフォルト: true)
 	Heatmap       *bool    `json:"heatmap,omitempty"`                // ヒートマップを生成するか (デフォルト: true)
 	ProcCis       *bool    `json:"proc_cis,omitempty"`               // cis解析を行うか (デフォルト: true)
 	Overwrite     *bool    `json:"overwrite,omitempty"`              // 上書きするか (デフォルト: true)
+	Concurrency   *int     `json:"concurrency,omitempty"`            // バッチ内の同時実行数 (デフォルト: サーバー上限)
+	CallbackURL   *string  `json:"callback_url,omitempty"`           // ジョブ終了時に通知する http(s) URL
+
+	// 構造が配列の途中から始まる場合に、残基番号（per_residue_scores の residue_number）を
+	// UniProt 上の位置に合わせるためのずれ（residue_number = index + 1 + reference_offset、デフォルト: 0）
+	ReferenceOffset *int `json:"reference_offset,omitempty"`
+}
+
+// AnalysisDefaults は AnalysisParams の未指定の項目に入れる値（サーバーの -defaults-file で変えられる）
+// GET /api/dsa/defaults でも返し、フロントエンドのフォームの初期値に使う
+type AnalysisDefaults struct {
+	Method       string  `json:"method"`
+	SeqRatio     float64 `json:"seq_ratio"`
+	CisThreshold float64 `json:"cis_threshold"`
+	Export       bool    `json:"export"`
+	Heatmap      bool    `json:"heatmap"`
+	ProcCis      bool    `json:"proc_cis"`
+	Overwrite    bool    `json:"overwrite"`
+}
+
+// LogValue はログ出力用にポインタを展開した値を返す（未指定は nil のまま）
+func (p AnalysisParams) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("uniprot_ids", p.UniProtIDs)}
+	if p.Method != nil {
+		attrs = append(attrs, slog.String("method", *p.Method))
+	}
+	if p.SeqRatio != nil {
+		attrs = append(attrs, slog.Float64("seq_ratio", *p.SeqRatio))
+	}
+	if p.NegativePDBID != nil {
+		attrs = append(attrs, slog.String("negative_pdbid", *p.NegativePDBID))
+	}
+	if len(p.PDBIDs) > 0 {
+		attrs = append(attrs, slog.Any("pdb_ids", p.PDBIDs))
+	}
+	if p.Priority != nil {
+		attrs = append(attrs, slog.String("priority", *p.Priority))
+	}
+	if p.CisThreshold != nil {
+		attrs = append(attrs, slog.Float64("cis_threshold", *p.CisThreshold))
+	}
+	if p.Export != nil {
+		attrs = append(attrs, slog.Bool("export", *p.Export))
+	}
+	if p.Heatmap != nil {
+		attrs = append(attrs, slog.Bool("heatmap", *p.Heatmap))
+	}
+	if p.ProcCis != nil {
+		attrs = append(attrs, slog.Bool("proc_cis", *p.ProcCis))
+	}
+	if p.Overwrite != nil {
+		attrs = append(attrs, slog.Bool("overwrite", *p.Overwrite))
+	}
+	if p.Concurrency != nil {
+		attrs = append(attrs, slog.Int("concurrency", *p.Concurrency))
+	}
+	if p.ReferenceOffset != nil {
+		attrs = append(attrs, slog.Int("reference_offset", *p.ReferenceOffset))
+	}
+	if p.CallbackURL != nil {
+		// URL にトークンが含まれることがあるのでホストだけ出す
+		if u, err := url.Parse(*p.CallbackURL); err == nil {
+			attrs = append(attrs, slog.String("callback_host", u.Host))
+		}
+	}
+	return slog.GroupValue(attrs...)
 }
 
 // JobResponse はジョブ作成時のレスポンス
 type JobResponse struct {
 	JobID     string    `json:"job_id"`
+	UniProtID string    `json:"uniprot_id,omitempty"` // バッチ作成時、このジョブが解析する UniProt ID
 	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// CancelJobResponse は DELETE /api/dsa/jobs/:job_id のレスポンス
+type CancelJobResponse struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"` // 常に "cancelled"
+}
+
+// DeleteJobStorageResponse は DELETE /api/dsa/jobs/:job_id/storage のレスポンス
+type DeleteJobStorageResponse struct {
+	JobID   string `json:"job_id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// RetryJobResponse は POST /api/dsa/jobs/:job_id/retry のレスポンス
+type RetryJobResponse struct {
+	JobID       string `json:"job_id"`       // 新しいジョブ
+	RetriedFrom string `json:"retried_from"` // 再実行したジョブ
+	Status      string `json:"status"`
+}
+
+// CancelJobsResponse は POST /api/dsa/admin/cancel のレスポンス
+type CancelJobsResponse struct {
+	Cancelled []string `json:"cancelled"`
+}
+
+// CleanupJobsResponse は POST /api/dsa/admin/cleanup のレスポンス
+type CleanupJobsResponse struct {
+	Removed []string `json:"removed"`
+}
+
+// BatchAnalysisParams は /analyze-batch のリクエスト（UniProt ID を配列で受け取り、1 ID につき 1 ジョブ）
+// uniprot_ids 以外のパラメータは AnalysisParams と同じで、全ジョブに共通で適用する
+type BatchAnalysisParams struct {
+	AnalysisParams
+	UniProtIDs []string `json:"uniprot_ids"`
+}
+
+// BatchJobStatus はバッチ内の 1 ジョブのステータス
+type BatchJobStatus struct {
+	UniProtID string `json:"uniprot_id"`
+	JobStatus
+}
+
+// BatchStatus はバッチ内のジョブのステータスの集計（interrupted は failed に含める）
+type BatchStatus struct {
+	BatchID   string           `json:"batch_id"`
+	CreatedAt time.Time        `json:"created_at"`
+	Total     int              `json:"total"`
+	Completed int              `json:"completed"`
+	Failed    int              `json:"failed"`
+	Cancelled int              `json:"cancelled"`
+	Running   int              `json:"running"`
+	Pending   int              `json:"pending"`
+	Missing   int              `json:"missing,omitempty"` // 削除済みでステータスを読めないジョブ
+	Jobs      []BatchJobStatus `json:"jobs"`
+}
+
+// BatchJobs はバッチ内の全ジョブのステータス（作成順）
+type BatchJobs struct {
+	BatchID string           `json:"batch_id"`
+	Jobs    []BatchJobStatus `json:"jobs"`
+}
+
 // JobsResponse は複数ジョブ作成時のレスポンス
 type JobsResponse struct {
+	BatchID   string        `json:"batch_id"`
 	Jobs      []JobResponse `json:"jobs"`
 	CreatedAt time.Time     `json:"created_at"`
 }
 
+// DryRunResponse は POST /analyze?dry_run=true のレスポンス（ジョブは作成しない）
+type DryRunResponse struct {
+	DryRun              bool           `json:"dry_run"`                         // 常に true
+	Params              AnalysisParams `json:"params"`                          // デフォルト値を補完したパラメータ
+	Jobs                []DryRunJob    `json:"jobs"`                            // 作成されるジョブ（UniProt ID ごと）
+	StructuresChecked   bool           `json:"structures_checked"`              // PDB エントリを照会したか（check_structures=true）
+	StructureCheckError string         `json:"structure_check_error,omitempty"` // 構造の確認自体に失敗した場合の理由
+}
+
+// DryRunJob は dry run で作成されるはずだったジョブ1件
+type DryRunJob struct {
+	UniProtID string   `json:"uniprot_id"`
+	PDBIDs    []string `json:"pdb_ids,omitempty"` // 解析対象になる PDB エントリ（check_structures=true の場合のみ）
+	Reason    string   `json:"reason,omitempty"`  // 実行すると失敗する見込みの理由（"no_suitable_structures" など）
+	Error     string   `json:"error,omitempty"`   // UniProt ID の照会に失敗した場合の理由
+}
+
+// BatchProgress はバッチ単位の進捗（batch.json と子ジョブのステータスから数える）
+type BatchProgress struct {
+	BatchID string `json:"batch_id"`
+	Total   int    `json:"total"`
+	Done    int    `json:"done"`    // 終了したジョブ（削除済みを含む）
+	Running int    `json:"running"` // processing
+	Queued  int    `json:"queued"`  // まだ開始していない
+}
+
 // JobStatus はジョブの状態を表す
 type JobStatus struct {
 	JobID     string    `json:"job_id"`
-	Status    string    `json:"status"` // "pending" | "processing" | "completed" | "failed"
+	Status    string    `json:"status"` // "pending" | "processing" | "completed" | "failed" | "cancelled" | "interrupted"
 	Progress  int       `json:"progress"`
 	Message   string    `json:"message"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// failed のときのみ。FailureReason は大分類（"timeout" | "invalid_input" | "no_structures" | "engine_error" | "internal_error"）、
+	// Reason はより細かい識別子（"no_suitable_structures" など、判別できた場合のみ）
+	FailureReason string `json:"failure_reason,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+
+	// 以下は保存せず、ステータスを返すときに計算する
+	DurationSeconds *int64     `json:"duration_seconds,omitempty"` // 実行中は現在まで、終了後は終了時点までの経過秒数（pending では省略）
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`     // 終了状態になった日時（終了前は省略）
+	QueuePosition   *int       `json:"queue_position,omitempty"`   // 実行枠の空き待ちの間のみ。1 なら次に実行される
+}
+
+// PollHint は未完了のジョブをいつ問い合わせ直せばよいかの目安（結果取得の 202 応答に含める）
+type PollHint struct {
+	Status                    string `json:"status"`
+	Progress                  int    `json:"progress"`
+	EstimatedRemainingSeconds *int64 `json:"estimated_remaining_seconds,omitempty"` // 完了したジョブの平均実行時間から見積もった残り秒数（実績が無ければ省略）
+	RetryAfterSeconds         int    `json:"retry_after_seconds"`                   // Retry-After ヘッダーと同じ値
+}
+
+// JobMetadata はジョブ実行時の環境情報
+type JobMetadata struct {
+	Nice *int   `json:"nice,omitempty"` // 適用されたniceness
+	CPUs string `json:"cpus,omitempty"` // 固定したCPUセット
+
+	FileLayoutVersion int `json:"file_layout_version,omitempty"` // 実行時の成果物の命名規則（FileLayout.Version）
+}
+
+// JobCommand はジョブを実行した Python CLI の呼び出し（再現・比較用）
+type JobCommand struct {
+	Args []string          `json:"args"` // nice/taskset を含む実際の argv
+	Dir  string            `json:"dir"`  // 作業ディレクトリ（絶対パス）
+	Env  map[string]string `json:"env"`  // 実行結果に影響する環境変数のみ
+}
+
+// JobFile はジョブの出力ファイル1件
+type JobFile struct {
+	Name string `json:"name"` // ジョブ配下の相対パス（"/" 区切り）
+	Size int64  `json:"size"` // バイト数
+	Type string `json:"type"` // 拡張子から判定した Content-Type
+}
+
+// StructureDetail は UniProt に登録された PDB エントリ1件の情報と、解析に使ったかどうか
+type StructureDetail struct {
+	PDBID      string   `json:"pdb_id"`
+	Method     string   `json:"method"`
+	Resolution *float64 `json:"resolution"`         // Å（NMR など値が無い・不明な場合は null）
+	NumChains  int      `json:"num_chains"`         // UniProt 配列に対応するチェーン数（不明なら 0）
+	ChainsUsed int      `json:"chains_used"`        // seq_ratio の絞り込み後に解析に使ったチェーン数
+	Mutation   string   `json:"mutation,omitempty"` // "normal" | "substitution" | "chimera" | "delins"
+	Included   bool     `json:"included"`
+	Reason     string   `json:"reason,omitempty"` // 除外理由: "negative_pdbid" | "error" | "chimera" | "delins" | "unclassified" | "seq_ratio" | "not_in_uniprot"
+}
+
+// ExcludedStructure は解析から除外した PDB エントリ（NotebookDSAResult.ExcludedStructures）
+type ExcludedStructure struct {
+	PDBID  string `json:"pdb_id"`
+	Reason string `json:"reason,omitempty"` // StructureDetail.Reason と同じ値
+}
+
+// JobStructures はジョブで対象になった PDB エントリの一覧
+type JobStructures struct {
+	JobID      string            `json:"job_id"`
+	UniProtID  string            `json:"uniprot_id"`
+	Source     string            `json:"source"` // "engine"（Python の出力）| "derived"（古いジョブ: 解析に使ったエントリのみ推定）
+	Structures []StructureDetail `json:"structures"`
+}
+
+// JobSocketMessage は GET /jobs/:job_id/ws でサーバーから送るフレーム
+type JobSocketMessage struct {
+	Type   string     `json:"type"` // "status" | "error"
+	Status *JobStatus `json:"status,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// JobSocketCommand は GET /jobs/:job_id/ws でクライアントから受け取るフレーム
+type JobSocketCommand struct {
+	Action string `json:"action"`          // "cancel" | "auth"
+	Token  string `json:"token,omitempty"` // auth のみ。X-API-Key と同じキー（ブラウザはヘッダーを付けられない）
 }
 
+// JobListPage はジョブ一覧の1ページ（作成日時の降順）
+type JobListPage struct {
+	Jobs       []JobStatus `json:"jobs"`
+	NextCursor string      `json:"next_cursor,omitempty"` // 次のページの ?cursor=（最後のページでは省略）
+}
+
+// LoadStatus はオートスケーリング向けの負荷状況
+type LoadStatus struct {
+	Status            string `json:"status"` // "ok" | "degraded" | "overloaded"
+	RunningJobs       int    `json:"running_jobs"`
+	MaxConcurrency    int    `json:"max_concurrency"`
+	QueueDepth        int    `json:"queue_depth"`
+	StorageFreeBytes  uint64 `json:"storage_free_bytes"`
+	StorageTotalBytes uint64 `json:"storage_total_bytes"`
+	AcceptingNewJobs  bool   `json:"accepting_new_jobs"`
+}
+
+// AdminStatus は実行中のジョブと実行枠・キュー・ストレージの状況（GET /api/dsa/admin/status 用）
+type AdminStatus struct {
+	RunningJobs       []RunningJob `json:"running_jobs"` // 開始が古い順
+	QueueDepth        int          `json:"queue_depth"`  // 実行枠の空き待ちのジョブ数
+	MaxConcurrency    int          `json:"max_concurrency"`
+	StorageUsedBytes  uint64       `json:"storage_used_bytes"`
+	StorageFreeBytes  uint64       `json:"storage_free_bytes"`
+	StorageTotalBytes uint64       `json:"storage_total_bytes"`
+}
+
+// RunningJob は実行中のジョブ 1 件
+type RunningJob struct {
+	JobID          string    `json:"job_id"`
+	StartedAt      time.Time `json:"started_at"`
+	ElapsedSeconds float64   `json:"elapsed_seconds"`
+}
+
+// HealthDetail は直近のジョブの成否と実行時間、止まっているジョブ（GET /api/dsa/admin/health-detailed 用）
+type HealthDetail struct {
+	Status                 string     `json:"status"`   // "ok" | "degraded"
+	Problems               []string   `json:"problems"` // degraded の理由（ok なら空）
+	Window                 int        `json:"window"`   // 集計する終了済みジョブの件数の上限（?n=）
+	Jobs                   int        `json:"jobs"`     // 集計した終了済みジョブの件数（作成が新しい順）
+	Completed              int        `json:"completed"`
+	Failed                 int        `json:"failed"` // interrupted を含む
+	Cancelled              int        `json:"cancelled"`
+	SuccessRate            *float64   `json:"success_rate"`             // completed / (completed + failed)。対象が無ければ null
+	AverageDurationSeconds *float64   `json:"average_duration_seconds"` // completed のジョブの作成から完了までの平均。対象が無ければ null
+	JobTimeoutSeconds      float64    `json:"job_timeout_seconds"`
+	StuckJobs              []StuckJob `json:"stuck_jobs"` // 制限時間を超えて processing のままのジョブ
+}
+
+// StuckJob は制限時間を超えて processing のままのジョブ
+type StuckJob struct {
+	JobID          string    `json:"job_id"`
+	StartedAt      time.Time `json:"started_at"` // 実行開始時刻（orphaned なら最後にステータスが更新された時刻）
+	ElapsedSeconds float64   `json:"elapsed_seconds"`
+	Orphaned       bool      `json:"orphaned"` // このサーバーで実行していない（プロセスの異常終了などで取り残された）
+}
+
+// Readiness はトラフィックを受けられる状態かどうか（/ready 用）
+type Readiness struct {
+	Ready  bool             `json:"ready"`
+	Checks []ReadinessCheck `json:"checks"`
+}
+
+// ReadinessCheck は個々の確認項目の結果
+type ReadinessCheck struct {
+	Name  string `json:"name"` // "storage" | "python"
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ResultSchemaVersion は現在の result.json の形式のバージョン
+// フィールドの追加・意味の変更をしたら上げ、古いバージョンの移行処理を services 側に足す
+const ResultSchemaVersion = 2
+
 // NotebookDSAResult はPythonエンジンの出力結果（仕様書のスキーマ）
 type NotebookDSAResult struct {
+	// result.json の形式のバージョン（導入前のファイルには無く 0 になる）
+	SchemaVersion int `json:"schema_version"`
+
+	// 完了せずに終わったジョブの途中の出力から組み立てた結果か（?partial=true の場合のみ true）
+	Partial bool `json:"partial,omitempty"`
+
 	// メタデータ
 	UniProtID     string   `json:"uniprot_id"`
 	NumStructures int      `json:"num_structures"`
 	NumResidues   int      `json:"num_residues"`
 	PDBIDs        []string `json:"pdb_ids"`
 	ExcludedPDBs  []string `json:"excluded_pdbs"`
+	// 除外した PDB エントリとその理由（ExcludedPDBs と同じ順。理由が分からないジョブでは reason を省略）
+	ExcludedStructures []ExcludedStructure `json:"excluded_structures"`
 	SeqRatio      float64  `json:"seq_ratio"`
 	Method        string   `json:"method"`
 	
@@ -63,6 +386,9 @@ type NotebookDSAResult struct {
 	// ペアごとの詳細
 	PairScores []PairScore `json:"pair_scores"`
 
+	// PairScores を Score 上位に絞った場合の元の件数（絞っていなければ省略）
+	PairScoresTotal *int `json:"pair_scores_total,omitempty"`
+
 	// Per-residue スコア（3D 可視化用）
 	PerResidueScores []PerResidueScore `json:"per_residue_scores"`
 
@@ -71,6 +397,27 @@ type NotebookDSAResult struct {
 
 	// Cis 統計
 	CisInfo CisInfo `json:"cis_info"`
+
+	// summary.csv の全列（ヘッダー→値、未パースの文字列のまま）
+	// ?include_raw_summary=true の場合のみ含める
+	RawSummary map[string]string `json:"raw_summary,omitempty"`
+}
+
+// ResultSummary はジョブカード表示用の結果サマリー（重い配列を除いたスカラー値のみ）
+type ResultSummary struct {
+	JobID                  string  `json:"job_id"`
+	UniProtID              string  `json:"uniprot_id"`
+	NumStructures          int     `json:"num_structures"`
+	NumResidues            int     `json:"num_residues"`
+	SeqRatio               float64 `json:"seq_ratio"`
+	Method                 string  `json:"method"`
+	UMF                    float64 `json:"umf"`
+	PairScoreMean          float64 `json:"pair_score_mean"`
+	PairScoreStd           float64 `json:"pair_score_std"`
+	ResidueCoveragePercent float64 `json:"residue_coverage_percent"`
+	NumChains              int     `json:"num_chains"`
+	CisNum                 int     `json:"cis_num"` // 全構造で常にcisのペア数
+	CisMix                 int     `json:"cis_mix"` // cis/trans混在ペア数
 }
 
 // PairScore はペアごとのスコア
@@ -83,18 +430,91 @@ type PairScore struct {
 	Score        float64 `json:"score"`
 }
 
+// PairScoresPage はペアスコアのページ（Score 降順）
+type PairScoresPage struct {
+	JobID      string      `json:"job_id"`
+	Total      int         `json:"total"` // min_score 適用後の総件数
+	Offset     int         `json:"offset"`
+	Limit      int         `json:"limit"`
+	PairScores []PairScore `json:"pair_scores"`
+}
+
+// PerResidueScores は 3D ビューアの色付け用の残基スコアとその範囲
+type PerResidueScores struct {
+	JobID            string            `json:"job_id"`
+	ScoreMin         float64           `json:"score_min"` // 有限値のみで計算（該当なしは 0）
+	ScoreMax         float64           `json:"score_max"`
+	PerResidueScores []PerResidueScore `json:"per_residue_scores"`
+}
+
 // PerResidueScore は残基ごとのスコア
 type PerResidueScore struct {
 	Index         int     `json:"index"`          // 0-based
-	ResidueNumber int     `json:"residue_number"` // 1-based (UniProt)
+	ResidueNumber int     `json:"residue_number"` // 1-based (UniProt、reference_offset を含む)
 	ResidueName   string  `json:"residue_name"`
 	Score         float64 `json:"score"`
 }
 
 // Heatmap はN×N行列
 type Heatmap struct {
-	Size   int            `json:"size"`
-	Values [][]*float64    `json:"values"` // NaN は null として表現（*float64 の nil）
+	Size   int          `json:"size"`
+	Values [][]*float64 `json:"values"` // NaN は null として表現（*float64 の nil）
+
+	// heatmap.json で ?vmin= / ?vmax= / ?scale= を指定した場合のみ。Values はこの範囲に収めた値
+	VMin  *float64 `json:"vmin,omitempty"`
+	VMax  *float64 `json:"vmax,omitempty"`
+	Scale string   `json:"scale,omitempty"` // "clamp" | "normalize"（0〜1 に写した）
+}
+
+// JobCisInfo はジョブの cis ペプチド結合の情報（GET /api/dsa/jobs/:job_id/cis 用）
+type JobCisInfo struct {
+	JobID     string    `json:"job_id"`
+	UniProtID string    `json:"uniprot_id"`
+	CisInfo   CisInfo   `json:"cis_info"`
+	Pairs     []CisPair `json:"pairs"` // CisInfo.CisPairs を残基番号に分け、残基名を付けたもの
+}
+
+// CisPair は全構造で cis だった残基ペア
+type CisPair struct {
+	I        int    `json:"i"` // 1-based
+	J        int    `json:"j"` // 1-based
+	ResidueI string `json:"residue_i,omitempty"` // 3文字コード（例: "PRO"、trimsequence が無ければ省略）
+	ResidueJ string `json:"residue_j,omitempty"`
+}
+
+// SparseHeatmap は Heatmap の値のあるセルだけを並べた形式（?format=sparse）
+type SparseHeatmap struct {
+	Size  int           `json:"size"`
+	Cells []HeatmapCell `json:"cells"` // Heatmap.Values で null になるセルは含まない
+
+	// Heatmap と同じ（表示範囲を指定した場合のみ）
+	VMin  *float64 `json:"vmin,omitempty"`
+	VMax  *float64 `json:"vmax,omitempty"`
+	Scale string   `json:"scale,omitempty"`
+}
+
+// HeatmapCell は SparseHeatmap の 1 セル（Heatmap.Values[I][J] に相当）
+type HeatmapCell struct {
+	I     int     `json:"i"` // 0-based
+	J     int     `json:"j"` // 0-based
+	Value float64 `json:"value"`
+}
+
+// DistanceScorePoints は distance_score.png の散布図の元データ（Score 降順）
+type DistanceScorePoints struct {
+	JobID       string               `json:"job_id"`
+	Total       int                  `json:"total"`       // 間引く前の点数（距離・スコアが NaN/Inf のペアは含まない）
+	Downsampled bool                 `json:"downsampled"` // max_points を超えたので間引いたか
+	Points      []DistanceScorePoint `json:"points"`
+}
+
+// DistanceScorePoint は散布図の 1 点（1 ペア）
+type DistanceScorePoint struct {
+	I            int     `json:"i"` // 1-based
+	J            int     `json:"j"` // 1-based
+	ResiduePair  string  `json:"residue_pair"`
+	MeanDistance float64 `json:"mean_distance"` // x 軸: PairScore.DistanceMean
+	Score        float64 `json:"score"`         // y 軸
 }
 
 // CisInfo はCisペプチド結合の統計情報
@@ -108,6 +528,26 @@ type CisInfo struct {
 	Threshold    float64  `json:"threshold"`
 }
 
+// ValidationIssue は result.json 検証で見つかった問題
+type ValidationIssue struct {
+	Field   string `json:"field"` // 例: "pair_scores[3]"、JSON 全体の場合は ""
+	Message string `json:"message"`
+}
+
+// ValidationReport は result.json の検証結果
+type ValidationReport struct {
+	Valid     bool              `json:"valid"`
+	Issues    []ValidationIssue `json:"issues"`
+	Truncated bool              `json:"truncated,omitempty"` // 問題が多すぎて省略した場合 true
+}
+
+// WebhookPayload はジョブ終了時に callback_url へ POST する本文
+type WebhookPayload struct {
+	JobID   string `json:"job_id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
 // ErrorResponse はエラー時のレスポンス
 type ErrorResponse struct {
 	Error         string                 `json:"error"`
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// uniProtAccessionPattern は UniProt accession の正規表現（https://www.uniprot.org/help/accession_numbers）
+var uniProtAccessionPattern = regexp.MustCompile(`^([OPQ][0-9][A-Z0-9]{3}[0-9]|[A-NR-Z][0-9]([A-Z][A-Z0-9]{2}[0-9]){1,2})$`)
+
+var uniProtIDSeparator = regexp.MustCompile(`[,\s]+`)
+
+// pdbIDPattern は 4 文字の PDB ID（先頭は数字）
+var pdbIDPattern = regexp.MustCompile(`^[0-9][A-Za-z0-9]{3}$`)
+
+// SplitUniProtIDs はUniProt ID文字列を分割（カンマまたはスペース区切り）
+func SplitUniProtIDs(idsStr string) []string {
+	parts := uniProtIDSeparator.Split(strings.TrimSpace(idsStr), -1)
+
+	var result []string
+	for _, part := range parts {
+		trimmed := strings.TrimSpace(part)
+		if trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+
+	return result
+}
+
+// SplitPDBIDs は PDB ID 文字列を分割（カンマまたはスペース区切り）
+func SplitPDBIDs(idsStr string) []string {
+	return SplitUniProtIDs(idsStr)
+}
+
+// NormalizePDBIDs は PDB ID を大文字にしてスペース区切りで連結する
+func NormalizePDBIDs(idsStr string) string {
+	ids := SplitPDBIDs(idsStr)
+	for i, id := range ids {
+		ids[i] = strings.ToUpper(id)
+	}
+	return strings.Join(ids, " ")
+}
+
+// NormalizePDBIDList は PDB ID を大文字にし、重複を除く（最初に現れた順を保つ）
+func NormalizePDBIDList(ids []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if id != "" && !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// InvalidPDBIDsError は PDB ID として不正なトークンの一覧
+type InvalidPDBIDsError struct {
+	IDs []string
+}
+
+func (e *InvalidPDBIDsError) Error() string {
+	return fmt.Sprintf("invalid PDB IDs in negative_pdbid: %s", strings.Join(e.IDs, ", "))
+}
+
+// InvalidUniProtIDsError は UniProt accession として不正なトークンの一覧
+type InvalidUniProtIDsError struct {
+	IDs []string
+}
+
+func (e *InvalidUniProtIDsError) Error() string {
+	return fmt.Sprintf("invalid UniProt IDs: %s", strings.Join(e.IDs, ", "))
+}
+
+// AnalysisMethods は method に指定できる構造決定手法
+var AnalysisMethods = []string{"X-ray", "NMR", "EM"}
+
+// priority に指定できる値
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// AnalysisPriorities は priority に指定できる値
+var AnalysisPriorities = []string{PriorityLow, PriorityNormal, PriorityHigh}
+
+// methodAliases は Python 側でも受け付ける別名
+var methodAliases = map[string]bool{"X-ray diffraction": true}
+
+// Validate はジョブ作成前にパラメータを検証
+// 未指定（nil）の項目はデフォルト値で補完されるので検証しない。明示的に不正な値はすべてまとめてエラーにする
+func (p AnalysisParams) Validate() error {
+	ids := SplitUniProtIDs(p.UniProtIDs)
+	if len(ids) == 0 {
+		return fmt.Errorf("no UniProt IDs provided")
+	}
+
+	var errs []error
+
+	var invalid []string
+	for _, id := range ids {
+		if !uniProtAccessionPattern.MatchString(id) {
+			invalid = append(invalid, id)
+		}
+	}
+	if len(invalid) > 0 {
+		errs = append(errs, &InvalidUniProtIDsError{IDs: invalid})
+	}
+
+	var invalidPDB []string
+	if p.NegativePDBID != nil {
+		for _, id := range SplitPDBIDs(*p.NegativePDBID) {
+			if !pdbIDPattern.MatchString(id) {
+				invalidPDB = append(invalidPDB, id)
+			}
+		}
+	}
+	for _, id := range p.PDBIDs {
+		if !pdbIDPattern.MatchString(strings.TrimSpace(id)) {
+			invalidPDB = append(invalidPDB, id)
+		}
+	}
+	if len(invalidPDB) > 0 {
+		errs = append(errs, &InvalidPDBIDsError{IDs: invalidPDB})
+	}
+	// 指定した PDB エントリは特定のタンパク質のものなので、複数の UniProt ID には使えない
+	if len(p.PDBIDs) > 0 && len(ids) > 1 {
+		errs = append(errs, fmt.Errorf("pdb_ids can only be used with a single UniProt ID: got %d", len(ids)))
+	}
+
+	if p.Method != nil && *p.Method != "" && !isAnalysisMethod(*p.Method) {
+		errs = append(errs, fmt.Errorf("method must be one of %s: %q", strings.Join(AnalysisMethods, ", "), *p.Method))
+	}
+	if p.Priority != nil && *p.Priority != "" && !isAnalysisPriority(*p.Priority) {
+		errs = append(errs, fmt.Errorf("priority must be one of %s: %q", strings.Join(AnalysisPriorities, ", "), *p.Priority))
+	}
+	if p.SeqRatio != nil && (math.IsNaN(*p.SeqRatio) || *p.SeqRatio <= 0 || *p.SeqRatio > 1) {
+		errs = append(errs, fmt.Errorf("seq_ratio must be in (0, 1]: %v", *p.SeqRatio))
+	}
+	if p.CisThreshold != nil && (math.IsNaN(*p.CisThreshold) || *p.CisThreshold <= 0) {
+		errs = append(errs, fmt.Errorf("cis_threshold must be > 0: %v", *p.CisThreshold))
+	}
+	if p.ReferenceOffset != nil && *p.ReferenceOffset < 0 {
+		errs = append(errs, fmt.Errorf("reference_offset must be >= 0: %d", *p.ReferenceOffset))
+	}
+
+	if p.CallbackURL != nil {
+		u, err := url.Parse(*p.CallbackURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("callback_url must be an absolute http(s) URL: %q", *p.CallbackURL))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate は AnalysisParams の Validate と同じ範囲で既定値を検証する
+func (d AnalysisDefaults) Validate() error {
+	var errs []error
+	if !isAnalysisMethod(d.Method) {
+		errs = append(errs, fmt.Errorf("method must be one of %s: %q", strings.Join(AnalysisMethods, ", "), d.Method))
+	}
+	if math.IsNaN(d.SeqRatio) || d.SeqRatio <= 0 || d.SeqRatio > 1 {
+		errs = append(errs, fmt.Errorf("seq_ratio must be in (0, 1]: %v", d.SeqRatio))
+	}
+	if math.IsNaN(d.CisThreshold) || d.CisThreshold <= 0 {
+		errs = append(errs, fmt.Errorf("cis_threshold must be > 0: %v", d.CisThreshold))
+	}
+	return errors.Join(errs...)
+}
+
+func isAnalysisMethod(method string) bool {
+	for _, m := range AnalysisMethods {
+		if method == m {
+			return true
+		}
+	}
+	return methodAliases[method]
+}
+
+func isAnalysisPriority(priority string) bool {
+	for _, p := range AnalysisPriorities {
+		if priority == p {
+			return true
+		}
+	}
+	return false
+}
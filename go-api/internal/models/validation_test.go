@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAnalysisParamsValidate(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	str := func(v string) *string { return &v }
+	n := func(v int) *int { return &v }
+
+	cases := []struct {
+		name    string
+		params  AnalysisParams
+		wantErr bool
+	}{
+		{"defaults", AnalysisParams{UniProtIDs: "P12345"}, false},
+		{"explicit valid", AnalysisParams{UniProtIDs: "P12345", SeqRatio: f(1), CisThreshold: f(3.3), Method: str("NMR")}, false},
+		{"method alias", AnalysisParams{UniProtIDs: "P12345", Method: str("X-ray diffraction")}, false},
+		{"seq_ratio too large", AnalysisParams{UniProtIDs: "P12345", SeqRatio: f(20)}, true},
+		{"seq_ratio zero", AnalysisParams{UniProtIDs: "P12345", SeqRatio: f(0)}, true},
+		{"cis_threshold negative", AnalysisParams{UniProtIDs: "P12345", CisThreshold: f(-1)}, true},
+		{"reference_offset", AnalysisParams{UniProtIDs: "P12345", ReferenceOffset: n(20)}, false},
+		{"reference_offset negative", AnalysisParams{UniProtIDs: "P12345", ReferenceOffset: n(-1)}, true},
+		{"unknown method", AnalysisParams{UniProtIDs: "P12345", Method: str("cryo")}, true},
+		{"no ids", AnalysisParams{UniProtIDs: " , "}, true},
+		{"negative_pdbid valid", AnalysisParams{UniProtIDs: "P12345", NegativePDBID: str("1abc, 2XYZ 3d4e")}, false},
+		{"negative_pdbid empty", AnalysisParams{UniProtIDs: "P12345", NegativePDBID: str("")}, false},
+		{"negative_pdbid too long", AnalysisParams{UniProtIDs: "P12345", NegativePDBID: str("1abcd")}, true},
+		{"negative_pdbid no digit", AnalysisParams{UniProtIDs: "P12345", NegativePDBID: str("xyz1")}, true},
+		{"pdb_ids", AnalysisParams{UniProtIDs: "P12345", PDBIDs: []string{"1abc", "2XYZ"}}, false},
+		{"pdb_ids invalid", AnalysisParams{UniProtIDs: "P12345", PDBIDs: []string{"1abc", "xyz"}}, true},
+		{"pdb_ids with several UniProt IDs", AnalysisParams{UniProtIDs: "P12345 Q67890", PDBIDs: []string{"1abc"}}, true},
+		{"priority", AnalysisParams{UniProtIDs: "P12345", Priority: str("high")}, false},
+		{"unknown priority", AnalysisParams{UniProtIDs: "P12345", Priority: str("urgent")}, true},
+	}
+	for _, tc := range cases {
+		if err := tc.params.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: got err=%v, wantErr=%v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestAnalysisParamsValidateReportsAllProblems(t *testing.T) {
+	seqRatio := 20.0
+	params := AnalysisParams{UniProtIDs: "P12345 nope", SeqRatio: &seqRatio}
+
+	err := params.Validate()
+	var invalidIDs *InvalidUniProtIDsError
+	if !errors.As(err, &invalidIDs) || len(invalidIDs.IDs) != 1 || invalidIDs.IDs[0] != "nope" {
+		t.Fatalf("got %v, want InvalidUniProtIDsError{nope}", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "seq_ratio") || !strings.Contains(msg, "nope") {
+		t.Errorf("error should mention every problem: %v", err)
+	}
+}
+
+func TestInvalidPDBIDsAreListed(t *testing.T) {
+	negative := "1abc xyz,1abcd 2def"
+	err := AnalysisParams{UniProtIDs: "P12345", NegativePDBID: &negative}.Validate()
+
+	var invalid *InvalidPDBIDsError
+	if !errors.As(err, &invalid) || strings.Join(invalid.IDs, ",") != "xyz,1abcd" {
+		t.Fatalf("got %v, want InvalidPDBIDsError{xyz, 1abcd}", err)
+	}
+	if got := NormalizePDBIDs(" 1abc,2def  3GHI "); got != "1ABC 2DEF 3GHI" {
+		t.Errorf("NormalizePDBIDs: got %q", got)
+	}
+	if got := NormalizePDBIDList([]string{"1abc", " 2DEF", "1ABC", ""}); strings.Join(got, " ") != "1ABC 2DEF" {
+		t.Errorf("NormalizePDBIDList: got %q", got)
+	}
+}
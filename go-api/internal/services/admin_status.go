@@ -0,0 +1,40 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// AdminStatus は実行中のジョブ（開始時刻と経過時間）、キュー長、実行枠、ストレージ使用量を返す
+func (s *JobService) AdminStatus() (*models.AdminStatus, error) {
+	now := time.Now()
+	s.mu.RLock()
+	running := make([]models.RunningJob, 0, len(s.running))
+	for jobID, startedAt := range s.running {
+		running = append(running, models.RunningJob{
+			JobID:          jobID,
+			StartedAt:      startedAt,
+			ElapsedSeconds: now.Sub(startedAt).Seconds(),
+		})
+	}
+	queued := len(s.waiting)
+	s.mu.RUnlock()
+	sort.Slice(running, func(i, j int) bool { return running[i].StartedAt.Before(running[j].StartedAt) })
+
+	free, total, err := diskUsage(s.storageDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat storage: %w", err)
+	}
+
+	return &models.AdminStatus{
+		RunningJobs:       running,
+		QueueDepth:        queued,
+		MaxConcurrency:    cap(s.workers),
+		StorageUsedBytes:  total - free,
+		StorageFreeBytes:  free,
+		StorageTotalBytes: total,
+	}, nil
+}
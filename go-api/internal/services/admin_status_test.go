@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestAdminStatus(t *testing.T) {
+	runner := &FakeRunner{Block: true}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	if err := s.SetMaxConcurrent(1); err != nil {
+		t.Fatalf("SetMaxConcurrent: %v", err)
+	}
+
+	first, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	second, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P67890"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+
+	// 片方が実行中、もう片方が実行枠待ちになるまで待つ（どちらが先に枠を取るかは決まらない）
+	var status *models.AdminStatus
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if status, err = s.AdminStatus(); err != nil {
+			t.Fatalf("AdminStatus: %v", err)
+		}
+		if len(status.RunningJobs) == 1 && status.QueueDepth == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(status.RunningJobs) != 1 || (status.RunningJobs[0].JobID != first.JobID && status.RunningJobs[0].JobID != second.JobID) {
+		t.Fatalf("running_jobs = %+v, want one of the created jobs", status.RunningJobs)
+	}
+	if job := status.RunningJobs[0]; job.StartedAt.IsZero() || job.ElapsedSeconds < 0 {
+		t.Errorf("running job = %+v", job)
+	}
+	if status.QueueDepth != 1 || status.MaxConcurrency != 1 {
+		t.Errorf("queue_depth = %d, max_concurrency = %d, want 1 and 1", status.QueueDepth, status.MaxConcurrency)
+	}
+	if status.StorageTotalBytes == 0 || status.StorageUsedBytes+status.StorageFreeBytes != status.StorageTotalBytes {
+		t.Errorf("storage used %d + free %d != total %d", status.StorageUsedBytes, status.StorageFreeBytes, status.StorageTotalBytes)
+	}
+
+	for _, jobID := range []string{first.JobID, second.JobID} {
+		if err := s.CancelJob(jobID); err != nil {
+			t.Fatalf("CancelJob: %v", err)
+		}
+	}
+	waitForStatus(t, s, status.RunningJobs[0].JobID)
+	if status, err := s.AdminStatus(); err != nil || len(status.RunningJobs) != 0 {
+		t.Errorf("after cancel: running_jobs = %+v (err %v), want none", status, err)
+	}
+}
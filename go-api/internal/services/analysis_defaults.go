@@ -0,0 +1,54 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// defaultAnalysisDefaults は設定ファイルが無い場合の既定値
+var defaultAnalysisDefaults = models.AnalysisDefaults{
+	Method:       "X-ray",
+	SeqRatio:     0.2,
+	CisThreshold: 3.3,
+	Export:       true,
+	Heatmap:      true,
+	ProcCis:      true,
+	Overwrite:    true,
+}
+
+// LoadAnalysisDefaults は既定値の JSON ファイル（{"seq_ratio": 0.3} のように AnalysisDefaults の一部）を読む
+// ファイルに無い項目は組み込みの既定値のまま。知らない項目や不正な値はエラーにする
+func LoadAnalysisDefaults(path string) (models.AnalysisDefaults, error) {
+	defaults := defaultAnalysisDefaults
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return defaults, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&defaults); err != nil {
+		return defaults, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := defaults.Validate(); err != nil {
+		return defaults, fmt.Errorf("invalid defaults in %s: %w", path, err)
+	}
+	return defaults, nil
+}
+
+// SetAnalysisDefaults は未指定のパラメータに入れる値を設定（起動時にのみ呼ぶ）
+func (s *JobService) SetAnalysisDefaults(defaults models.AnalysisDefaults) error {
+	if err := defaults.Validate(); err != nil {
+		return err
+	}
+	s.defaults = defaults
+	return nil
+}
+
+// AnalysisDefaults は未指定のパラメータに入れる値を返す
+func (s *JobService) AnalysisDefaults() models.AnalysisDefaults {
+	return s.defaults
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestLoadAnalysisDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "defaults.json")
+	writeFile(t, path, `{"method": "NMR", "seq_ratio": 0.3, "heatmap": false}`)
+
+	got, err := LoadAnalysisDefaults(path)
+	if err != nil {
+		t.Fatalf("LoadAnalysisDefaults: %v", err)
+	}
+	// ファイルに無い項目は組み込みの既定値のまま
+	want := defaultAnalysisDefaults
+	want.Method, want.SeqRatio, want.Heatmap = "NMR", 0.3, false
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	for _, content := range []string{`{"seq_ratio": 2}`, `{"method": "cryo"}`, `{"seq_ration": 0.3}`, `{`} {
+		writeFile(t, path, content)
+		if _, err := LoadAnalysisDefaults(path); err == nil {
+			t.Errorf("%s: expected error", content)
+		}
+	}
+}
+
+func TestApplyDefaultParamsUsesConfiguredDefaults(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	defaults := defaultAnalysisDefaults
+	defaults.SeqRatio, defaults.ProcCis = 0.35, false
+	if err := s.SetAnalysisDefaults(defaults); err != nil {
+		t.Fatalf("SetAnalysisDefaults: %v", err)
+	}
+
+	cisThreshold := 4.0
+	params := s.applyDefaultParams(models.AnalysisParams{UniProtIDs: "P12345", CisThreshold: &cisThreshold})
+	if *params.SeqRatio != 0.35 || *params.ProcCis || *params.Method != "X-ray" {
+		t.Errorf("got seq_ratio=%v proc_cis=%v method=%q, want 0.35/false/X-ray", *params.SeqRatio, *params.ProcCis, *params.Method)
+	}
+	// 指定した値は既定値で上書きしない
+	if *params.CisThreshold != 4.0 {
+		t.Errorf("cis_threshold = %v, want 4.0", *params.CisThreshold)
+	}
+
+	defaults.SeqRatio = 0
+	if err := s.SetAnalysisDefaults(defaults); err == nil {
+		t.Error("SetAnalysisDefaults with seq_ratio 0: expected error")
+	}
+}
+
+func TestConvertSummaryCSVWithoutParamsUsesConfiguredDefaults(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	defaults := defaultAnalysisDefaults
+	defaults.Method, defaults.CisThreshold = "NMR", 4.0
+	if err := s.SetAnalysisDefaults(defaults); err != nil {
+		t.Fatalf("SetAnalysisDefaults: %v", err)
+	}
+
+	// params.json の無い古いジョブ
+	jobDir := filepath.Join(s.StorageDir(), "job")
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(jobDir, "summary.csv"), "uniprotid,seq_ratio,Entries,Length\nP12345,0.2,3,3\n")
+	writeFile(t, filepath.Join(jobDir, "trimsequence_P12345.csv"), "P12345,1A00 A\nALA,ALA\nGLY,GLY\n")
+
+	result, err := s.convertSummaryCSVToResult("job")
+	if err != nil {
+		t.Fatalf("convertSummaryCSVToResult: %v", err)
+	}
+	if result.Method != "NMR" || result.CisInfo.Threshold != 4.0 {
+		t.Errorf("got method=%q cis threshold=%v, want NMR/4.0", result.Method, result.CisInfo.Threshold)
+	}
+	if len(result.PerResidueScores) != 2 {
+		t.Errorf("got %d per-residue scores, want 2", len(result.PerResidueScores))
+	}
+}
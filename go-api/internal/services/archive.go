@@ -0,0 +1,61 @@
+package services
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+)
+
+// アーカイブに含めないファイル（ジョブ内部の状態管理用）
+var archiveExcludes = map[string]bool{
+	"status.json":     true,
+	"status.json.tmp": true,
+}
+
+// WriteJobArchive はジョブの全ファイルを ZIP として w に書き出す（メモリに全体を溜めない）
+func (s *JobService) WriteJobArchive(jobID string, w io.Writer) error {
+	if !IsValidJobID(jobID) {
+		return fmt.Errorf("invalid job id: %s", jobID)
+	}
+
+	files, err := s.storage.List(jobID)
+	if err != nil {
+		return fmt.Errorf("failed to list job files: %w", err)
+	}
+
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		if archiveExcludes[f.Name] {
+			continue
+		}
+		if err := s.writeArchiveEntry(zw, jobID, f); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// writeArchiveEntry は1ファイル分のエントリを書き出す
+func (s *JobService) writeArchiveEntry(zw *zip.Writer, jobID string, f FileInfo) error {
+	header := &zip.FileHeader{
+		Name:     f.Name,
+		Method:   zip.Deflate,
+		Modified: f.ModTime,
+	}
+	header.SetMode(0o644)
+
+	entry, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	file, err := s.storage.Open(jobID, f.Name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(entry, file)
+	return err
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sanitizeFilenameComponent はファイル名に使えない文字を "_" に置き換える
+func sanitizeFilenameComponent(s string) string {
+	return unsafeFilenameChars.ReplaceAllString(s, "_")
+}
+
+// ArtifactFilename はダウンロード用のファイル名を生成
+// 例: P12345_sr0.2_heatmap.png（パラメータが読めない場合は {job_id}_heatmap.png）
+func (s *JobService) ArtifactFilename(jobID, artifact, ext string) string {
+	name := sanitizeFilenameComponent(jobID)
+
+	if params, err := s.GetJobParams(jobID); err == nil && params.UniProtIDs != "" {
+		name = sanitizeFilenameComponent(params.UniProtIDs)
+		if params.SeqRatio != nil {
+			name += "_sr" + sanitizeFilenameComponent(strconv.FormatFloat(*params.SeqRatio, 'g', -1, 64))
+		}
+	}
+
+	return fmt.Sprintf("%s_%s.%s", name, sanitizeFilenameComponent(artifact), ext)
+}
+
+// OpenHeatmapImage はヒートマップ PNG を開く
+// 標準の名前が無ければ Notebook DSA 形式 {uniprotid}_{seq_ratio}_heatmap.png を探す
+func (s *JobService) OpenHeatmapImage(jobID string) (io.ReadCloser, FileInfo, error) {
+	return s.OpenArtifact(jobID, s.layout.Heatmap, s.layout.HeatmapSuffix)
+}
+
+// OpenDistanceScorePlot は distance–score プロット PNG を開く
+func (s *JobService) OpenDistanceScorePlot(jobID string) (io.ReadCloser, FileInfo, error) {
+	return s.OpenArtifact(jobID, s.layout.DistanceScorePlot, "")
+}
+
+// OpenArtifact はジョブ直下の name を開く。無ければ suffix で終わるファイルを探して開く（suffix が空なら探さない）
+// 例: heatmap.png が無い場合の Notebook DSA 形式 {uniprotid}_{seq_ratio}_heatmap.png
+func (s *JobService) OpenArtifact(jobID, name, suffix string) (io.ReadCloser, FileInfo, error) {
+	if !IsValidJobID(jobID) {
+		return nil, FileInfo{}, fmt.Errorf("%w: invalid job id: %s", ErrJobNotFound, jobID)
+	}
+
+	info, err := s.storage.Stat(jobID, name)
+	if err != nil && suffix != "" {
+		files, listErr := s.storage.List(jobID)
+		if listErr != nil {
+			return nil, FileInfo{}, listErr
+		}
+		for _, f := range files {
+			if !strings.Contains(f.Name, "/") && strings.HasSuffix(f.Name, suffix) {
+				s.logger.Debug("OpenArtifact: found fallback file", "job_id", jobID, "file", f.Name)
+				info, err = f, nil
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, FileInfo{}, err
+	}
+
+	file, err := s.storage.Open(jobID, info.Name)
+	if err != nil {
+		return nil, FileInfo{}, err
+	}
+	return file, info, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// バッチ内で同時に実行できるジョブ数のデフォルト上限
+const defaultBatchConcurrencyCap = 4
+
+// batchJob は実行待ちのバッチ内ジョブ
+type batchJob struct {
+	jobID  string
+	params models.AnalysisParams
+	logger *slog.Logger // job_id / request_id 付きのロガー
+}
+
+// SetBatchConcurrencyCap はバッチ内の同時実行数のサーバー上限を設定
+func (s *JobService) SetBatchConcurrencyCap(n int) error {
+	if n < 1 {
+		return fmt.Errorf("batch concurrency cap must be >= 1: %d", n)
+	}
+	s.batchConcurrencyCap = n
+	return nil
+}
+
+// batchConcurrency はリクエストの concurrency をサーバー上限で丸めた値を返す
+func (s *JobService) batchConcurrency(requested *int) (int, error) {
+	if requested == nil {
+		return s.batchConcurrencyCap, nil
+	}
+	if *requested < 1 {
+		return 0, fmt.Errorf("concurrency must be >= 1: %d", *requested)
+	}
+	if *requested > s.batchConcurrencyCap {
+		return s.batchConcurrencyCap, nil
+	}
+	return *requested, nil
+}
+
+// runBatch はバッチ内のジョブを limit 件ずつ実行し、先行ジョブの終了に合わせて残りを解放
+func (s *JobService) runBatch(jobs []batchJob, limit int) {
+	sem := make(chan struct{}, limit)
+	for _, job := range jobs {
+		sem <- struct{}{}
+		go func(job batchJob) {
+			defer func() { <-sem }()
+			s.executeDSAAnalysis(job.jobID, job.params, job.logger)
+		}(job)
+	}
+}
+
+// BatchProgress はバッチの進捗（done/total）を batch.json と子ジョブのステータスから返す（再起動後も読める）
+// done は終了した（削除済みを含む）ジョブ、running は processing、queued はそれ以外
+func (s *JobService) BatchProgress(batchID string) (*models.BatchProgress, error) {
+	status, err := s.BatchStatus(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BatchProgress{
+		BatchID: batchID,
+		Total:   status.Total,
+		Done:    status.Completed + status.Failed + status.Cancelled + status.Missing,
+		Running: status.Running,
+		Queued:  status.Pending,
+	}, nil
+}
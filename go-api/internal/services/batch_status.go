@@ -0,0 +1,150 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// batchFileName はバッチの子ジョブ一覧を保存するファイル名（保存先の {batch_id}/ 配下）
+const batchFileName = "batch.json"
+
+// ErrBatchNotFound はバッチが存在しない場合のエラー
+var ErrBatchNotFound = errors.New("batch not found")
+
+// batchFile は batch.json の内容
+type batchFile struct {
+	BatchID   string        `json:"batch_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Jobs      []batchMember `json:"jobs"` // 作成順
+}
+
+type batchMember struct {
+	JobID     string `json:"job_id"`
+	UniProtID string `json:"uniprot_id"`
+}
+
+// saveBatch はバッチの子ジョブ一覧を batch.json に保存
+func (s *JobService) saveBatch(batchID string, jobs []models.JobResponse, createdAt time.Time) error {
+	file := batchFile{BatchID: batchID, CreatedAt: createdAt, Jobs: make([]batchMember, 0, len(jobs))}
+	for _, job := range jobs {
+		file.Jobs = append(file.Jobs, batchMember{JobID: job.JobID, UniProtID: job.UniProtID})
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+	if err := writeBytes(s.storage, batchID, batchFileName, data); err != nil {
+		return fmt.Errorf("failed to write batch: %w", err)
+	}
+	return nil
+}
+
+// loadBatch は batch.json を読み込む
+func (s *JobService) loadBatch(batchID string) (*batchFile, error) {
+	if !IsValidJobID(batchID) {
+		return nil, fmt.Errorf("%w: invalid batch id: %s", ErrBatchNotFound, batchID)
+	}
+
+	data, err := s.storage.ReadFile(batchID, batchFileName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrBatchNotFound, batchID)
+		}
+		return nil, fmt.Errorf("failed to read batch: %w", err)
+	}
+
+	var file batchFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse batch: %w", err)
+	}
+	return &file, nil
+}
+
+// BatchJobs はバッチ内の各ジョブの UniProt ID と現在のステータスを作成順に返す
+// 削除済みなどでステータスを読めないジョブは含めない
+func (s *JobService) BatchJobs(batchID string) (*models.BatchJobs, error) {
+	file, err := s.loadBatch(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BatchJobs{BatchID: batchID, Jobs: []models.BatchJobStatus{}}
+	for _, member := range file.Jobs {
+		status, err := s.GetJobStatus(member.JobID)
+		if err != nil {
+			continue
+		}
+		result.Jobs = append(result.Jobs, models.BatchJobStatus{UniProtID: member.UniProtID, JobStatus: *status})
+	}
+	return result, nil
+}
+
+// BatchStatus は子ジョブのステータスを集計して返す（"10 件中 7 件完了、1 件失敗" のような表示用）
+// interrupted（サーバー停止で中断）は failed として数え、削除済みのジョブは missing として数える
+func (s *JobService) BatchStatus(batchID string) (*models.BatchStatus, error) {
+	file, err := s.loadBatch(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BatchStatus{BatchID: batchID, CreatedAt: file.CreatedAt, Total: len(file.Jobs), Jobs: []models.BatchJobStatus{}}
+	for _, member := range file.Jobs {
+		status, err := s.GetJobStatus(member.JobID)
+		if err != nil {
+			result.Missing++
+			continue
+		}
+		switch status.Status {
+		case "completed":
+			result.Completed++
+		case "failed", "interrupted":
+			result.Failed++
+		case "cancelled":
+			result.Cancelled++
+		case "processing":
+			result.Running++
+		default:
+			result.Pending++
+		}
+		result.Jobs = append(result.Jobs, models.BatchJobStatus{UniProtID: member.UniProtID, JobStatus: *status})
+	}
+	return result, nil
+}
+
+// removeOrphanBatches は子ジョブが全て削除されたバッチの batch.json を削除し、削除した batch_id を返す
+func (s *JobService) removeOrphanBatches() ([]string, error) {
+	ids, err := s.storage.Jobs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage: %w", err)
+	}
+
+	removed := []string{}
+	for _, id := range ids {
+		file, err := s.loadBatch(id)
+		if err != nil {
+			continue // ジョブのディレクトリ、または読めないバッチ
+		}
+		orphan := true
+		for _, member := range file.Jobs {
+			if _, err := s.GetJobStatus(member.JobID); !errors.Is(err, ErrJobNotFound) {
+				orphan = false
+				break
+			}
+		}
+		if !orphan {
+			continue
+		}
+		if err := s.storage.Remove(id); err != nil {
+			s.logger.Error("removeOrphanBatches: failed to remove batch", "batch_id", id, "error", err)
+			continue
+		}
+		removed = append(removed, id)
+	}
+	return removed, nil
+}
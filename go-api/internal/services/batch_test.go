@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestCreateJobsOneJobPerUniProtID(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+
+	resp, err := s.CreateJobs(context.Background(), models.AnalysisParams{UniProtIDs: "P12345, Q67890"})
+	if err != nil {
+		t.Fatalf("CreateJobs: %v", err)
+	}
+	if len(resp.Jobs) != 2 || resp.Jobs[0].UniProtID != "P12345" || resp.Jobs[1].UniProtID != "Q67890" {
+		t.Fatalf("unexpected jobs: %+v", resp.Jobs)
+	}
+	for _, job := range resp.Jobs {
+		params, err := s.GetJobParams(job.JobID)
+		if err != nil {
+			t.Fatalf("GetJobParams: %v", err)
+		}
+		if params.UniProtIDs != job.UniProtID {
+			t.Errorf("job %s runs %q, want %q", job.JobID, params.UniProtIDs, job.UniProtID)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		batch, err := s.BatchJobs(resp.BatchID)
+		if err != nil {
+			t.Fatalf("BatchJobs: %v", err)
+		}
+		if len(batch.Jobs) != 2 {
+			t.Fatalf("got %d batch jobs, want 2", len(batch.Jobs))
+		}
+		if batch.Jobs[0].UniProtID != "P12345" || batch.Jobs[0].JobID != resp.Jobs[0].JobID {
+			t.Fatalf("batch jobs are not in creation order: %+v", batch.Jobs)
+		}
+		if batch.Jobs[0].Status == "completed" && batch.Jobs[1].Status == "completed" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("batch did not finish in time: %+v", batch.Jobs)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := s.BatchJobs("00000000-0000-0000-0000-000000000000"); !errors.Is(err, ErrBatchNotFound) {
+		t.Errorf("unknown batch: got %v, want ErrBatchNotFound", err)
+	}
+}
+
+func TestBatchStatusAggregatesPersistedBatch(t *testing.T) {
+	dir := t.TempDir()
+	s := NewJobService(dir, "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+
+	resp, err := s.CreateJobs(context.Background(), models.AnalysisParams{UniProtIDs: "P12345 Q67890"})
+	if err != nil {
+		t.Fatalf("CreateJobs: %v", err)
+	}
+	for _, job := range resp.Jobs {
+		waitForStatus(t, s, job.JobID)
+	}
+
+	// 再起動後（メモリ上のバッチ情報なし）でも batch.json から集計できる
+	restarted := NewJobService(dir, "python3", "", &FakeRunner{}, nil)
+	status, err := restarted.BatchStatus(resp.BatchID)
+	if err != nil {
+		t.Fatalf("BatchStatus: %v", err)
+	}
+	if status.Total != 2 || status.Completed != 2 || status.Failed != 0 || len(status.Jobs) != 2 {
+		t.Errorf("unexpected aggregate: %+v", status)
+	}
+	progress, err := restarted.BatchProgress(resp.BatchID)
+	if err != nil {
+		t.Fatalf("BatchProgress: %v", err)
+	}
+	if *progress != (models.BatchProgress{BatchID: resp.BatchID, Total: 2, Done: 2}) {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+	if _, err := restarted.BatchProgress("00000000-0000-0000-0000-000000000000"); !errors.Is(err, ErrBatchNotFound) {
+		t.Errorf("unknown batch progress: got %v, want ErrBatchNotFound", err)
+	}
+
+	// バッチのディレクトリはジョブとして一覧に出ない
+	jobs, err := restarted.ListJobs("", 0)
+	if err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Errorf("ListJobs returned %d jobs, want 2", len(jobs))
+	}
+
+	// 子ジョブを 1 つ消すと missing に数え、全て消えたら掃除で batch.json も消える
+	if err := restarted.DeleteJob(resp.Jobs[0].JobID); err != nil {
+		t.Fatalf("DeleteJob: %v", err)
+	}
+	status, err = restarted.BatchStatus(resp.BatchID)
+	if err != nil {
+		t.Fatalf("BatchStatus: %v", err)
+	}
+	if status.Missing != 1 || status.Completed != 1 {
+		t.Errorf("after delete: %+v", status)
+	}
+	if removed, _ := restarted.removeOrphanBatches(); len(removed) != 0 {
+		t.Errorf("batch with a remaining job was removed: %v", removed)
+	}
+
+	if err := restarted.DeleteJob(resp.Jobs[1].JobID); err != nil {
+		t.Fatalf("DeleteJob: %v", err)
+	}
+	if _, err := restarted.CleanupExpired(time.Hour); err != nil {
+		t.Fatalf("CleanupExpired: %v", err)
+	}
+	if _, err := restarted.BatchStatus(resp.BatchID); !errors.Is(err, ErrBatchNotFound) {
+		t.Errorf("orphan batch was not removed: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrJobNotFound はジョブが存在しない場合のエラー
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobFinished はジョブが既に終了（completed/failed/cancelled/interrupted）している場合のエラー
+	ErrJobFinished = errors.New("job already finished")
+)
+
+// IsTerminalStatus はジョブが終了状態（completed/failed/cancelled/interrupted）かどうかを返す
+func IsTerminalStatus(status string) bool {
+	return status == "completed" || status == "failed" || status == "cancelled" || status == "interrupted"
+}
+
+// registerCancel は実行中ジョブのキャンセル関数を登録
+func (s *JobService) registerCancel(jobID string, cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancels[jobID] = cancel
+}
+
+// unregisterCancel はジョブ終了時にキャンセル関数を解除
+func (s *JobService) unregisterCancel(jobID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cancels, jobID)
+}
+
+// CancelJob はジョブをキャンセルし、ステータスを "cancelled" にする
+// 実行中の Python プロセスはグループごと kill し、途中まで書かれたファイルはデバッグ用に残す
+// 開始前（pending）のジョブは開始時にスキップされる
+func (s *JobService) CancelJob(jobID string) error {
+	status, err := s.GetJobStatus(jobID)
+	if err != nil {
+		return err
+	}
+	if IsTerminalStatus(status.Status) {
+		return fmt.Errorf("%w: %s is %s", ErrJobFinished, jobID, status.Status)
+	}
+
+	// 確認してから書くまでの間に終了したジョブは ErrJobFinished になる
+	// 先に cancelled を書くので、この後にキャンセル関数を登録するジョブは登録後の確認で止まる
+	if err := s.updateJobStatus(jobID, "cancelled", status.Progress, "Job cancelled by user"); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	cancel, ok := s.cancels[jobID]
+	s.mu.RUnlock()
+	if ok {
+		cancel()
+	}
+	return nil
+}
+
+// CancelJobs は指定ステータスに一致するジョブをすべてキャンセルし、キャンセルした job_id を返す
+func (s *JobService) CancelJobs(status string) ([]string, error) {
+	if status == "" {
+		return nil, fmt.Errorf("status filter is required")
+	}
+
+	jobs, err := s.ListJobs(status, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled := []string{}
+	for _, job := range jobs {
+		if err := s.CancelJob(job.JobID); err != nil {
+			// 一覧取得後に終了したジョブは対象外
+			continue
+		}
+		cancelled = append(cancelled, job.JobID)
+	}
+
+	return cancelled, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// GetCisInfo は完了したジョブの cis ペプチド結合の統計と、cis ペアごとの残基名を返す
+// result.json からは uniprot_id と cis_info だけを読む（ペアスコアやヒートマップは組み立てない）
+// result.json が無い古いジョブは GetResult で CSV から組み立てる
+func (s *JobService) GetCisInfo(jobID string) (*models.JobCisInfo, error) {
+	status, err := s.GetJobStatus(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Status != "completed" {
+		return nil, &JobNotCompletedError{Status: status.Status}
+	}
+
+	var cis struct {
+		UniProtID string         `json:"uniprot_id"`
+		CisInfo   models.CisInfo `json:"cis_info"`
+	}
+	if result, ok := s.resultCache.get(jobID, status.UpdatedAt); ok {
+		cis.UniProtID, cis.CisInfo = result.UniProtID, result.CisInfo
+	} else if data, err := s.storage.ReadFile(jobID, "result.json"); err == nil {
+		if err := json.Unmarshal(replaceNonFiniteLiterals(data), &cis); err != nil {
+			return nil, fmt.Errorf("failed to parse result: %w", err)
+		}
+	} else if errors.Is(err, fs.ErrNotExist) {
+		result, err := s.GetResult(jobID)
+		if err != nil {
+			return nil, err
+		}
+		cis.UniProtID, cis.CisInfo = result.UniProtID, result.CisInfo
+	} else {
+		return nil, fmt.Errorf("failed to read result: %w", err)
+	}
+
+	// 残基名は trimsequence の先頭列（UniProt 配列）から引く（読めなければ番号のみ）
+	sequence, err := s.readJobTrimSequence(jobID, s.layout.TrimSequence(cis.UniProtID))
+	if err != nil {
+		s.logger.Debug("GetCisInfo: trimsequence not available", "job_id", jobID, "error", err)
+	}
+
+	resp := &models.JobCisInfo{
+		JobID:     jobID,
+		UniProtID: cis.UniProtID,
+		CisInfo:   cis.CisInfo,
+		Pairs:     make([]models.CisPair, 0, len(cis.CisInfo.CisPairs)),
+	}
+	if resp.CisInfo.CisPairs == nil {
+		resp.CisInfo.CisPairs = []string{}
+	}
+	for _, pairStr := range cis.CisInfo.CisPairs {
+		pair, ok := parseCisPair(pairStr)
+		if !ok {
+			s.logger.Warn("GetCisInfo: malformed cis pair", "job_id", jobID, "pair", pairStr)
+			continue
+		}
+		pair.ResidueI = residueName(sequence, pair.I)
+		pair.ResidueJ = residueName(sequence, pair.J)
+		resp.Pairs = append(resp.Pairs, pair)
+	}
+	return resp, nil
+}
+
+// parseCisPair は CisInfo.CisPairs の要素（"1, 2" 形式、1-based）を読む
+func parseCisPair(s string) (models.CisPair, bool) {
+	first, second, ok := strings.Cut(s, ",")
+	if !ok {
+		return models.CisPair{}, false
+	}
+	i, err1 := strconv.Atoi(strings.TrimSpace(first))
+	j, err2 := strconv.Atoi(strings.TrimSpace(second))
+	if err1 != nil || err2 != nil {
+		return models.CisPair{}, false
+	}
+	return models.CisPair{I: i, J: j}, true
+}
+
+// residueName は 1-based の残基番号の 3 文字コードを返す（配列上の位置が得られなければ空文字）
+func residueName(sequence []string, residueNum int) string {
+	if residueNum >= 1 && residueNum <= len(sequence) {
+		if code := strings.ToUpper(sequence[residueNum-1]); code != "NA" {
+			return code
+		}
+	}
+	return ""
+}
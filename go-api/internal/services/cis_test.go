@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// cisRow は cis CSV の 1 行（0 列目がペア、15-17 列目が距離とスコア、18-19 列目が cis/trans の構造数）
+func cisRow(pair, residues string, cisCnt, transCnt string) string {
+	cols := make([]string, 20)
+	cols[0], cols[1] = `"`+pair+`"`, `"`+residues+`"`
+	cols[15], cols[16], cols[17] = "2.9", "0.1", "0.5"
+	cols[18], cols[19] = cisCnt, transCnt
+	return strings.Join(cols, ",") + "\n"
+}
+
+func TestGetCisInfo(t *testing.T) {
+	files := summaryFixture()
+	files["P12345_0.2_cis_nor+sub.csv"] = strings.Repeat("c,", 19) + "c\n" +
+		cisRow("1, 2", "ALA-1, GLY-2", "3", "0") +
+		cisRow("2, 3", "GLY-2, SER-3", "1", "2") // mix（全構造で cis ではない）
+	s, jobID := runStructuresJob(t, files)
+
+	got, err := s.GetCisInfo(jobID)
+	if err != nil {
+		t.Fatalf("GetCisInfo: %v", err)
+	}
+	if got.JobID != jobID || got.UniProtID != "P12345" {
+		t.Errorf("unexpected ids: %+v", got)
+	}
+	if len(got.CisInfo.CisPairs) != 1 || got.CisInfo.CisPairs[0] != "1, 2" {
+		t.Errorf("cis_pairs = %v, want [1, 2]", got.CisInfo.CisPairs)
+	}
+	want := models.CisPair{I: 1, J: 2, ResidueI: "ALA", ResidueJ: "GLY"}
+	if len(got.Pairs) != 1 || got.Pairs[0] != want {
+		t.Errorf("pairs = %+v, want [%+v]", got.Pairs, want)
+	}
+}
+
+func TestGetCisInfoNotCompleted(t *testing.T) {
+	s, jobID := runFailedJob(t, summaryFixture())
+	if _, err := s.GetCisInfo(jobID); !errors.Is(err, ErrJobNotCompleted) {
+		t.Errorf("got %v, want ErrJobNotCompleted", err)
+	}
+	if _, err := s.GetCisInfo("missing-job"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("got %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestParseCisPair(t *testing.T) {
+	if got, ok := parseCisPair("12, 345"); !ok || got.I != 12 || got.J != 345 {
+		t.Errorf("parseCisPair(\"12, 345\") = %+v, %v", got, ok)
+	}
+	for _, s := range []string{"", "12", "a, b"} {
+		if _, ok := parseCisPair(s); ok {
+			t.Errorf("parseCisPair(%q) should fail", s)
+		}
+	}
+}
+
+func TestCisFileWithNonRoundSeqRatio(t *testing.T) {
+	for _, seqRatio := range []float64{0.15, 0.33} {
+		files := summaryFixture()
+		files["summary.csv"] = strings.Replace(files["summary.csv"], "P12345,0.2,", "P12345,"+formatSeqRatio(seqRatio)+",", 1)
+		header := strings.Repeat("c,", 19) + "c\n"
+		files["P12345_"+formatSeqRatio(seqRatio)+"_cis_nor+sub.csv"] = header + cisRow("1, 2", "ALA-1, GLY-2", "3", "0")
+		// 別の seq_ratio の出力が残っていても、summary の seq_ratio に一致する方を使う
+		files["P12345_0.2_cis_nor+sub.csv"] = header + cisRow("2, 3", "GLY-2, SER-3", "3", "0")
+		runner := &FakeRunner{Files: files}
+		s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+		job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345", SeqRatio: &seqRatio})
+		if err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+		waitForStatus(t, s, job.JobID)
+
+		// CLI にもファイル名と同じ書き方で渡す
+		if got := argValue(runner.Calls()[0], "--seq-ratio"); got != formatSeqRatio(seqRatio) {
+			t.Errorf("seq_ratio %v: --seq-ratio %q, want %q", seqRatio, got, formatSeqRatio(seqRatio))
+		}
+		got, err := s.GetCisInfo(job.JobID)
+		if err != nil {
+			t.Fatalf("seq_ratio %v: GetCisInfo: %v", seqRatio, err)
+		}
+		if len(got.CisInfo.CisPairs) != 1 || got.CisInfo.CisPairs[0] != "1, 2" {
+			t.Errorf("seq_ratio %v: cis_pairs = %v, want [1, 2]", seqRatio, got.CisInfo.CisPairs)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// cleanupInterval は期限切れジョブを掃除する間隔
+const cleanupInterval = time.Hour
+
+// ErrJobNotExpired は削除対象にできないジョブ（実行中など）の場合のエラー
+var ErrJobNotExpired = errors.New("job is not eligible for cleanup")
+
+// SetJobTTL は終了したジョブを保持する期間を設定（0 で自動削除しない）
+func (s *JobService) SetJobTTL(ttl time.Duration) error {
+	if ttl < 0 {
+		return fmt.Errorf("job TTL must be >= 0: %s", ttl)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobTTL = ttl
+	return nil
+}
+
+// JobTTL は終了したジョブを保持する期間を返す
+func (s *JobService) JobTTL() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.jobTTL
+}
+
+// StartCleanup は ctx が終わるまで定期的に CleanupExpired を実行する（TTL が 0 なら何もしない）
+func (s *JobService) StartCleanup(ctx context.Context) {
+	if s.JobTTL() == 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+		for {
+			if _, err := s.CleanupExpired(s.JobTTL()); err != nil {
+				s.logger.Error("StartCleanup: cleanup failed", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// CleanupExpired は最終更新から ttl 以上経過した終了済みジョブのディレクトリを削除し、削除した job_id を返す
+func (s *JobService) CleanupExpired(ttl time.Duration) ([]string, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ttl must be positive: %s", ttl)
+	}
+
+	jobs, err := s.ListJobs("", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-ttl)
+	removed := []string{}
+	for _, job := range jobs {
+		if !IsTerminalStatus(job.Status) || job.UpdatedAt.After(cutoff) {
+			continue
+		}
+		if err := s.removeJob(job.JobID, cutoff); err != nil {
+			if !errors.Is(err, ErrJobNotExpired) {
+				s.logger.Error("CleanupExpired: failed to remove job", "job_id", job.JobID, "error", err)
+			}
+			continue
+		}
+		s.logger.Info("CleanupExpired: removed job", "job_id", job.JobID, "status", job.Status, "updated_at", job.UpdatedAt)
+		removed = append(removed, job.JobID)
+	}
+
+	// 子ジョブが全て消えたバッチの batch.json も片付ける
+	if batches, err := s.removeOrphanBatches(); err != nil {
+		s.logger.Error("CleanupExpired: failed to remove orphan batches", "error", err)
+	} else if len(batches) > 0 {
+		s.logger.Info("CleanupExpired: removed orphan batches", "batch_ids", batches)
+	}
+
+	return removed, nil
+}
+
+// removeJob はジョブが終了済みで cutoff より前に更新されたものであることをロック下で再確認してから削除する
+// 実行中・実行待ちのジョブは決して削除しない
+func (s *JobService) removeJob(jobID string, cutoff time.Time) error {
+	s.mu.Lock()
+	status, err := s.readStatus(jobID)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	_, active := s.cancels[jobID]
+	s.mu.Unlock()
+	if active || !IsTerminalStatus(status.Status) || status.UpdatedAt.After(cutoff) {
+		return fmt.Errorf("%w: %s is %s", ErrJobNotExpired, jobID, status.Status)
+	}
+
+	return s.purgeJob(jobID)
+}
+
+// DeleteJob は終了済みのジョブを TTL を待たずに保存先から削除する
+// 実行中・実行待ちのジョブは ErrJobInProgress を返す
+func (s *JobService) DeleteJob(jobID string) error {
+	s.mu.Lock()
+	status, err := s.readStatus(jobID)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	_, active := s.cancels[jobID]
+	s.mu.Unlock()
+	if active || !IsTerminalStatus(status.Status) {
+		return fmt.Errorf("%w: %s is %s", ErrJobInProgress, jobID, status.Status)
+	}
+
+	return s.purgeJob(jobID)
+}
+
+// purgeJob はジョブの全ファイルとキャッシュ済みの結果を削除する
+// 終了済みのジョブはもう更新されないので、時間のかかる削除はロック外で行う
+// Storage.Remove は最初にジョブを一覧から見えなくするので、削除途中の状態は読まれない
+func (s *JobService) purgeJob(jobID string) error {
+	if err := s.storage.Remove(jobID); err != nil {
+		return err
+	}
+	s.resultCache.remove(jobID)
+	return nil
+}
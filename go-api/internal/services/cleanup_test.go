@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestCleanupExpiredRemovesOnlyOldFinishedJobs(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+
+	// ジョブディレクトリを直接作る（old: 古い完了ジョブ、running: 古い実行中ジョブ、recent: 新しい完了ジョブ）
+	old := time.Now().Add(-100 * time.Hour)
+	mk := func(status string, updatedAt time.Time) string {
+		job, _, err := s.prepareJob(models.AnalysisParams{UniProtIDs: "P12345"})
+		if err != nil {
+			t.Fatalf("prepareJob: %v", err)
+		}
+		if err := s.saveJobStatus(job.JobID, models.JobStatus{JobID: job.JobID, Status: status, CreatedAt: updatedAt, UpdatedAt: updatedAt}); err != nil {
+			t.Fatalf("saveJobStatus: %v", err)
+		}
+		return job.JobID
+	}
+	expired := mk("completed", old)
+	running := mk("processing", old)
+	recent := mk("failed", time.Now())
+
+	removed, err := s.CleanupExpired(72 * time.Hour)
+	if err != nil {
+		t.Fatalf("CleanupExpired: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != expired {
+		t.Fatalf("got removed %v, want [%s]", removed, expired)
+	}
+
+	for jobID, wantExists := range map[string]bool{expired: false, running: true, recent: true} {
+		_, err := os.Stat(filepath.Join(s.StorageDir(), jobID))
+		if exists := err == nil; exists != wantExists {
+			t.Errorf("job %s: exists=%v, want %v", jobID, exists, wantExists)
+		}
+	}
+}
+
+func TestDeleteJob(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+
+	mk := func(status string) string {
+		job, _, err := s.prepareJob(models.AnalysisParams{UniProtIDs: "P12345"})
+		if err != nil {
+			t.Fatalf("prepareJob: %v", err)
+		}
+		now := time.Now()
+		if err := s.saveJobStatus(job.JobID, models.JobStatus{JobID: job.JobID, Status: status, CreatedAt: now, UpdatedAt: now}); err != nil {
+			t.Fatalf("saveJobStatus: %v", err)
+		}
+		return job.JobID
+	}
+	finished := mk("completed")
+	running := mk("processing")
+
+	if err := s.DeleteJob(finished); err != nil {
+		t.Fatalf("DeleteJob: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.StorageDir(), finished)); !os.IsNotExist(err) {
+		t.Errorf("job directory still exists: %v", err)
+	}
+	if err := s.DeleteJob(finished); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("second delete: got %v, want ErrJobNotFound", err)
+	}
+	if err := s.DeleteJob(running); !errors.Is(err, ErrJobInProgress) {
+		t.Errorf("running job: got %v, want ErrJobInProgress", err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package services
+
+// diskUsage は未対応プラットフォームでは常に 0 を返す（判定をスキップ）
+func diskUsage(path string) (free, total uint64, err error) {
+	return 0, 0, nil
+}
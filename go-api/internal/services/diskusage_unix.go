@@ -0,0 +1,14 @@
+//go:build unix
+
+package services
+
+import "syscall"
+
+// diskUsage は path を含むファイルシステムの空き容量と総容量（バイト）を返す
+func diskUsage(path string) (free, total uint64, err error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), st.Blocks * uint64(st.Bsize), nil
+}
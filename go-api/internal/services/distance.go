@@ -0,0 +1,93 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// readJobDistancePairs は Storage 上の距離データCSVを開いて readDistancePairs で読む
+func (s *JobService) readJobDistancePairs(jobID, name string, skip map[string]bool, trimSequence []string) ([]models.PairScore, error) {
+	file, err := s.storage.Open(jobID, name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readDistancePairs(file, skip, trimSequence)
+}
+
+// readDistancePairs は距離データCSVを1行ずつ読み、ペアごとの PairScore を返す
+// フォーマット: residue_num1,residue_num2,distance1,distance2,...（ヘッダーなし）
+// skip に含まれるペア（"i,j"）は読み飛ばす。ファイル全体や1ペアの距離列をメモリに溜めない
+func readDistancePairs(r io.Reader, skip map[string]bool, trimSequence []string) ([]models.PairScore, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var pairScores []models.PairScore
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read distance data: %w", err)
+		}
+		if len(row) < 2 {
+			continue
+		}
+
+		iIdx, err1 := strconv.Atoi(row[0])
+		jIdx, err2 := strconv.Atoi(row[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		if skip[fmt.Sprintf("%d,%d", iIdx, jIdx)] {
+			continue // 既にcisデータから追加済み
+		}
+
+		// Welford 法で平均と分散を逐次計算（3列目以降が距離値）
+		var n int
+		var mean, m2 float64
+		for _, field := range row[2:] {
+			d, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				continue
+			}
+			n++
+			delta := d - mean
+			mean += delta / float64(n)
+			m2 += delta * (d - mean)
+		}
+		if n == 0 {
+			continue
+		}
+		std := math.Sqrt(m2 / float64(n))
+
+		// scoreを計算（mean / std、stdが0の場合は0.0001）
+		score := mean / std
+		if std == 0 {
+			score = mean / 0.0001
+		}
+
+		// 残基ペア名をtrimsequenceから補完（位置が得られない場合はプレースホルダー）
+		residuePair := residueLabel(trimSequence, iIdx) + ", " + residueLabel(trimSequence, jIdx)
+
+		pairScores = append(pairScores, models.PairScore{
+			I:            iIdx,
+			J:            jIdx,
+			ResiduePair:  residuePair,
+			DistanceMean: mean,
+			DistanceStd:  std,
+			Score:        score,
+		})
+	}
+
+	return pairScores, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// GetDistanceScorePoints は距離-スコア散布図の点を PairScores から作って Score 降順で返す
+// maxPoints > 0 かつ点数がそれを超える場合は、Score 順に等間隔で maxPoints 点に間引く（分布の形と最大値は残る）
+func (s *JobService) GetDistanceScorePoints(jobID string, maxPoints int) (*models.DistanceScorePoints, error) {
+	result, err := s.GetResult(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	points := distanceScorePoints(result.PairScores)
+	response := &models.DistanceScorePoints{JobID: jobID, Total: len(points), Points: points}
+	if maxPoints > 0 && len(points) > maxPoints {
+		response.Points = downsamplePoints(points, maxPoints)
+		response.Downsampled = true
+	}
+	return response, nil
+}
+
+// distanceScorePoints は描画できる（距離・スコアとも有限の）ペアを Score 降順に並べる
+func distanceScorePoints(pairs []models.PairScore) []models.DistanceScorePoint {
+	points := make([]models.DistanceScorePoint, 0, len(pairs))
+	for _, ps := range pairs {
+		if !isFinite(ps.DistanceMean) || !isFinite(ps.Score) {
+			continue
+		}
+		points = append(points, models.DistanceScorePoint{
+			I:            ps.I,
+			J:            ps.J,
+			ResiduePair:  ps.ResiduePair,
+			MeanDistance: ps.DistanceMean,
+			Score:        ps.Score,
+		})
+	}
+	sort.SliceStable(points, func(a, b int) bool {
+		return points[a].Score > points[b].Score
+	})
+	return points
+}
+
+// downsamplePoints は並び順を保ったまま等間隔に n 点を選ぶ
+func downsamplePoints(points []models.DistanceScorePoint, n int) []models.DistanceScorePoint {
+	sampled := make([]models.DistanceScorePoint, n)
+	for k := range sampled {
+		sampled[k] = points[k*len(points)/n]
+	}
+	return sampled
+}
+
+// isFinite は v が NaN/Inf でないか
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestGetDistanceScorePoints(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	all, err := s.GetDistanceScorePoints(job.JobID, 0)
+	if err != nil {
+		t.Fatalf("GetDistanceScorePoints: %v", err)
+	}
+	if all.Total != 3 || len(all.Points) != 3 || all.Downsampled {
+		t.Fatalf("got total=%d len=%d downsampled=%v, want 3/3/false", all.Total, len(all.Points), all.Downsampled)
+	}
+	if p := all.Points[0]; p.ResiduePair == "" || p.MeanDistance <= 0 {
+		t.Errorf("point missing pair or distance: %+v", p)
+	}
+	for i := 1; i < len(all.Points); i++ {
+		if all.Points[i-1].Score < all.Points[i].Score {
+			t.Fatalf("points not sorted by score: %+v", all.Points)
+		}
+	}
+
+	sampled, err := s.GetDistanceScorePoints(job.JobID, 2)
+	if err != nil {
+		t.Fatalf("GetDistanceScorePoints: %v", err)
+	}
+	if sampled.Total != 3 || len(sampled.Points) != 2 || !sampled.Downsampled || sampled.Points[0] != all.Points[0] {
+		t.Fatalf("unexpected downsampled points: %+v", sampled)
+	}
+}
+
+func TestDistanceScorePointsSkipsNonFinite(t *testing.T) {
+	points := distanceScorePoints([]models.PairScore{
+		{I: 1, J: 2, DistanceMean: 3.8, Score: 0.1},
+		{I: 1, J: 3, DistanceMean: math.NaN(), Score: 0.5},
+		{I: 2, J: 3, DistanceMean: 6.2, Score: math.Inf(1)},
+		{I: 2, J: 4, DistanceMean: 7.0, Score: 0.9},
+	})
+	if len(points) != 2 || points[0].J != 4 || points[1].J != 2 {
+		t.Fatalf("got %+v, want pairs (2,4) and (1,2)", points)
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// writeDistanceFixture は residues 残基・structures 構造分の距離データCSVを書き出す
+func writeDistanceFixture(tb testing.TB, residues, structures int) string {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "distance_P12345.csv")
+
+	var sb strings.Builder
+	for i := 1; i <= residues; i++ {
+		for j := i + 1; j <= residues; j++ {
+			fmt.Fprintf(&sb, "%d,%d", i, j)
+			for k := 0; k < structures; k++ {
+				fmt.Fprintf(&sb, ",%.3f", float64(j-i)*3.8+float64(k%7)*0.05)
+			}
+			sb.WriteByte('\n')
+		}
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		tb.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+// readDistancePairsFile は path を開いて readDistancePairs で読む
+func readDistancePairsFile(path string, skip map[string]bool, trimSequence []string) ([]models.PairScore, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readDistancePairs(file, skip, trimSequence)
+}
+
+// readDistancePairsReadAll は ReadAll で全行を読み込む従来の実装（ベンチマーク比較用）
+func readDistancePairsReadAll(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	pairs := 0
+	for _, row := range records {
+		var distances []float64
+		for _, field := range row[2:] {
+			if f, err := strconv.ParseFloat(field, 64); err == nil {
+				distances = append(distances, f)
+			}
+		}
+		var sum float64
+		for _, d := range distances {
+			sum += d
+		}
+		mean := sum / float64(len(distances))
+		var variance float64
+		for _, d := range distances {
+			variance += (d - mean) * (d - mean)
+		}
+		_ = math.Sqrt(variance / float64(len(distances)))
+		pairs++
+	}
+	return pairs, nil
+}
+
+func TestReadDistancePairsMatchesTwoPass(t *testing.T) {
+	path := writeDistanceFixture(t, 5, 4)
+	sequence := []string{"ALA", "GLY", "SER", "LYS", "MET"}
+
+	pairs, err := readDistancePairsFile(path, map[string]bool{"1,2": true}, sequence)
+	if err != nil {
+		t.Fatalf("readDistancePairs: %v", err)
+	}
+	if len(pairs) != 9 {
+		t.Fatalf("got %d pairs, want 9 (10 minus 1 skipped)", len(pairs))
+	}
+
+	for _, ps := range pairs {
+		var distances []float64
+		for k := 0; k < 4; k++ {
+			d, _ := strconv.ParseFloat(fmt.Sprintf("%.3f", float64(ps.J-ps.I)*3.8+float64(k%7)*0.05), 64)
+			distances = append(distances, d)
+		}
+		var sum float64
+		for _, d := range distances {
+			sum += d
+		}
+		mean := sum / float64(len(distances))
+		var variance float64
+		for _, d := range distances {
+			variance += (d - mean) * (d - mean)
+		}
+		std := math.Sqrt(variance / float64(len(distances)))
+
+		if math.Abs(ps.DistanceMean-mean) > 1e-9 || math.Abs(ps.DistanceStd-std) > 1e-9 {
+			t.Errorf("pair (%d,%d): got mean=%v std=%v, want %v/%v", ps.I, ps.J, ps.DistanceMean, ps.DistanceStd, mean, std)
+		}
+	}
+	if pairs[0].ResiduePair != "A-1, S-3" {
+		t.Errorf("got residue pair %q, want %q", pairs[0].ResiduePair, "A-1, S-3")
+	}
+}
+
+func BenchmarkReadDistancePairs(b *testing.B) {
+	path := writeDistanceFixture(b, 300, 40)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := readDistancePairsFile(path, nil, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadDistancePairsReadAll(b *testing.B) {
+	path := writeDistanceFixture(b, 300, 40)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := readDistancePairsReadAll(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	// defaultDownloadRetries は PDB などのダウンロード失敗時に Python CLI を再実行する最大回数
+	defaultDownloadRetries = 2
+	// defaultDownloadRetryDelay は最初の再実行までの待ち時間（以降は倍々）
+	defaultDownloadRetryDelay = 10 * time.Second
+)
+
+// transientDownloadPattern は一時的なネットワーク障害による失敗を示す出力
+var transientDownloadPattern = regexp.MustCompile(`(?i)` +
+	`requests\.exceptions\.(ConnectionError|ConnectTimeout|ReadTimeout|Timeout|ChunkedEncodingError)|` +
+	`urllib\.error\.URLError|ConnectionResetError|RemoteDisconnected|IncompleteRead|` +
+	`Max retries exceeded|Temporary failure in name resolution|Connection reset by peer|` +
+	`HTTPError: 5\d\d|\b5\d\d Server Error`)
+
+// deterministicFailurePattern は再実行しても結果が変わらない失敗を示す出力（こちらを優先）
+var deterministicFailurePattern = regexp.MustCompile(`(?i)` +
+	`UniProt ID not found|No entry found in UniProt|\b404 Client Error|No structures? found|NoSuitableStructuresError|InputTooLargeError`)
+
+// SetDownloadRetries はダウンロード失敗時の再実行回数と初回の待ち時間を設定（0 回で再実行しない）
+func (s *JobService) SetDownloadRetries(retries int, delay time.Duration) error {
+	if retries < 0 {
+		return fmt.Errorf("download retries must be >= 0: %d", retries)
+	}
+	if delay <= 0 {
+		return fmt.Errorf("download retry delay must be positive: %s", delay)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.downloadRetries = retries
+	s.downloadRetryDelay = delay
+	return nil
+}
+
+// downloadRetryPolicy は再実行回数と初回の待ち時間を返す
+func (s *JobService) downloadRetryPolicy() (int, time.Duration) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.downloadRetries, s.downloadRetryDelay
+}
+
+// isTransientDownloadFailure は Python CLI の出力が一時的なダウンロード失敗を示すか判定する
+func isTransientDownloadFailure(stdout, stderr string) bool {
+	for _, output := range []string{stderr, stdout} {
+		if deterministicFailurePattern.MatchString(output) {
+			return false
+		}
+	}
+	return transientDownloadPattern.MatchString(stderr) || transientDownloadPattern.MatchString(stdout)
+}
+
+// sleepContext は d だけ待つ。ctx が先に終わった場合は false を返す
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestTransientDownloadFailureIsRetried(t *testing.T) {
+	tests := []struct {
+		name      string
+		stderr    string
+		wantCalls int
+	}{
+		{
+			name:      "network error",
+			stderr:    "Traceback (most recent call last):\n  File \"cif_data.py\", line 20\nrequests.exceptions.ConnectionError: Max retries exceeded with url: /download/1a00.cif\n",
+			wantCalls: 3,
+		},
+		{
+			name:      "unknown UniProt ID",
+			stderr:    "Traceback (most recent call last):\n  File \"uniprot_data.py\", line 50\nKeyError: 'No entry found in UniProt XML for P99999'\n",
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &FakeRunner{Stderr: tt.stderr, Err: errors.New("exit status 1")}
+			s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+			if err := s.SetDownloadRetries(2, time.Millisecond); err != nil {
+				t.Fatalf("SetDownloadRetries: %v", err)
+			}
+
+			job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+			status := waitForStatus(t, s, job.JobID)
+			if status.Status != "failed" || !strings.Contains(status.Message, pythonErrorLine(tt.stderr)) {
+				t.Errorf("got %q %q, want failed with the Python exception", status.Status, status.Message)
+			}
+			if got := len(runner.Calls()); got != tt.wantCalls {
+				t.Errorf("runner called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// structureCheckTimeout は dry run で PDB エントリを照会する Python の check サブコマンドの制限時間
+const structureCheckTimeout = 2 * time.Minute
+
+// structureCheck は check サブコマンドが出力する UniProt ID ごとの結果
+type structureCheck struct {
+	UniProtID  string   `json:"uniprot_id"`
+	PDBIDs     []string `json:"pdb_ids"`
+	Sufficient bool     `json:"sufficient"`
+	Error      string   `json:"error"`
+}
+
+// DryRun はジョブを作らずに params を検証し、作成されるはずのジョブを返す
+// checkStructures なら Python の check サブコマンドで各 UniProt ID の PDB エントリも照会する（構造のダウンロードはしない）
+// params が不正なら InvalidParamsError を返す
+func (s *JobService) DryRun(ctx context.Context, params models.AnalysisParams, checkStructures bool) (*models.DryRunResponse, error) {
+	if err := validateParams(params); err != nil {
+		return nil, err
+	}
+	params = s.applyDefaultParams(params)
+
+	response := &models.DryRunResponse{DryRun: true, Params: params, StructuresChecked: checkStructures}
+	for _, uniprotID := range models.SplitUniProtIDs(params.UniProtIDs) {
+		response.Jobs = append(response.Jobs, models.DryRunJob{UniProtID: uniprotID})
+	}
+	if !checkStructures {
+		return response, nil
+	}
+
+	checks, err := s.checkStructures(ctx, params)
+	if err != nil {
+		s.logger.Warn("DryRun: structure check failed", "request_id", RequestIDFromContext(ctx), "error", err)
+		response.StructureCheckError = err.Error()
+		return response, nil
+	}
+	for i := range response.Jobs {
+		job := &response.Jobs[i]
+		check, ok := checks[job.UniProtID]
+		switch {
+		case !ok:
+			job.Error = "not checked"
+		case check.Error != "":
+			job.Error = check.Error
+		default:
+			job.PDBIDs = check.PDBIDs
+			if !check.Sufficient {
+				job.Reason = FailureReasonNoSuitableStructures
+			}
+		}
+	}
+	return response, nil
+}
+
+// checkStructures は Python の check サブコマンドを実行し、UniProt ID ごとの結果を返す
+func (s *JobService) checkStructures(ctx context.Context, params models.AnalysisParams) (map[string]structureCheck, error) {
+	ctx, cancel := context.WithTimeout(ctx, structureCheckTimeout)
+	defer cancel()
+
+	argv := []string{
+		s.pythonBin, "-m", "flex_analyzer.cli", "check",
+		"--uniprot-ids", params.UniProtIDs,
+		"--method", *params.Method,
+	}
+	if *params.NegativePDBID != "" {
+		argv = append(argv, "--negative-pdbid", *params.NegativePDBID)
+	}
+	if len(params.PDBIDs) > 0 {
+		argv = append(argv, "--pdb-ids", strings.Join(params.PDBIDs, " "))
+	}
+
+	stdout, stderr, err := s.runner.Run(ctx, argv, s.pythonEngineDir, s.pythonEnv(), nil)
+	if err != nil {
+		if exception := pythonErrorLine(string(stderr)); exception != "" {
+			return nil, fmt.Errorf("structure check failed (%v): %s", err, exception)
+		}
+		return nil, fmt.Errorf("structure check failed: %w", err)
+	}
+
+	// 結果の JSON は最終行（それより前の行は UniProt 照会中のログ）
+	lines := bytes.Split(bytes.TrimSpace(stdout), []byte("\n"))
+	var checks []structureCheck
+	if err := json.Unmarshal(lines[len(lines)-1], &checks); err != nil {
+		return nil, fmt.Errorf("failed to parse structure check output: %w", err)
+	}
+
+	result := make(map[string]structureCheck, len(checks))
+	for _, check := range checks {
+		result[check.UniProtID] = check
+	}
+	return result, nil
+}
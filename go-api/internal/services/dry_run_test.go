@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestDryRunCreatesNoJobs(t *testing.T) {
+	runner := &FakeRunner{}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+
+	resp, err := s.DryRun(context.Background(), models.AnalysisParams{UniProtIDs: "P12345, Q67890"}, false)
+	if err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+	if !resp.DryRun || resp.StructuresChecked || len(resp.Jobs) != 2 || resp.Jobs[1].UniProtID != "Q67890" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Params.Method == nil || *resp.Params.Method != "X-ray" || resp.Params.SeqRatio == nil {
+		t.Errorf("defaults were not applied: %+v", resp.Params)
+	}
+
+	if jobs, err := s.storage.Jobs(); err != nil || len(jobs) != 0 {
+		t.Errorf("dry run created jobs: %v (err %v)", jobs, err)
+	}
+	if len(runner.Calls()) != 0 {
+		t.Error("Python was run without check_structures")
+	}
+
+	if _, err := s.DryRun(context.Background(), models.AnalysisParams{UniProtIDs: "nope"}, false); !errors.Is(err, ErrInvalidParams) || !errors.As(err, new(*models.InvalidUniProtIDsError)) {
+		t.Errorf("invalid ID: got %v, want ErrInvalidParams with InvalidUniProtIDsError", err)
+	}
+}
+
+func TestDryRunChecksStructures(t *testing.T) {
+	runner := &FakeRunner{Output: "Fetching UniProt data ...\n" +
+		`[{"uniprot_id":"P12345","pdb_ids":["1ABC","2DEF"],"sufficient":true},` +
+		`{"uniprot_id":"Q67890","pdb_ids":[],"sufficient":false},` +
+		`{"uniprot_id":"P99999","pdb_ids":[],"sufficient":false,"error":"No entry found in UniProt"}]`}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	negative := "1xyz"
+
+	resp, err := s.DryRun(context.Background(), models.AnalysisParams{UniProtIDs: "P12345 Q67890 P99999", NegativePDBID: &negative}, true)
+	if err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+	if !resp.StructuresChecked || resp.StructureCheckError != "" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if job := resp.Jobs[0]; len(job.PDBIDs) != 2 || job.Reason != "" || job.Error != "" {
+		t.Errorf("P12345: %+v", job)
+	}
+	if job := resp.Jobs[1]; job.Reason != FailureReasonNoSuitableStructures {
+		t.Errorf("Q67890: %+v", job)
+	}
+	if job := resp.Jobs[2]; job.Error != "No entry found in UniProt" {
+		t.Errorf("P99999: %+v", job)
+	}
+
+	calls := runner.Calls()
+	if len(calls) != 1 || calls[0][3] != "check" || argValue(calls[0], "--negative-pdbid") != "1XYZ" {
+		t.Errorf("unexpected check command: %v", calls)
+	}
+}
+
+func TestDryRunReportsFailedStructureCheck(t *testing.T) {
+	runner := &FakeRunner{Stderr: "Traceback (most recent call last):\nModuleNotFoundError: No module named 'flex_analyzer'\n", Err: errors.New("exit status 1")}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+
+	resp, err := s.DryRun(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"}, true)
+	if err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+	if resp.StructureCheckError == "" || len(resp.Jobs) != 1 || resp.Jobs[0].PDBIDs != nil {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import "github.com/yourusername/flex-api/internal/models"
+
+// 購読者ごとのバッファ（溢れた場合は古いイベントから捨てる）
+const subscriberBuffer = 16
+
+// Subscribe はジョブのステータス変更を購読し、受信チャネルと購読解除関数を返す
+func (s *JobService) Subscribe(jobID string) (<-chan models.JobStatus, func()) {
+	ch := make(chan models.JobStatus, subscriberBuffer)
+
+	s.subMu.Lock()
+	s.subscribers[jobID] = append(s.subscribers[jobID], ch)
+	s.subMu.Unlock()
+
+	unsubscribe := func() {
+		s.subMu.Lock()
+		defer s.subMu.Unlock()
+		subs := s.subscribers[jobID]
+		for i, sub := range subs {
+			if sub == ch {
+				s.subscribers[jobID] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		if len(s.subscribers[jobID]) == 0 {
+			delete(s.subscribers, jobID)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// publish は購読者にステータスを配信（ブロックしない）
+func (s *JobService) publish(status models.JobStatus) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	for _, ch := range s.subscribers[status.JobID] {
+		select {
+		case ch <- status:
+		default:
+			// バッファが一杯なら最も古いイベントを捨てて最新を入れる
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- status:
+			default:
+			}
+		}
+	}
+}
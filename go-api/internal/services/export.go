@@ -0,0 +1,68 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// 結果 CSV の種類
+const (
+	ResultCSVResidues = "residues" // PerResidueScores（デフォルト）
+	ResultCSVPairs    = "pairs"    // PairScores
+)
+
+// formatCSVFloat は CSV 用に数値を整形（NaN/Inf は空欄）
+func formatCSVFloat(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// WriteResultCSV は解析結果を CSV として w に書き出す
+func WriteResultCSV(w io.Writer, result *models.NotebookDSAResult, kind string) error {
+	cw := csv.NewWriter(w)
+
+	switch kind {
+	case ResultCSVResidues:
+		if err := cw.Write([]string{"index", "residue_number", "residue_name", "score"}); err != nil {
+			return err
+		}
+		for _, rs := range result.PerResidueScores {
+			if err := cw.Write([]string{
+				strconv.Itoa(rs.Index),
+				strconv.Itoa(rs.ResidueNumber),
+				rs.ResidueName,
+				formatCSVFloat(rs.Score),
+			}); err != nil {
+				return err
+			}
+		}
+	case ResultCSVPairs:
+		if err := cw.Write([]string{"i", "j", "residue_pair", "distance_mean", "distance_std", "score"}); err != nil {
+			return err
+		}
+		for _, ps := range result.PairScores {
+			if err := cw.Write([]string{
+				strconv.Itoa(ps.I),
+				strconv.Itoa(ps.J),
+				ps.ResiduePair,
+				formatCSVFloat(ps.DistanceMean),
+				formatCSVFloat(ps.DistanceStd),
+				formatCSVFloat(ps.Score),
+			}); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown result CSV type: %s", kind)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
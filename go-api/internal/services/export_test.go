@@ -0,0 +1,39 @@
+package services
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestWriteResultCSV(t *testing.T) {
+	result := &models.NotebookDSAResult{
+		PerResidueScores: []models.PerResidueScore{
+			{Index: 0, ResidueNumber: 1, ResidueName: "A", Score: 1.5},
+			{Index: 1, ResidueNumber: 2, ResidueName: "G", Score: math.NaN()},
+		},
+		PairScores: []models.PairScore{
+			{I: 1, J: 2, ResiduePair: "A-1, G-2", DistanceMean: 3.8, DistanceStd: 0.1, Score: 2},
+		},
+	}
+
+	cases := map[string]string{
+		ResultCSVResidues: "index,residue_number,residue_name,score\n0,1,A,1.5\n1,2,G,\n",
+		ResultCSVPairs:    "i,j,residue_pair,distance_mean,distance_std,score\n1,2,\"A-1, G-2\",3.8,0.1,2\n",
+	}
+	for kind, want := range cases {
+		var buf bytes.Buffer
+		if err := WriteResultCSV(&buf, result, kind); err != nil {
+			t.Fatalf("WriteResultCSV(%s): %v", kind, err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("WriteResultCSV(%s):\ngot  %q\nwant %q", kind, got, want)
+		}
+	}
+
+	if err := WriteResultCSV(&bytes.Buffer{}, result, "bogus"); err == nil {
+		t.Error("WriteResultCSV should reject unknown types")
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import "regexp"
+
+// JobStatus.FailureReason の値（失敗の大分類。クライアントはメッセージではなくこれで対処を出し分ける）
+const (
+	FailureTimeout       = "timeout"        // 制限時間内に終わらなかった
+	FailureInvalidInput  = "invalid_input"  // UniProt ID が存在しない、入力が大きすぎるなど
+	FailureNoStructures  = "no_structures"  // 解析に使える構造が足りない
+	FailureEngineError   = "engine_error"   // Python CLI のその他の失敗（import エラー、メモリ不足など）
+	FailureInternalError = "internal_error" // サーバー側（作業ディレクトリ、保存先など）の失敗
+)
+
+// FailureReasonNoSuitableStructures は解析に使える構造（PDBエントリ・Chain）が足りずに失敗したことを示す
+const FailureReasonNoSuitableStructures = "no_suitable_structures"
+
+// FailureReasonInputTooLarge は残基数・PDB エントリ数が -max-residues / -max-structures を超えて断ったことを示す
+const FailureReasonInputTooLarge = "input_too_large"
+
+// noSuitableStructuresMessage は構造不足で失敗したときにステータスへ出すメッセージ
+const noSuitableStructuresMessage = "No suitable structures found for this UniProt ID; try lowering seq_ratio or changing method"
+
+// noSuitableStructuresPattern は Python CLI が構造不足で終了したことを示す出力
+var noSuitableStructuresPattern = regexp.MustCompile(`(?m)^NoSuitableStructuresError:`)
+
+// inputTooLargeMessage は入力が大きすぎて断ったときにステータスへ出すメッセージ
+const inputTooLargeMessage = "UniProt entry is too large to analyse (too many residues or PDB entries)"
+
+// inputTooLargePattern は Python CLI が上限を超える入力を断ったことを示す出力
+// 例外メッセージ（実際の件数と上限）を 1 番目のグループで取り出せる
+var inputTooLargePattern = regexp.MustCompile(`(?m)^InputTooLargeError: *(.*?)\r?$`)
+
+// invalidInputPattern は入力の UniProt ID が存在しないことを示す出力
+var invalidInputPattern = regexp.MustCompile(`UniProt ID not found|No entry found in UniProt|\b404 Client Error`)
+
+// noStructuresPattern は UniProt ID に対応する構造が見つからなかったことを示す出力
+var noStructuresPattern = regexp.MustCompile(`(?i)No structures? found`)
+
+// failureCategory は Python CLI の失敗を大分類する（reason は failureReason の結果）
+func failureCategory(reason, stdout, stderr string) string {
+	output := stdout + "\n" + stderr
+	switch {
+	case reason == FailureReasonNoSuitableStructures:
+		return FailureNoStructures
+	case reason == FailureReasonInputTooLarge:
+		return FailureInvalidInput
+	case invalidInputPattern.MatchString(output):
+		return FailureInvalidInput
+	case noStructuresPattern.MatchString(output):
+		return FailureNoStructures
+	default:
+		return FailureEngineError
+	}
+}
+
+// failureReason は Python CLI の出力から失敗理由の識別子を判定する（判別できなければ空文字）
+func failureReason(stdout, stderr string) string {
+	if noSuitableStructuresPattern.MatchString(stderr) || noSuitableStructuresPattern.MatchString(stdout) {
+		return FailureReasonNoSuitableStructures
+	}
+	if inputTooLargePattern.MatchString(stderr) || inputTooLargePattern.MatchString(stdout) {
+		return FailureReasonInputTooLarge
+	}
+	return ""
+}
+
+// inputTooLargeDetail は InputTooLargeError の例外メッセージを返す（見つからなければ空文字）
+func inputTooLargeDetail(stderr string) string {
+	if m := inputTooLargePattern.FindStringSubmatch(stderr); m != nil {
+		return m[1]
+	}
+	return ""
+}
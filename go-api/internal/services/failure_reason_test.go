@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestNoSuitableStructuresFailureReason(t *testing.T) {
+	runner := &FakeRunner{
+		Output: "Processing P12345 ...\nLess than 3 PDB entries",
+		Stderr: "\nNoSuitableStructuresError: No suitable structures for P12345 (method=X-ray, seq_ratio=0.9)\nAborted!",
+		Err:    errors.New("exit status 1"),
+	}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	if err := s.SetDownloadRetries(2, time.Millisecond); err != nil {
+		t.Fatalf("SetDownloadRetries: %v", err)
+	}
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	status := waitForStatus(t, s, job.JobID)
+	if status.Status != "failed" || status.Reason != FailureReasonNoSuitableStructures {
+		t.Fatalf("got %q reason %q, want failed with %q", status.Status, status.Reason, FailureReasonNoSuitableStructures)
+	}
+	if status.FailureReason != FailureNoStructures {
+		t.Errorf("failure_reason = %q, want %q", status.FailureReason, FailureNoStructures)
+	}
+	if status.Message != noSuitableStructuresMessage {
+		t.Errorf("message = %q", status.Message)
+	}
+	// 再実行しても結果は変わらないので再試行しない
+	if got := len(runner.Calls()); got != 1 {
+		t.Errorf("runner called %d times, want 1", got)
+	}
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		stdout, stderr string
+		want           string
+	}{
+		{"", "\nNoSuitableStructuresError: No suitable structures for P12345\nAborted!", FailureReasonNoSuitableStructures},
+		{"", "Traceback (most recent call last):\nValueError: bad input", ""},
+		{"Less than 3 PDB entries", "", ""},
+	}
+	for _, tt := range tests {
+		if got := failureReason(tt.stdout, tt.stderr); got != tt.want {
+			t.Errorf("failureReason(%q, %q) = %q, want %q", tt.stdout, tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestFailureCategory(t *testing.T) {
+	tests := []struct {
+		reason, stdout, stderr string
+		want                   string
+	}{
+		{FailureReasonNoSuitableStructures, "", "", FailureNoStructures},
+		{FailureReasonInputTooLarge, "", "", FailureInvalidInput},
+		{"", "", "ValueError: UniProt ID not found: XXXXXX", FailureInvalidInput},
+		{"", "requests.exceptions.HTTPError: 404 Client Error: Not Found for url", "", FailureInvalidInput},
+		{"", "No structures found for P12345", "", FailureNoStructures},
+		{"", "", "ModuleNotFoundError: No module named 'pandas'", FailureEngineError},
+		{"", "", "MemoryError", FailureEngineError},
+	}
+	for _, tt := range tests {
+		if got := failureCategory(tt.reason, tt.stdout, tt.stderr); got != tt.want {
+			t.Errorf("failureCategory(%q, %q, %q) = %q, want %q", tt.reason, tt.stdout, tt.stderr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FakeRunner は Python を起動せず、--output-dir にフィクスチャファイルを書き出す Runner
+type FakeRunner struct {
+	Files  map[string]string // 出力ディレクトリからの相対パス → 内容
+	Output string            // 返す標準出力
+	Stderr string            // 返す標準エラー出力
+	Err    error             // 返すエラー
+	Block  bool              // true なら ctx がキャンセルされるまで返らない
+
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (f *FakeRunner) Run(ctx context.Context, args []string, dir string, env []string, onLine func(line string)) ([]byte, []byte, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, args)
+	f.mu.Unlock()
+
+	outputDir := argValue(args, "--output-dir")
+	for name, content := range f.Files {
+		path := filepath.Join(outputDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, nil, err
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if onLine != nil {
+		for _, output := range []string{f.Output, f.Stderr} {
+			if output == "" {
+				continue
+			}
+			for _, line := range strings.Split(output, "\n") {
+				onLine(line)
+			}
+		}
+	}
+
+	if f.Block {
+		<-ctx.Done()
+		return []byte(f.Output), []byte(f.Stderr), ctx.Err()
+	}
+
+	return []byte(f.Output), []byte(f.Stderr), f.Err
+}
+
+// Calls は Run に渡された argv の一覧を返す
+func (f *FakeRunner) Calls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.calls...)
+}
+
+// argValue は argv から "--flag value" 形式の値を取り出す
+func argValue(args []string, flag string) string {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// summaryFixture は P12345 の最小構成の Notebook DSA 出力
+func summaryFixture() map[string]string {
+	return map[string]string{
+		"summary.csv":             "uniprotid,seq_ratio,Entries,Chains,Length,Length(%),UMF\nP12345,0.2,3,3,3,50.0,1.5\n",
+		"trimsequence_P12345.csv": "P12345,1A00 A\nALA,ALA\nGLY,GLY\nSER,SER\n",
+		"distance_P12345.csv":     "1,2,3.8,3.9,4.0\n1,3,6.1,6.3,6.2\n2,3,3.7,3.8,3.9\n",
+		"atom_coord/1a00.csv":     "",
+		"pdb_files/.keep":         "",
+	}
+}
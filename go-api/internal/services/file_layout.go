@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FileLayout は Python エンジンがジョブディレクトリに書く成果物の名前（ジョブ直下からの相対パス）
+// 実行側（--output-dir / --pdb-dir）と読み取り側（結果の組み立て、画像の配信）はどちらもここから名前を引く
+// エンジンの命名を変えたら Version を上げ、ここだけを直す
+type FileLayout struct {
+	Version int
+
+	Summary      string // 全 UniProt ID のまとめ CSV
+	PDBDir       string // 構造ファイルの置き場所（名前は pdb_files だが中身は mmCIF）
+	AtomCoordDir string // 構造ごとの座標 CSV（{pdbid}.csv）のディレクトリ
+
+	// UniProt ID ごとの CSV（%s は UniProt ID）
+	DistanceFormat     string
+	TrimSequenceFormat string
+	StructuresFormat   string
+
+	// cis 解析の CSV（%s は UniProt ID と formatSeqRatio で書いた seq_ratio）
+	// 探すときは CisMarker を含む CSV を全て候補にし、seq_ratio の部分は数値として比べる（FindCis）
+	CisFormat string
+	CisMarker string
+
+	Heatmap           string // ヒートマップ PNG
+	HeatmapSuffix     string // Heatmap が無い場合に探す Notebook DSA 形式（{uniprotid}_{seq_ratio}_heatmap.png）
+	DistanceScorePlot string // distance–score プロット PNG
+
+	SummaryColumns SummaryColumns // Summary の列名
+}
+
+// defaultFileLayout は現在の flex_analyzer notebook コマンドの出力
+var defaultFileLayout = FileLayout{
+	Version: 1,
+
+	Summary:      "summary.csv",
+	PDBDir:       "pdb_files",
+	AtomCoordDir: "atom_coord",
+
+	DistanceFormat:     "distance_%s.csv",
+	TrimSequenceFormat: "trimsequence_%s.csv",
+	StructuresFormat:   "structures_%s.csv",
+
+	CisFormat: "%s_%s_cis_nor+sub.csv",
+	CisMarker: "_cis_",
+
+	Heatmap:           "heatmap.png",
+	HeatmapSuffix:     "_heatmap.png",
+	DistanceScorePlot: "distance_score.png",
+
+	SummaryColumns: defaultSummaryColumns,
+}
+
+// Distance は UniProt ID ごとの距離 CSV の名前
+func (l FileLayout) Distance(uniprotID string) string {
+	return fmt.Sprintf(l.DistanceFormat, uniprotID)
+}
+
+// TrimSequence は UniProt ID ごとのアライメント済み配列 CSV の名前
+func (l FileLayout) TrimSequence(uniprotID string) string {
+	return fmt.Sprintf(l.TrimSequenceFormat, uniprotID)
+}
+
+// Structures は UniProt ID ごとの PDB エントリの採否 CSV の名前
+func (l FileLayout) Structures(uniprotID string) string {
+	return fmt.Sprintf(l.StructuresFormat, uniprotID)
+}
+
+// Cis は cis 解析の CSV の既定の名前
+func (l FileLayout) Cis(uniprotID string, seqRatio float64) string {
+	return fmt.Sprintf(l.CisFormat, uniprotID, formatSeqRatio(seqRatio))
+}
+
+// IsCis はジョブ直下の name が uniprotID の cis 解析の CSV らしいか
+func (l FileLayout) IsCis(name, uniprotID string) bool {
+	return !strings.Contains(name, "/") && strings.Contains(name, uniprotID) &&
+		strings.Contains(name, l.CisMarker) && strings.HasSuffix(name, ".csv")
+}
+
+// FindCis は names（ジョブ直下のファイル名）から uniprotID の cis 解析の CSV を選ぶ
+// seq_ratio の書き方（"0.2" / "0.20" / "2e-01"）に依らないよう、名前の seq_ratio 部分を数値で比べる
+// 一致するものが無ければ最初の候補を返す（seq_ratio 部分の無い名前の場合など）
+func (l FileLayout) FindCis(names []string, uniprotID string, seqRatio float64) (string, bool) {
+	var candidates []string
+	for _, name := range names {
+		if !l.IsCis(name, uniprotID) {
+			continue
+		}
+		if ratio, ok := l.cisSeqRatio(name, uniprotID); ok && ratio == seqRatio {
+			return name, true
+		}
+		candidates = append(candidates, name)
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	return candidates[0], true
+}
+
+// cisSeqRatio は "{uniprotID}_{seq_ratio}{CisMarker}..." の seq_ratio 部分を数値で返す
+func (l FileLayout) cisSeqRatio(name, uniprotID string) (float64, bool) {
+	rest, ok := strings.CutPrefix(name, uniprotID+"_")
+	if !ok {
+		return 0, false
+	}
+	ratio, _, ok := strings.Cut(rest, l.CisMarker)
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(ratio, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// formatSeqRatio は seq_ratio を Python の str(float) と同じ書き方にする（0.2 → "0.2"、0.15 → "0.15"、1 → "1.0"）
+// CLI の --seq-ratio とファイル名の両方でこれを使い、エンジンが書く名前と揃える
+func formatSeqRatio(v float64) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// AtomCoordPDBID は name が AtomCoordDir 直下の座標 CSV なら PDB ID（大文字）を返す
+func (l FileLayout) AtomCoordPDBID(name string) (string, bool) {
+	rest, ok := strings.CutPrefix(name, l.AtomCoordDir+"/")
+	if !ok || strings.Contains(rest, "/") || !strings.HasSuffix(rest, ".csv") {
+		return "", false
+	}
+	return strings.ToUpper(strings.TrimSuffix(rest, ".csv")), true
+}
@@ -0,0 +1,92 @@
+package services
+
+import "testing"
+
+func TestDefaultFileLayoutNames(t *testing.T) {
+	l := defaultFileLayout
+	tests := []struct{ got, want string }{
+		{l.Distance("P12345"), "distance_P12345.csv"},
+		{l.TrimSequence("P12345"), "trimsequence_P12345.csv"},
+		{l.Structures("P12345"), "structures_P12345.csv"},
+		{l.Cis("P12345", 0.2), "P12345_0.2_cis_nor+sub.csv"},
+		{l.Cis("P12345", 0.15), "P12345_0.15_cis_nor+sub.csv"},
+		{l.Cis("P12345", 0.33), "P12345_0.33_cis_nor+sub.csv"},
+		{l.Cis("P12345", 1), "P12345_1.0_cis_nor+sub.csv"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestFileLayoutIsCis(t *testing.T) {
+	l := defaultFileLayout
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"P12345_0.25_cis_nor+sub.csv", true},
+		{"P12345_0.2_cis_nor+sub.csv", true},
+		{"P67890_0.2_cis_nor+sub.csv", false},
+		{"sub/P12345_0.2_cis_nor+sub.csv", false},
+		{"P12345_0.2_heatmap.png", false},
+	}
+	for _, tt := range tests {
+		if got := l.IsCis(tt.name, "P12345"); got != tt.want {
+			t.Errorf("IsCis(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFileLayoutFindCis(t *testing.T) {
+	l := defaultFileLayout
+	names := []string{"summary.csv", "P12345_0.2_cis_nor+sub.csv", "P12345_0.150_cis_nor+sub.csv", "P12345_0.33_cis_nor+sub.csv"}
+	tests := []struct {
+		seqRatio float64
+		want     string
+	}{
+		{0.15, "P12345_0.150_cis_nor+sub.csv"},
+		{0.33, "P12345_0.33_cis_nor+sub.csv"},
+		{0.2, "P12345_0.2_cis_nor+sub.csv"},
+		{0.25, "P12345_0.2_cis_nor+sub.csv"}, // 一致しなければ最初の候補
+	}
+	for _, tt := range tests {
+		if got, ok := l.FindCis(names, "P12345", tt.seqRatio); !ok || got != tt.want {
+			t.Errorf("FindCis(%v) = %q, %v, want %q", tt.seqRatio, got, ok, tt.want)
+		}
+	}
+	if got, ok := l.FindCis([]string{"summary.csv"}, "P12345", 0.2); ok {
+		t.Errorf("FindCis without cis files = %q, want none", got)
+	}
+}
+
+func TestFormatSeqRatioMatchesPython(t *testing.T) {
+	// Python の str(float) の書き方
+	tests := map[float64]string{0.2: "0.2", 0.15: "0.15", 0.33: "0.33", 0.333: "0.333", 1: "1.0"}
+	for v, want := range tests {
+		if got := formatSeqRatio(v); got != want {
+			t.Errorf("formatSeqRatio(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestFileLayoutAtomCoordPDBID(t *testing.T) {
+	l := defaultFileLayout
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"atom_coord/1a00.csv", "1A00", true},
+		{"atom_coord/sub/1a00.csv", "", false},
+		{"atom_coord/1a00.cif", "", false},
+		{"pdb_files/1a00.csv", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := l.AtomCoordPDBID(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("AtomCoordPDBID(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
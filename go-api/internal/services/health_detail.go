@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// DefaultHealthWindow は HealthDetail で集計する直近の終了済みジョブの件数のデフォルト
+const DefaultHealthWindow = 50
+
+// healthMinJobs は成功率で degraded と判定するのに必要な集計件数（少なすぎる件数では判定しない）
+// healthMinSuccessRate はこれを下回ると degraded とする成功率
+const (
+	healthMinJobs        = 5
+	healthMinSuccessRate = 0.5
+)
+
+// HealthDetail は作成が新しい順に n 件の終了済みジョブの成功率・平均実行時間と、
+// 制限時間（JobTimeout）を超えて processing のままのジョブを返す
+// 成功率が低い（Python エンジンが失敗し続けている）か止まったジョブがあれば Status を "degraded" にする
+func (s *JobService) HealthDetail(n int) (*models.HealthDetail, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("window must be positive: %d", n)
+	}
+
+	jobs, err := s.ListJobs("", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	timeout := s.JobTimeout()
+	s.mu.RLock()
+	running := make(map[string]time.Time, len(s.running))
+	for jobID, startedAt := range s.running {
+		running[jobID] = startedAt
+	}
+	s.mu.RUnlock()
+
+	detail := &models.HealthDetail{
+		Status:            "ok",
+		Problems:          []string{},
+		Window:            n,
+		JobTimeoutSeconds: timeout.Seconds(),
+		StuckJobs:         []models.StuckJob{},
+	}
+	var durationSum float64
+	for _, job := range jobs {
+		if job.Status == "processing" {
+			// 実行中の goroutine が無ければ、最後にステータスが更新された時刻から数える
+			startedAt, ok := running[job.JobID]
+			if !ok {
+				startedAt = job.UpdatedAt
+			}
+			if elapsed := now.Sub(startedAt); elapsed > timeout {
+				detail.StuckJobs = append(detail.StuckJobs, models.StuckJob{
+					JobID:          job.JobID,
+					StartedAt:      startedAt,
+					ElapsedSeconds: elapsed.Seconds(),
+					Orphaned:       !ok,
+				})
+			}
+			continue
+		}
+		if !IsTerminalStatus(job.Status) || detail.Jobs == n {
+			continue
+		}
+
+		detail.Jobs++
+		switch job.Status {
+		case "completed":
+			detail.Completed++
+			if job.DurationSeconds != nil {
+				durationSum += float64(*job.DurationSeconds)
+			}
+		case "failed", "interrupted":
+			detail.Failed++
+		case "cancelled":
+			detail.Cancelled++
+		}
+	}
+
+	if finished := detail.Completed + detail.Failed; finished > 0 {
+		rate := float64(detail.Completed) / float64(finished)
+		detail.SuccessRate = &rate
+		if finished >= healthMinJobs && rate < healthMinSuccessRate {
+			detail.Problems = append(detail.Problems, fmt.Sprintf("%d of the last %d finished jobs failed", detail.Failed, finished))
+		}
+	}
+	if detail.Completed > 0 {
+		average := durationSum / float64(detail.Completed)
+		detail.AverageDurationSeconds = &average
+	}
+	if len(detail.StuckJobs) > 0 {
+		detail.Problems = append(detail.Problems, fmt.Sprintf("%d jobs have been processing longer than the %s timeout", len(detail.StuckJobs), timeout))
+	}
+	if len(detail.Problems) > 0 {
+		detail.Status = "degraded"
+		s.logger.Warn("HealthDetail: degraded", "problems", detail.Problems)
+	}
+
+	return detail, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestHealthDetail(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	createJob := func() {
+		t.Helper()
+		job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+		if err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+		waitForStatus(t, s, job.JobID)
+	}
+
+	createJob()
+	detail, err := s.HealthDetail(DefaultHealthWindow)
+	if err != nil {
+		t.Fatalf("HealthDetail: %v", err)
+	}
+	if detail.Status != "ok" || detail.Completed != 1 || detail.SuccessRate == nil || *detail.SuccessRate != 1 || detail.AverageDurationSeconds == nil {
+		t.Errorf("after one completed job: %+v", detail)
+	}
+
+	// Python エンジンが失敗し続けると成功率で degraded になる
+	s.runner = &FakeRunner{Err: errors.New("exit status 1")}
+	for i := 0; i < 4; i++ {
+		createJob()
+	}
+	if detail, err = s.HealthDetail(DefaultHealthWindow); err != nil {
+		t.Fatalf("HealthDetail: %v", err)
+	}
+	if detail.Status != "degraded" || detail.Jobs != 5 || detail.Failed != 4 || *detail.SuccessRate != 0.2 {
+		t.Errorf("after four failures: %+v", detail)
+	}
+	if detail, err = s.HealthDetail(3); err != nil || detail.Jobs != 3 || detail.Completed != 0 {
+		t.Errorf("window 3: %+v (err %v), want only the three newest jobs", detail, err)
+	}
+
+	// 実行していないのに processing のまま制限時間を超えたジョブ
+	if err := s.SetJobTimeout(time.Millisecond); err != nil {
+		t.Fatalf("SetJobTimeout: %v", err)
+	}
+	const orphanID = "99999999-8888-7777-6666-555555555555"
+	s.writeJobStatus(models.JobStatus{JobID: orphanID, Status: "processing"})
+	time.Sleep(5 * time.Millisecond)
+	if detail, err = s.HealthDetail(DefaultHealthWindow); err != nil {
+		t.Fatalf("HealthDetail: %v", err)
+	}
+	if len(detail.StuckJobs) != 1 || detail.StuckJobs[0].JobID != orphanID || !detail.StuckJobs[0].Orphaned {
+		t.Errorf("stuck_jobs = %+v, want the orphaned job", detail.StuckJobs)
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// ErrNoHeatmap は結果にヒートマップが含まれない場合のエラー
+var ErrNoHeatmap = errors.New("heatmap not available")
+
+// GetHeatmapValues はヒートマップの数値行列を返す
+// downsample > 0 かつ行列サイズより小さい場合は downsample×downsample のブロック平均に縮約する
+// scale が nil でなければ値を表示範囲に収める（範囲は縮約前の値から決める）
+func (s *JobService) GetHeatmapValues(jobID string, downsample int, scale *HeatmapScale) (*models.Heatmap, error) {
+	result, err := s.GetResult(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if result.Heatmap == nil || len(result.Heatmap.Values) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoHeatmap, jobID)
+	}
+
+	var vmin, vmax float64
+	if scale != nil {
+		if vmin, vmax, err = scale.resolve(heatmapCellValues(result.Heatmap)); err != nil {
+			return nil, err
+		}
+	}
+
+	heatmap := result.Heatmap
+	if downsample > 0 && downsample < len(heatmap.Values) {
+		heatmap = downsampleHeatmap(heatmap, downsample)
+	}
+	if scale != nil {
+		heatmap = scaleHeatmap(heatmap, scale, vmin, vmax)
+	}
+	return heatmap, nil
+}
+
+// GetSparseHeatmap はヒートマップを値のあるセルのみの形式で返す
+// N×N の行列は確保せず PairScores から直接組み立てるので、ヒートマップを省いた大きな結果でも返せる
+// scale が nil でなければ値を表示範囲に収める
+func (s *JobService) GetSparseHeatmap(jobID string, scale *HeatmapScale) (*models.SparseHeatmap, error) {
+	result, err := s.GetResult(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	size := result.NumResidues
+	if result.Heatmap != nil {
+		size = result.Heatmap.Size
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoHeatmap, jobID)
+	}
+	heatmap := sparseHeatmap(result.PairScores, size)
+	if scale != nil {
+		return scaleSparseHeatmap(heatmap, scale)
+	}
+	return heatmap, nil
+}
+
+// sparseHeatmap は convertSummaryCSVToResult の密な行列と同じ規則（範囲外・NaN/Inf は除く）でセルを並べる
+// 同じセルのペアが複数ある場合は密な行列と同じく後のものを使う
+func sparseHeatmap(pairs []models.PairScore, size int) *models.SparseHeatmap {
+	cells := make([]models.HeatmapCell, 0, len(pairs))
+	index := make(map[[2]int]int, len(pairs))
+	for _, ps := range pairs {
+		i, j := ps.I-1, ps.J-1
+		if i < 0 || i >= size || j < 0 || j >= size || math.IsNaN(ps.Score) || math.IsInf(ps.Score, 0) {
+			continue
+		}
+		cell := models.HeatmapCell{I: i, J: j, Value: ps.Score}
+		if k, ok := index[[2]int{i, j}]; ok {
+			cells[k] = cell
+			continue
+		}
+		index[[2]int{i, j}] = len(cells)
+		cells = append(cells, cell)
+	}
+	return &models.SparseHeatmap{Size: size, Cells: cells}
+}
+
+// downsampleHeatmap は行列を n×n ブロックに分け、各ブロックの非 null 値の平均を取る
+// ブロック内が全て null（NaN）の場合は null のまま
+func downsampleHeatmap(h *models.Heatmap, n int) *models.Heatmap {
+	size := len(h.Values)
+	values := make([][]*float64, n)
+	for bi := 0; bi < n; bi++ {
+		rowStart, rowEnd := bi*size/n, (bi+1)*size/n
+		values[bi] = make([]*float64, n)
+		for bj := 0; bj < n; bj++ {
+			colStart, colEnd := bj*size/n, (bj+1)*size/n
+
+			var sum float64
+			var count int
+			for i := rowStart; i < rowEnd; i++ {
+				row := h.Values[i]
+				for j := colStart; j < colEnd && j < len(row); j++ {
+					if row[j] != nil {
+						sum += *row[j]
+						count++
+					}
+				}
+			}
+			if count > 0 {
+				mean := sum / float64(count)
+				values[bi][bj] = &mean
+			}
+		}
+	}
+
+	return &models.Heatmap{Size: n, Values: values}
+}
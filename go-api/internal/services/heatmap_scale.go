@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// ErrInvalidHeatmapScale はヒートマップの表示範囲が不正な場合のエラー
+var ErrInvalidHeatmapScale = errors.New("invalid heatmap scale")
+
+// HeatmapBound はヒートマップの表示範囲の端（値そのもの、または全セルの値のパーセンタイル）
+type HeatmapBound struct {
+	Value      float64
+	Percentile bool // true なら Value は 0〜100 のパーセンタイル
+}
+
+// ParseHeatmapBound は ?vmin= / ?vmax= の値を解釈する（"0.5" なら値、"p95" なら 95 パーセンタイル）
+func ParseHeatmapBound(s string) (HeatmapBound, error) {
+	if rest, ok := strings.CutPrefix(s, "p"); ok {
+		p, err := strconv.ParseFloat(rest, 64)
+		if err != nil || p < 0 || p > 100 {
+			return HeatmapBound{}, fmt.Errorf("%w: percentile must be p0 to p100: %q", ErrInvalidHeatmapScale, s)
+		}
+		return HeatmapBound{Value: p, Percentile: true}, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return HeatmapBound{}, fmt.Errorf("%w: bound must be a number or a percentile such as p95: %q", ErrInvalidHeatmapScale, s)
+	}
+	return HeatmapBound{Value: v}, nil
+}
+
+// HeatmapScale は heatmap.json の値を表示範囲 [vmin, vmax] に収める変換
+// Min・Max が nil ならその端は全セルの最小値・最大値、Normalize なら範囲を 0〜1 に写す
+// パーセンタイルは縮約（downsample）前の全セルの値から求める
+type HeatmapScale struct {
+	Min       *HeatmapBound
+	Max       *HeatmapBound
+	Normalize bool
+}
+
+// resolve は values（NaN/Inf を含まない）から表示範囲を決める
+func (sc *HeatmapScale) resolve(values []float64) (vmin, vmax float64, err error) {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	bound := func(b *HeatmapBound, fallback float64) float64 {
+		switch {
+		case b == nil:
+			return fallback
+		case b.Percentile:
+			return percentile(sorted, b.Value)
+		default:
+			return b.Value
+		}
+	}
+	var lo, hi float64
+	if len(sorted) > 0 {
+		lo, hi = sorted[0], sorted[len(sorted)-1]
+	}
+	vmin, vmax = bound(sc.Min, lo), bound(sc.Max, hi)
+	if vmin > vmax {
+		return 0, 0, fmt.Errorf("%w: vmin %g is greater than vmax %g", ErrInvalidHeatmapScale, vmin, vmax)
+	}
+	return vmin, vmax, nil
+}
+
+// apply は v を [vmin, vmax] に収め、Normalize なら 0〜1 に写す（vmin == vmax なら 0）
+func (sc *HeatmapScale) apply(v, vmin, vmax float64) float64 {
+	v = math.Min(math.Max(v, vmin), vmax)
+	if !sc.Normalize {
+		return v
+	}
+	if vmax == vmin {
+		return 0
+	}
+	return (v - vmin) / (vmax - vmin)
+}
+
+// name は応答の scale に入れる変換の名前
+func (sc *HeatmapScale) name() string {
+	if sc.Normalize {
+		return "normalize"
+	}
+	return "clamp"
+}
+
+// percentile は昇順の sorted の p パーセンタイル（隣り合う順位の間は線形補間、numpy の既定と同じ）
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// heatmapCellValues は行列の null でないセルの値を返す
+func heatmapCellValues(h *models.Heatmap) []float64 {
+	var values []float64
+	for _, row := range h.Values {
+		for _, v := range row {
+			if v != nil {
+				values = append(values, *v)
+			}
+		}
+	}
+	return values
+}
+
+// scaleHeatmap は h の値を [vmin, vmax] で変換した新しい行列を返す（h はキャッシュされた結果なので変更しない）
+func scaleHeatmap(h *models.Heatmap, sc *HeatmapScale, vmin, vmax float64) *models.Heatmap {
+	values := make([][]*float64, len(h.Values))
+	for i, row := range h.Values {
+		values[i] = make([]*float64, len(row))
+		for j, v := range row {
+			if v != nil {
+				scaled := sc.apply(*v, vmin, vmax)
+				values[i][j] = &scaled
+			}
+		}
+	}
+	return &models.Heatmap{Size: h.Size, Values: values, VMin: &vmin, VMax: &vmax, Scale: sc.name()}
+}
+
+// scaleSparseHeatmap は h のセルの値を [vmin, vmax] で変換する（h は呼び出し側で組み立てたもの）
+func scaleSparseHeatmap(h *models.SparseHeatmap, sc *HeatmapScale) (*models.SparseHeatmap, error) {
+	values := make([]float64, len(h.Cells))
+	for i, cell := range h.Cells {
+		values[i] = cell.Value
+	}
+	vmin, vmax, err := sc.resolve(values)
+	if err != nil {
+		return nil, err
+	}
+	for i := range h.Cells {
+		h.Cells[i].Value = sc.apply(h.Cells[i].Value, vmin, vmax)
+	}
+	h.VMin, h.VMax, h.Scale = &vmin, &vmax, sc.name()
+	return h, nil
+}
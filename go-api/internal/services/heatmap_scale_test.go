@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestParseHeatmapBound(t *testing.T) {
+	if b, err := ParseHeatmapBound("p95"); err != nil || b != (HeatmapBound{Value: 95, Percentile: true}) {
+		t.Errorf("p95: got %+v (err %v)", b, err)
+	}
+	if b, err := ParseHeatmapBound("-0.5"); err != nil || b != (HeatmapBound{Value: -0.5}) {
+		t.Errorf("-0.5: got %+v (err %v)", b, err)
+	}
+	for _, s := range []string{"p101", "px", "abc", "NaN", "Inf"} {
+		if _, err := ParseHeatmapBound(s); !errors.Is(err, ErrInvalidHeatmapScale) {
+			t.Errorf("%q: got %v, want ErrInvalidHeatmapScale", s, err)
+		}
+	}
+}
+
+func TestScaleHeatmap(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	h := &models.Heatmap{Size: 2, Values: [][]*float64{{f(0), f(10)}, {nil, f(20)}}}
+
+	// 10〜90 パーセンタイル（値は 0, 10, 20 なので 2 と 18）に収める
+	scale := &HeatmapScale{Min: &HeatmapBound{Value: 10, Percentile: true}, Max: &HeatmapBound{Value: 90, Percentile: true}}
+	vmin, vmax, err := scale.resolve(heatmapCellValues(h))
+	if err != nil || vmin != 2 || vmax != 18 {
+		t.Fatalf("resolve: got [%v, %v] (err %v), want [2, 18]", vmin, vmax, err)
+	}
+	got := scaleHeatmap(h, scale, vmin, vmax)
+	if *got.Values[0][0] != 2 || *got.Values[0][1] != 10 || got.Values[1][0] != nil || *got.Values[1][1] != 18 || got.Scale != "clamp" {
+		t.Errorf("clamp: got %+v", got)
+	}
+	// キャッシュされた結果の行列は変えない
+	if *h.Values[0][0] != 0 || h.VMin != nil {
+		t.Errorf("original heatmap was modified: %+v", h)
+	}
+
+	// 範囲を指定しない端は全セルの最小値・最大値
+	scale = &HeatmapScale{Max: &HeatmapBound{Value: 10}, Normalize: true}
+	if vmin, vmax, err = scale.resolve(heatmapCellValues(h)); err != nil || vmin != 0 || vmax != 10 {
+		t.Fatalf("resolve: got [%v, %v] (err %v), want [0, 10]", vmin, vmax, err)
+	}
+	got = scaleHeatmap(h, scale, vmin, vmax)
+	if *got.Values[0][1] != 1 || *got.Values[1][1] != 1 || *got.VMax != 10 || got.Scale != "normalize" {
+		t.Errorf("normalize: got %+v", got)
+	}
+
+	scale = &HeatmapScale{Min: &HeatmapBound{Value: 5}, Max: &HeatmapBound{Value: 1}}
+	if _, _, err := scale.resolve(heatmapCellValues(h)); !errors.Is(err, ErrInvalidHeatmapScale) {
+		t.Errorf("vmin > vmax: got %v, want ErrInvalidHeatmapScale", err)
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestDownsampleHeatmapAveragesBlocks(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	h := &models.Heatmap{
+		Size: 4,
+		Values: [][]*float64{
+			{f(1), f(3), nil, nil},
+			{f(5), f(7), nil, nil},
+			{f(2), nil, f(4), f(4)},
+			{nil, nil, f(4), f(4)},
+		},
+	}
+
+	got := downsampleHeatmap(h, 2)
+	if got.Size != 2 || len(got.Values) != 2 {
+		t.Fatalf("got size %d (%d rows), want 2", got.Size, len(got.Values))
+	}
+
+	want := [][]*float64{{f(4), nil}, {f(2), f(4)}}
+	for i := range want {
+		for j := range want[i] {
+			g, w := got.Values[i][j], want[i][j]
+			if (g == nil) != (w == nil) || (g != nil && *g != *w) {
+				t.Errorf("block (%d,%d): got %v, want %v", i, j, g, w)
+			}
+		}
+	}
+}
+
+func TestSparseHeatmap(t *testing.T) {
+	pairs := []models.PairScore{
+		{I: 1, J: 2, Score: 0.5},
+		{I: 2, J: 3, Score: math.NaN()},
+		{I: 1, J: 9, Score: 1}, // 範囲外
+		{I: 3, J: 1, Score: 2},
+		{I: 1, J: 2, Score: 0.7}, // 同じセルは後のものを使う
+	}
+
+	got := sparseHeatmap(pairs, 3)
+	want := []models.HeatmapCell{{I: 0, J: 1, Value: 0.7}, {I: 2, J: 0, Value: 2}}
+	if got.Size != 3 || len(got.Cells) != len(want) {
+		t.Fatalf("got %+v, want size 3 with %v", got, want)
+	}
+	for i := range want {
+		if got.Cells[i] != want[i] {
+			t.Errorf("cells[%d] = %+v, want %+v", i, got.Cells[i], want[i])
+		}
+	}
+}
+
+func TestGetSparseHeatmapWithoutDenseHeatmap(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	// 密なヒートマップを省いた結果でも PairScores から返せる
+	if err := s.SetInputLimits(2, 0); err != nil {
+		t.Fatalf("SetInputLimits: %v", err)
+	}
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	got, err := s.GetSparseHeatmap(job.JobID, nil)
+	if err != nil {
+		t.Fatalf("GetSparseHeatmap: %v", err)
+	}
+	if got.Size != 3 || len(got.Cells) != 3 {
+		t.Errorf("got size %d with %d cells, want 3 and 3", got.Size, len(got.Cells))
+	}
+}
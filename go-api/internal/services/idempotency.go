@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// defaultIdempotencyGrace は冪等キーを、対応するジョブが全て終了してから保持する期間のデフォルト
+const defaultIdempotencyGrace = 24 * time.Hour
+
+// idempotencyPruneInterval は期限切れの冪等キーを掃除する間隔（掃除は受け付け時にまとめて行う）
+const idempotencyPruneInterval = time.Minute
+
+// ErrIdempotencyKeyReused は同じ冪等キーで異なるパラメータが送られた場合のエラー
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used with different parameters")
+
+// ErrIdempotencyKeyInProgress は同じ冪等キーの最初のリクエストがまだジョブを作成中の場合のエラー
+var ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
+
+// idempotencyEntry は冪等キーごとの最初のリクエストの内容と作成したジョブ
+type idempotencyEntry struct {
+	fingerprint string               // パラメータのハッシュ
+	response    *models.JobsResponse // 作成中は nil
+	expiresAt   time.Time            // 全ジョブが終了した時刻 + 猶予（終了するまではゼロ）
+}
+
+// SetIdempotencyGrace は冪等キーを、作成したジョブが全て終了してから保持する期間を設定
+func (s *JobService) SetIdempotencyGrace(grace time.Duration) error {
+	if grace <= 0 {
+		return fmt.Errorf("idempotency grace period must be positive: %s", grace)
+	}
+	s.idemMu.Lock()
+	defer s.idemMu.Unlock()
+	s.idempotencyGrace = grace
+	return nil
+}
+
+// CreateJobsIdempotent は key が未使用なら CreateJobs でジョブを作成し、key と結果を記録する
+// 期限内に同じ key・同じパラメータで呼ばれたら新しいジョブは作らず、記録した結果を replayed=true で返す
+// key はクライアントごとに一意になるよう呼び出し側で組み立てる（サーバーを再起動すると忘れる）
+func (s *JobService) CreateJobsIdempotent(ctx context.Context, key string, params models.AnalysisParams) (resp *models.JobsResponse, replayed bool, err error) {
+	fingerprint, err := paramsFingerprint(params)
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.idemMu.Lock()
+	now := time.Now()
+	if now.Sub(s.idempotencyPrunedAt) >= idempotencyPruneInterval {
+		s.pruneIdempotencyKeys(now)
+	}
+	if entry, ok := s.idempotencyKeys[key]; ok && !s.idempotencyExpired(entry, now) {
+		s.idemMu.Unlock()
+		switch {
+		case entry.fingerprint != fingerprint:
+			return nil, false, ErrIdempotencyKeyReused
+		case entry.response == nil:
+			return nil, false, ErrIdempotencyKeyInProgress
+		}
+		s.logger.Info("CreateJobsIdempotent: replaying jobs for idempotency key", "request_id", RequestIDFromContext(ctx), "batch_id", entry.response.BatchID)
+		return entry.response, true, nil
+	}
+	// 作成中に同じ key が来ても二重に作らないよう、先に予約しておく
+	entry := &idempotencyEntry{fingerprint: fingerprint}
+	s.idempotencyKeys[key] = entry
+	s.idemMu.Unlock()
+
+	resp, err = s.CreateJobs(ctx, params)
+
+	s.idemMu.Lock()
+	defer s.idemMu.Unlock()
+	if err != nil {
+		// 失敗したリクエストは同じ key で再試行できるようにする
+		delete(s.idempotencyKeys, key)
+		return nil, false, err
+	}
+	entry.response = resp
+	return resp, false, nil
+}
+
+// idempotencyExpired は entry の期限が切れたかを返す（呼び出し側で s.idemMu を保持すること）
+// ジョブが全て終了していれば、最後に終了した時刻から期限を決める
+func (s *JobService) idempotencyExpired(entry *idempotencyEntry, now time.Time) bool {
+	if entry.response == nil {
+		return false
+	}
+	if entry.expiresAt.IsZero() {
+		var finishedAt time.Time
+		for _, job := range entry.response.Jobs {
+			status, err := s.GetJobStatus(job.JobID)
+			if err != nil {
+				// ジョブが削除されていれば、返しても意味が無いので期限切れにする
+				return true
+			}
+			if !IsTerminalStatus(status.Status) {
+				return false
+			}
+			if status.UpdatedAt.After(finishedAt) {
+				finishedAt = status.UpdatedAt
+			}
+		}
+		entry.expiresAt = finishedAt.Add(s.idempotencyGrace)
+	}
+	return now.After(entry.expiresAt)
+}
+
+// pruneIdempotencyKeys は期限切れの冪等キーを削除する（呼び出し側で s.idemMu を保持すること）
+func (s *JobService) pruneIdempotencyKeys(now time.Time) {
+	for key, entry := range s.idempotencyKeys {
+		if s.idempotencyExpired(entry, now) {
+			delete(s.idempotencyKeys, key)
+		}
+	}
+	s.idempotencyPrunedAt = now
+}
+
+// paramsFingerprint は同じ冪等キーで送られたパラメータが同じかを比べるためのハッシュ
+func paramsFingerprint(params models.AnalysisParams) (string, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal params: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestCreateJobsIdempotent(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	params := models.AnalysisParams{UniProtIDs: "P12345"}
+
+	first, replayed, err := s.CreateJobsIdempotent(context.Background(), "client key-1", params)
+	if err != nil || replayed {
+		t.Fatalf("first request: replayed=%v, err=%v", replayed, err)
+	}
+	again, replayed, err := s.CreateJobsIdempotent(context.Background(), "client key-1", params)
+	if err != nil || !replayed || again.BatchID != first.BatchID || again.Jobs[0].JobID != first.Jobs[0].JobID {
+		t.Fatalf("repeat: got %+v, replayed=%v, err=%v, want batch %s", again, replayed, err, first.BatchID)
+	}
+
+	other := params
+	other.UniProtIDs = "Q67890"
+	if _, _, err := s.CreateJobsIdempotent(context.Background(), "client key-1", other); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("different params: got %v, want ErrIdempotencyKeyReused", err)
+	}
+	// キーはクライアントごと
+	if _, replayed, err := s.CreateJobsIdempotent(context.Background(), "other-client key-1", other); err != nil || replayed {
+		t.Errorf("other client: replayed=%v, err=%v", replayed, err)
+	}
+
+	jobs, err := s.ListJobs("", 0)
+	if err != nil || len(jobs) != 2 {
+		t.Fatalf("got %d jobs (err %v), want 2", len(jobs), err)
+	}
+	for _, job := range jobs {
+		waitForStatus(t, s, job.JobID)
+	}
+}
+
+func TestIdempotencyKeyExpiresAfterJobsFinish(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	if err := s.SetIdempotencyGrace(time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	params := models.AnalysisParams{UniProtIDs: "P12345"}
+
+	first, _, err := s.CreateJobsIdempotent(context.Background(), "key", params)
+	if err != nil {
+		t.Fatalf("CreateJobsIdempotent: %v", err)
+	}
+	waitForStatus(t, s, first.Jobs[0].JobID)
+	time.Sleep(10 * time.Millisecond)
+
+	second, replayed, err := s.CreateJobsIdempotent(context.Background(), "key", params)
+	if err != nil || replayed || second.BatchID == first.BatchID {
+		t.Fatalf("after grace: replayed=%v, err=%v, want a new batch", replayed, err)
+	}
+	waitForStatus(t, s, second.Jobs[0].JobID)
+
+	if err := s.SetIdempotencyGrace(0); err == nil {
+		t.Error("SetIdempotencyGrace(0) should fail")
+	}
+}
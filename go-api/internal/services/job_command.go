@@ -0,0 +1,90 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// jobCommandFile は実行した CLI の呼び出しを保存するファイル名
+const jobCommandFile = "command.json"
+
+// ErrNoJobCommand はジョブがまだ実行されていない（command.json が無い）場合のエラー
+var ErrNoJobCommand = errors.New("job command not recorded yet")
+
+// commandEnvKeys は command.json に残す環境変数（API キーなどの秘密を含めないよう明示的に列挙する）
+var commandEnvKeys = []string{"PATH", "PYTHONPATH", "PYTHONHOME", "VIRTUAL_ENV", "CONDA_PREFIX"}
+
+// newJobCommand は argv・作業ディレクトリ・環境変数から JobCommand を作る
+// env に同じキーが複数あれば os/exec と同じく後のものを使う
+func newJobCommand(argv []string, dir string, env []string) models.JobCommand {
+	if dir == "" {
+		dir = "."
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	values := make(map[string]string)
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			values[key] = value
+		}
+	}
+	cmdEnv := make(map[string]string)
+	for _, key := range commandEnvKeys {
+		if value, ok := values[key]; ok {
+			cmdEnv[key] = value
+		}
+	}
+
+	return models.JobCommand{
+		Args: append([]string(nil), argv...),
+		Dir:  dir,
+		Env:  cmdEnv,
+	}
+}
+
+// saveJobCommand は実行する CLI の呼び出しを command.json に保存
+func (s *JobService) saveJobCommand(jobID string, cmd models.JobCommand) error {
+	data, err := json.MarshalIndent(cmd, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	if err := writeBytes(s.storage, jobID, jobCommandFile, data); err != nil {
+		return fmt.Errorf("failed to write command: %w", err)
+	}
+
+	return nil
+}
+
+// GetJobCommand はジョブを実行した CLI の呼び出しを取得
+func (s *JobService) GetJobCommand(jobID string) (*models.JobCommand, error) {
+	if !IsValidJobID(jobID) {
+		return nil, fmt.Errorf("%w: invalid job id: %s", ErrJobNotFound, jobID)
+	}
+
+	data, err := s.storage.ReadFile(jobID, jobCommandFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read command: %w", err)
+		}
+		if _, statusErr := s.GetJobStatus(jobID); statusErr != nil {
+			return nil, statusErr
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNoJobCommand, jobID)
+	}
+
+	var cmd models.JobCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return nil, fmt.Errorf("failed to parse command: %w", err)
+	}
+
+	return &cmd, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestJobCommandIsRecorded(t *testing.T) {
+	t.Setenv("API_KEYS", "secret")
+	runner := &FakeRunner{Files: summaryFixture()}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	cmd, err := s.GetJobCommand(job.JobID)
+	if err != nil {
+		t.Fatalf("GetJobCommand: %v", err)
+	}
+	calls := runner.Calls()
+	if len(calls) != 1 || len(cmd.Args) != len(calls[0]) || argValue(cmd.Args, "--uniprot-ids") != "P12345" {
+		t.Errorf("recorded args %v, ran %v", cmd.Args, calls)
+	}
+	if cmd.Dir == "" {
+		t.Error("working directory was not recorded")
+	}
+	if cmd.Env["PYTHONPATH"] != "./src" {
+		t.Errorf("PYTHONPATH = %q, want ./src", cmd.Env["PYTHONPATH"])
+	}
+	if _, ok := cmd.Env["API_KEYS"]; ok {
+		t.Error("unrelated environment variable was recorded")
+	}
+}
+
+func TestGetJobCommandBeforeRun(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{}, nil)
+	job, _, err := s.prepareJob(models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("prepareJob: %v", err)
+	}
+
+	if _, err := s.GetJobCommand(job.JobID); !errors.Is(err, ErrNoJobCommand) {
+		t.Errorf("got %v, want ErrNoJobCommand", err)
+	}
+}
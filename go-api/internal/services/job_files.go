@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// jobFileTypes は拡張子ごとの Content-Type（ここに無いものは application/octet-stream）
+var jobFileTypes = map[string]string{
+	".csv":  "text/csv",
+	".json": "application/json",
+	".png":  "image/png",
+	".txt":  "text/plain",
+	".log":  "text/plain",
+	".cif":  "chemical/x-cif",
+}
+
+// jobFileType は name の拡張子から Content-Type を判定する
+func jobFileType(name string) string {
+	if t, ok := jobFileTypes[strings.ToLower(path.Ext(name))]; ok {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// ListJobFiles はジョブ配下の出力ファイルを名前順に返す（ZIP と同じく内部の状態ファイルは除く）
+func (s *JobService) ListJobFiles(jobID string) ([]models.JobFile, error) {
+	if _, err := s.GetJobStatus(jobID); err != nil {
+		return nil, err
+	}
+
+	files, err := s.storage.List(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job files: %w", err)
+	}
+
+	result := make([]models.JobFile, 0, len(files))
+	for _, f := range files {
+		if archiveExcludes[f.Name] {
+			continue
+		}
+		result = append(result, models.JobFile{Name: f.Name, Size: f.Size, Type: jobFileType(f.Name)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
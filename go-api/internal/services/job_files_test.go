@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestListJobFiles(t *testing.T) {
+	runner := &FakeRunner{Files: map[string]string{
+		"summary.csv":                 "uniprotid\nP12345\n",
+		"P12345_0.2_heatmap.png":      "png",
+		"P12345_0.2_cis_nor+sub.csv":  "cis",
+		"P12345_0.2_summary.txt":      "summary",
+		"nested/P12345_0.2_extra.bin": "bin",
+	}}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	if status := waitForStatus(t, s, job.JobID); status.Status != "completed" {
+		t.Fatalf("got status %q, want completed", status.Status)
+	}
+
+	files, err := s.ListJobFiles(job.JobID)
+	if err != nil {
+		t.Fatalf("ListJobFiles: %v", err)
+	}
+	got := make(map[string]models.JobFile)
+	for i, f := range files {
+		if i > 0 && files[i-1].Name >= f.Name {
+			t.Errorf("files not sorted: %q before %q", files[i-1].Name, f.Name)
+		}
+		got[f.Name] = f
+	}
+
+	for name, wantType := range map[string]string{
+		"summary.csv":                 "text/csv",
+		"P12345_0.2_heatmap.png":      "image/png",
+		"P12345_0.2_summary.txt":      "text/plain",
+		"nested/P12345_0.2_extra.bin": "application/octet-stream",
+		"params.json":                 "application/json",
+	} {
+		f, ok := got[name]
+		if !ok {
+			t.Errorf("%s not listed", name)
+			continue
+		}
+		if f.Type != wantType {
+			t.Errorf("%s type = %q, want %q", name, f.Type, wantType)
+		}
+	}
+	if got["P12345_0.2_heatmap.png"].Size != 3 {
+		t.Errorf("heatmap size = %d, want 3", got["P12345_0.2_heatmap.png"].Size)
+	}
+	if _, ok := got["status.json"]; ok {
+		t.Error("status.json should not be listed")
+	}
+
+	if _, err := s.ListJobFiles("missing-job"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("got %v, want ErrJobNotFound", err)
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// ErrInvalidCursor は ?cursor= が解釈できない場合のエラー
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// JobListQuery はジョブ一覧の絞り込みとページングの条件
+type JobListQuery struct {
+	Status    string // 空なら全ステータス
+	UniProtID string // 空でなければ保存済みパラメータにこの UniProt ID を含むジョブのみ
+	Cursor    string // 前のページの next_cursor（空なら先頭から）
+	Limit     int    // 0 なら残り全件
+}
+
+// ListJobsPage は条件に一致するジョブを作成日時の降順で1ページ分返す
+// カーソルは最後に返したジョブの (CreatedAt, JobID) なので、ページ間でジョブが増減しても重複・欠落しない
+func (s *JobService) ListJobsPage(q JobListQuery) (*models.JobListPage, error) {
+	var after *jobCursor
+	if q.Cursor != "" {
+		cursor, err := decodeJobCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = &cursor
+	}
+
+	jobs, err := s.ListJobs(q.Status, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.JobListPage{Jobs: []models.JobStatus{}}
+	for _, job := range jobs {
+		if after != nil && !after.precedes(job) {
+			continue
+		}
+		// params.json はカーソル・ステータスで絞った後、必要な分だけ読む
+		if q.UniProtID != "" && !s.jobHasUniProtID(job.JobID, q.UniProtID) {
+			continue
+		}
+		if q.Limit > 0 && len(page.Jobs) == q.Limit {
+			page.NextCursor = encodeJobCursor(page.Jobs[len(page.Jobs)-1])
+			break
+		}
+		page.Jobs = append(page.Jobs, job)
+	}
+
+	return page, nil
+}
+
+// jobHasUniProtID は保存済みパラメータの uniprot_ids に uniprotID が含まれるか（大文字小文字は区別しない）
+func (s *JobService) jobHasUniProtID(jobID, uniprotID string) bool {
+	params, err := s.GetJobParams(jobID)
+	if err != nil {
+		return false
+	}
+	for _, id := range models.SplitUniProtIDs(params.UniProtIDs) {
+		if strings.EqualFold(id, uniprotID) {
+			return true
+		}
+	}
+	return false
+}
+
+// jobCursor は一覧上の位置（このジョブより後ろから再開する）
+type jobCursor struct {
+	CreatedAt time.Time
+	JobID     string
+}
+
+// precedes は一覧の並び（CreatedAt 降順、同時刻なら JobID 降順）で job がカーソルより後ろにあるか
+func (c jobCursor) precedes(job models.JobStatus) bool {
+	if !job.CreatedAt.Equal(c.CreatedAt) {
+		return job.CreatedAt.Before(c.CreatedAt)
+	}
+	return job.JobID < c.JobID
+}
+
+// encodeJobCursor は "{CreatedAt の UnixNano}_{job_id}" を URL セーフな base64 にする
+func encodeJobCursor(job models.JobStatus) string {
+	raw := strconv.FormatInt(job.CreatedAt.UnixNano(), 10) + "_" + job.JobID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeJobCursor(cursor string) (jobCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return jobCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	nanos, jobID, ok := strings.Cut(string(raw), "_")
+	if !ok || jobID == "" {
+		return jobCursor{}, fmt.Errorf("%w: malformed", ErrInvalidCursor)
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return jobCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return jobCursor{CreatedAt: time.Unix(0, n), JobID: jobID}, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestListJobsPageFiltersAndPaginates(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+
+	// 同時刻のジョブを含めて作成（want は一覧の並び順）
+	base := time.Now()
+	var want []string
+	for i, ids := range []string{"P12345", "Q67890", "P12345,Q67890", "Q67890 P12345", "P12345"} {
+		job, _, err := s.prepareJob(models.AnalysisParams{UniProtIDs: ids})
+		if err != nil {
+			t.Fatalf("prepareJob: %v", err)
+		}
+		createdAt := base.Add(-time.Duration(i/2) * time.Minute)
+		if err := s.saveJobStatus(job.JobID, models.JobStatus{JobID: job.JobID, Status: "completed", CreatedAt: createdAt, UpdatedAt: createdAt}); err != nil {
+			t.Fatalf("saveJobStatus: %v", err)
+		}
+		if ids != "Q67890" {
+			want = append(want, job.JobID)
+		}
+	}
+
+	all, err := s.ListJobsPage(JobListQuery{UniProtID: "p12345"})
+	if err != nil {
+		t.Fatalf("ListJobsPage: %v", err)
+	}
+	if len(all.Jobs) != len(want) || all.NextCursor != "" {
+		t.Fatalf("got %d jobs (next_cursor %q), want %d and no cursor", len(all.Jobs), all.NextCursor, len(want))
+	}
+
+	var paged []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > len(want) {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := s.ListJobsPage(JobListQuery{UniProtID: "P12345", Cursor: cursor, Limit: 2})
+		if err != nil {
+			t.Fatalf("ListJobsPage: %v", err)
+		}
+		for _, job := range page.Jobs {
+			paged = append(paged, job.JobID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if len(paged) != len(all.Jobs) {
+		t.Fatalf("paged %d jobs, want %d", len(paged), len(all.Jobs))
+	}
+	for i, job := range all.Jobs {
+		if paged[i] != job.JobID {
+			t.Errorf("job %d: paged %s, unpaged %s", i, paged[i], job.JobID)
+		}
+	}
+
+	if _, err := s.ListJobsPage(JobListQuery{Cursor: "not a cursor!"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("got %v, want ErrInvalidCursor", err)
+	}
+}
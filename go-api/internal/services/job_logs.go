@@ -0,0 +1,74 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+)
+
+// jobLogFile は Python の標準出力/エラー出力を実行順に保存するファイル名
+const jobLogFile = "output.log"
+
+// ErrNoJobLogs はジョブのログがまだ無い（実行前・実行中）場合のエラー
+var ErrNoJobLogs = errors.New("job logs not available yet")
+
+// jobLog は Python の出力行を標準出力/エラー出力の区別なく届いた順に溜める
+type jobLog struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *jobLog) writeLine(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf.WriteString(line)
+	l.buf.WriteByte('\n')
+}
+
+func (l *jobLog) bytes() []byte {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]byte(nil), l.buf.Bytes()...)
+}
+
+// GetJobLogs は保存済みの Python の出力を返す（tail > 0 なら最後の tail 行のみ）
+func (s *JobService) GetJobLogs(jobID string, tail int) ([]byte, error) {
+	if !IsValidJobID(jobID) {
+		return nil, fmt.Errorf("%w: invalid job id: %s", ErrJobNotFound, jobID)
+	}
+
+	data, err := s.storage.ReadFile(jobID, jobLogFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read logs: %w", err)
+		}
+		if _, statusErr := s.GetJobStatus(jobID); statusErr != nil {
+			return nil, statusErr
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNoJobLogs, jobID)
+	}
+
+	if tail > 0 {
+		data = tailLines(data, tail)
+	}
+	return data, nil
+}
+
+// tailLines は data の最後の n 行を返す（末尾の改行は行として数えない）
+func tailLines(data []byte, n int) []byte {
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+	for i := end - 1; i >= 0; i-- {
+		if data[i] == '\n' {
+			n--
+			if n == 0 {
+				return data[i+1:]
+			}
+		}
+	}
+	return data
+}
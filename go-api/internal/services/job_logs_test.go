@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestJobLogsAreSavedOnSuccessAndFailure(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		runner *FakeRunner
+		status string
+	}{
+		{"completed", &FakeRunner{Files: summaryFixture(), Output: "step 1\nstep 2", Stderr: "warning"}, "completed"},
+		{"failed", &FakeRunner{Output: "step 1\nstep 2", Stderr: "warning", Err: errors.New("exit status 1")}, "failed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewJobService(t.TempDir(), "python3", "", tc.runner, nil)
+			job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+			if status := waitForStatus(t, s, job.JobID); status.Status != tc.status {
+				t.Fatalf("got status %q, want %q", status.Status, tc.status)
+			}
+
+			logs, err := s.GetJobLogs(job.JobID, 0)
+			if err != nil {
+				t.Fatalf("GetJobLogs: %v", err)
+			}
+			if want := "step 1\nstep 2\nwarning\n"; string(logs) != want {
+				t.Errorf("logs = %q, want %q", logs, want)
+			}
+
+			logs, err = s.GetJobLogs(job.JobID, 2)
+			if err != nil {
+				t.Fatalf("GetJobLogs tail: %v", err)
+			}
+			if want := "step 2\nwarning\n"; string(logs) != want {
+				t.Errorf("tail logs = %q, want %q", logs, want)
+			}
+		})
+	}
+}
+
+func TestGetJobLogsBeforeRun(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{}, nil)
+	job, _, err := s.prepareJob(models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("prepareJob: %v", err)
+	}
+
+	if _, err := s.GetJobLogs(job.JobID, 0); !errors.Is(err, ErrNoJobLogs) {
+		t.Errorf("got %v, want ErrNoJobLogs", err)
+	}
+	if _, err := s.GetJobLogs("missing-job", 0); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("got %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	tests := []struct {
+		data string
+		n    int
+		want string
+	}{
+		{"a\nb\nc\n", 2, "b\nc\n"},
+		{"a\nb\nc", 2, "b\nc"},
+		{"a\nb\n", 5, "a\nb\n"},
+		{"", 3, ""},
+	}
+	for _, tt := range tests {
+		if got := string(tailLines([]byte(tt.data), tt.n)); got != tt.want {
+			t.Errorf("tailLines(%q, %d) = %q, want %q", tt.data, tt.n, got, tt.want)
+		}
+	}
+}
@@ -4,52 +4,191 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"math"
+	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/yourusername/flex-api/internal/models"
 )
 
 type JobService struct {
-	storageDir string
-	mu         sync.RWMutex
-	pythonBin  string
+	logger          *slog.Logger
+	storageDir      string  // ローカルの作業ディレクトリ（Python の出力先）
+	storage         Storage // ジョブ成果物の保存先（既定は storageDir そのもの）
+	layout          FileLayout // Python エンジンが書く成果物の名前
+	defaults        models.AnalysisDefaults // 未指定のパラメータに入れる値
+	sharded         bool    // ジョブディレクトリを ID 先頭 2 文字のサブディレクトリに分けるか
+	mu              sync.RWMutex
+	pythonBin       string
+	pythonEngineDir string   // Python CLI の作業ディレクトリ（python-engine）
+	pythonExtraEnv  []string // Python CLI に追加で渡す環境変数（"KEY=VALUE"）
+	runner          Runner
+	jobIDFormat     string                        // "uuid" | "short"
+	subprocessNice  int                           // Pythonサブプロセスのniceness
+	subprocessCPUs  string                        // Pythonサブプロセスを固定するCPUセット
+	running         map[string]time.Time          // 実行中の解析（job_id → 開始時刻）
+	owned           map[string]struct{}           // このインスタンスが受け付けて、まだ実行を終えていないジョブ
+	cancels         map[string]context.CancelFunc // 実行中ジョブのキャンセル関数
+	workers         chan struct{}                 // 同時実行数を制限するセマフォ
+	waiting         []*queuedJob                  // 実行枠の空き待ちジョブ（並んだ順、渡す順は queueOrder）
+	subMu           sync.Mutex
+	subscribers     map[string][]chan models.JobStatus // ステータス変更の購読者
+
+	batchConcurrencyCap int           // バッチ内の同時実行数の上限
+	closing             bool          // Shutdown 開始後は新しいジョブを実行しない
+	jobTTL              time.Duration // 終了したジョブを保持する期間（0 は無期限）
+	jobTimeout          time.Duration // Python CLI 1回の実行の制限時間
+	keepPDBFiles        bool          // ジョブの終了後も構造ファイル（pdb_files）を残すか
+
+	idemMu              sync.Mutex
+	idempotencyKeys     map[string]*idempotencyEntry // Idempotency-Key → 最初のリクエストで作成したジョブ
+	idempotencyGrace    time.Duration                // 冪等キーをジョブの終了後に保持する期間
+	idempotencyPrunedAt time.Time                    // 最後に期限切れのキーを掃除した時刻
+
+	webhookClient  *http.Client  // callback_url への通知用
+	webhookBackoff time.Duration // 通知の再試行間隔（初回）
+
+	resultCache    *resultCache // 解析済み結果の LRU キャッシュ
+	resultTopPairs int          // GET /result で返すペア数の既定上限（0 は全件）
+	metrics        *metrics     // /metrics で公開する統計
+
+	downloadRetries    int           // ダウンロード失敗時の再実行回数
+	downloadRetryDelay time.Duration // 最初の再実行までの待ち時間
+
+	maxResidues   int // 解析する残基数の上限（0 は無制限）
+	maxStructures int // 解析する PDB エントリ数の上限（0 は無制限）
+
+	readyMu         sync.Mutex
+	pythonErr       error     // 直近の flex_analyzer import 確認の結果
+	pythonCheckedAt time.Time // 直近の確認時刻（ゼロなら未確認）
 }
 
-func NewJobService(storageDir, pythonBin string) *JobService {
+// ErrJobNotCompleted はジョブがまだ completed でない場合のエラー
+// 実際のステータスは JobNotCompletedError から取得できる
+var ErrJobNotCompleted = errors.New("job not completed")
+
+// JobNotCompletedError は完了していないジョブの結果を要求した場合のエラー
+type JobNotCompletedError struct {
+	Status string
+}
+
+func (e *JobNotCompletedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrJobNotCompleted, e.Status)
+}
+
+func (e *JobNotCompletedError) Is(target error) bool {
+	return target == ErrJobNotCompleted
+}
+
+// ErrInvalidParams は解析パラメータが Validate を通らなかった場合のエラー
+// 検証結果（InvalidUniProtIDsError など）は InvalidParamsError から取得できる
+var ErrInvalidParams = errors.New("invalid analysis params")
+
+// InvalidParamsError は Validate のエラーをそのままのメッセージで包む
+type InvalidParamsError struct {
+	Err error
+}
+
+func (e *InvalidParamsError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InvalidParamsError) Unwrap() error {
+	return e.Err
+}
+
+func (e *InvalidParamsError) Is(target error) bool {
+	return target == ErrInvalidParams
+}
+
+// validateParams はジョブを作る入口（CreateJobs / CreateJob / DryRun）で1回だけ呼ぶ
+func validateParams(params models.AnalysisParams) error {
+	if err := params.Validate(); err != nil {
+		return &InvalidParamsError{Err: err}
+	}
+	return nil
+}
+
+func NewJobService(storageDir, pythonBin, pythonEngineDir string, runner Runner, logger *slog.Logger) *JobService {
+	if logger == nil {
+		logger = slog.Default()
+	}
 	if pythonBin == "" {
 		pythonBin = "python3"
 	}
+	if runner == nil {
+		runner = ExecRunner{}
+	}
 	return &JobService{
-		storageDir: storageDir,
-		pythonBin:  pythonBin,
+		logger:          logger,
+		storageDir:      storageDir,
+		storage:         NewLocalStorage(storageDir),
+		pythonBin:       pythonBin,
+		pythonEngineDir: pythonEngineDir,
+		runner:          runner,
+		jobIDFormat:     JobIDFormatUUID,
+		jobTimeout:      defaultJobTimeout,
+		running:         make(map[string]time.Time),
+		owned:           make(map[string]struct{}),
+		cancels:         make(map[string]context.CancelFunc),
+		layout:          defaultFileLayout,
+		defaults:        defaultAnalysisDefaults,
+		workers:         make(chan struct{}, defaultMaxConcurrent),
+		subscribers:     make(map[string][]chan models.JobStatus),
+
+		batchConcurrencyCap: defaultBatchConcurrencyCap,
+
+		idempotencyKeys:  make(map[string]*idempotencyEntry),
+		idempotencyGrace: defaultIdempotencyGrace,
+
+		webhookClient:  &http.Client{Timeout: webhookTimeout},
+		webhookBackoff: defaultWebhookBackoff,
+
+		downloadRetries:    defaultDownloadRetries,
+		downloadRetryDelay: defaultDownloadRetryDelay,
+
+		maxResidues:   defaultMaxResidues,
+		maxStructures: defaultMaxStructures,
+
+		resultCache:    newResultCache(defaultResultCacheSize),
+		resultTopPairs: defaultResultTopPairs,
+		metrics:        newMetrics(),
 	}
 }
 
-// ★ heatmap エンドポイント用：storageDir を公開
+// StorageDir はローカルの作業ディレクトリを返す
 func (s *JobService) StorageDir() string {
 	return s.storageDir
 }
 
 // CreateJobs は複数のUniProt IDを分割してそれぞれ別のジョブとして作成
-func (s *JobService) CreateJobs(params models.AnalysisParams) (*models.JobsResponse, error) {
+// 同時に実行されるのは concurrency 件までで、残りはバッチ内で待機させる
+func (s *JobService) CreateJobs(ctx context.Context, params models.AnalysisParams) (*models.JobsResponse, error) {
+	// ジョブディレクトリを作る前に検証
+	if err := validateParams(params); err != nil {
+		return nil, err
+	}
+
 	// UniProt IDを分割（カンマまたはスペース区切り）
-	ids := splitUniProtIDs(params.UniProtIDs)
-	
-	if len(ids) == 0 {
-		return nil, fmt.Errorf("no UniProt IDs provided")
+	ids := models.SplitUniProtIDs(params.UniProtIDs)
+
+	limit, err := s.batchConcurrency(params.Concurrency)
+	if err != nil {
+		return nil, err
 	}
 
 	var jobs []models.JobResponse
+	var pending []batchJob
 	createdAt := time.Now()
 
 	// 各UniProt IDに対してジョブを作成
@@ -58,172 +197,182 @@ func (s *JobService) CreateJobs(params models.AnalysisParams) (*models.JobsRespo
 		singleParams := params
 		singleParams.UniProtIDs = uniprotID
 
-		job, err := s.CreateJob(singleParams)
+		job, jobParams, err := s.prepareJob(singleParams)
 		if err != nil {
 			// エラーが発生した場合でも、作成済みのジョブは返す
-			fmt.Printf("[ERROR] CreateJobs - Failed to create job for %s: %v\n", uniprotID, err)
+			s.logger.Error("CreateJobs: failed to create job", "request_id", RequestIDFromContext(ctx), "uniprot_id", uniprotID, "error", err)
 			continue
 		}
 
+		if len(pending) >= limit {
+			s.updateJobStatus(job.JobID, "pending", 0, "Waiting in batch queue")
+		}
+
+		job.UniProtID = uniprotID
 		jobs = append(jobs, *job)
+		pending = append(pending, batchJob{jobID: job.JobID, params: jobParams, logger: s.jobLogger(ctx, job.JobID)})
 	}
 
 	if len(jobs) == 0 {
 		return nil, fmt.Errorf("failed to create any jobs")
 	}
 
+	batchID, err := s.newJobID()
+	if err != nil {
+		return nil, err
+	}
+	// 再起動後もバッチ単位で集計できるよう、子ジョブの一覧を保存先に残す
+	if err := s.saveBatch(batchID, jobs, createdAt); err != nil {
+		s.logger.Warn("CreateJobs: failed to save batch.json", "request_id", RequestIDFromContext(ctx), "batch_id", batchID, "error", err)
+	}
+
+	go s.runBatch(pending, limit)
+
 	return &models.JobsResponse{
+		BatchID:   batchID,
 		Jobs:      jobs,
 		CreatedAt: createdAt,
 	}, nil
 }
 
-// splitUniProtIDs はUniProt ID文字列を分割（カンマまたはスペース区切り）
-func splitUniProtIDs(idsStr string) []string {
-	// カンマまたはスペースで分割
-	re := regexp.MustCompile(`[,\s]+`)
-	parts := re.Split(strings.TrimSpace(idsStr), -1)
-	
-	var result []string
-	for _, part := range parts {
-		trimmed := strings.TrimSpace(part)
-		if trimmed != "" {
-			result = append(result, trimmed)
-		}
-	}
-	
-	return result
-}
-
 // CreateJob は新しいジョブを作成（単一のUniProt ID用）
-func (s *JobService) CreateJob(params models.AnalysisParams) (*models.JobResponse, error) {
-	// デバッグ: 受け取ったパラメータをログ出力
-	fmt.Printf("[DEBUG] CreateJob - Received params:\n")
-	fmt.Printf("  UniProtIDs: %s\n", params.UniProtIDs)
-	if params.Method != nil {
-		fmt.Printf("  Method: %s (pointer)\n", *params.Method)
-	} else {
-		fmt.Printf("  Method: nil\n")
+func (s *JobService) CreateJob(ctx context.Context, params models.AnalysisParams) (*models.JobResponse, error) {
+	if err := validateParams(params); err != nil {
+		return nil, err
 	}
-	if params.SeqRatio != nil {
-		fmt.Printf("  SeqRatio: %f (pointer)\n", *params.SeqRatio)
-	} else {
-		fmt.Printf("  SeqRatio: nil\n")
+
+	job, params, err := s.prepareJob(params)
+	if err != nil {
+		return nil, err
 	}
-	if params.NegativePDBID != nil {
-		fmt.Printf("  NegativePDBID: %s (pointer)\n", *params.NegativePDBID)
-	} else {
-		fmt.Printf("  NegativePDBID: nil\n")
+
+	// 非同期で解析実行
+	go s.executeDSAAnalysis(job.JobID, params, s.jobLogger(ctx, job.JobID))
+
+	return job, nil
+}
+
+// prepareJob はデフォルト値を補完し、ジョブディレクトリと初期ステータスを作成（解析は開始しない）
+// params は呼び出し側（CreateJobs / CreateJob）で検証済みであること
+func (s *JobService) prepareJob(params models.AnalysisParams) (*models.JobResponse, models.AnalysisParams, error) {
+	s.logger.Debug("CreateJob: received params", "params", params)
+
+	params = s.applyDefaultParams(params)
+
+	// ジョブID生成
+	jobID, err := s.newJobID()
+	if err != nil {
+		return nil, params, err
 	}
-	if params.CisThreshold != nil {
-		fmt.Printf("  CisThreshold: %f (pointer)\n", *params.CisThreshold)
-	} else {
-		fmt.Printf("  CisThreshold: nil\n")
+
+	// ステータス初期化
+	status := models.JobStatus{
+		JobID:     jobID,
+		Status:    "pending",
+		Progress:  0,
+		Message:   "Job created",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
-	if params.Export != nil {
-		fmt.Printf("  Export: %t (pointer)\n", *params.Export)
-	} else {
-		fmt.Printf("  Export: nil\n")
+
+	if err := s.saveJobStatus(jobID, status); err != nil {
+		return nil, params, err
 	}
-	if params.Heatmap != nil {
-		fmt.Printf("  Heatmap: %t (pointer)\n", *params.Heatmap)
-	} else {
-		fmt.Printf("  Heatmap: nil\n")
+	// 実行はこのインスタンスが受け持つ（Shutdown で interrupted にするのはこのジョブだけ）
+	s.mu.Lock()
+	s.owned[jobID] = struct{}{}
+	s.mu.Unlock()
+
+	// デフォルト補完後のパラメータを保存（ダウンロード名の生成などで使用）
+	if err := s.saveJobParams(jobID, params); err != nil {
+		return nil, params, err
 	}
-	if params.ProcCis != nil {
-		fmt.Printf("  ProcCis: %t (pointer)\n", *params.ProcCis)
-	} else {
-		fmt.Printf("  ProcCis: nil\n")
+	s.metrics.jobCreated()
+
+	return &models.JobResponse{
+		JobID:     jobID,
+		Status:    status.Status,
+		CreatedAt: status.CreatedAt,
+	}, params, nil
+}
+
+// applyDefaultParams は未指定のパラメータにデフォルト値を補完し、PDB ID を正規化する
+func (s *JobService) applyDefaultParams(params models.AnalysisParams) models.AnalysisParams {
+	// 除外する PDB ID は大文字・スペース区切りに正規化して Python に渡す
+	if params.NegativePDBID != nil {
+		normalized := models.NormalizePDBIDs(*params.NegativePDBID)
+		params.NegativePDBID = &normalized
 	}
-	if params.Overwrite != nil {
-		fmt.Printf("  Overwrite: %t (pointer)\n", *params.Overwrite)
-	} else {
-		fmt.Printf("  Overwrite: nil\n")
+	if len(params.PDBIDs) > 0 {
+		params.PDBIDs = models.NormalizePDBIDList(params.PDBIDs)
 	}
 
-	// デフォルト値設定
+	// デフォルト値設定（未指定の項目のみ、値は s.defaults）
 	if params.Method == nil || *params.Method == "" {
-		defaultMethod := "X-ray"
+		defaultMethod := s.defaults.Method
 		params.Method = &defaultMethod
-		fmt.Printf("[DEBUG] CreateJob - Set default Method: %s\n", defaultMethod)
+		s.logger.Debug("CreateJob: set default", "param", "method", "value", defaultMethod)
 	}
-	if params.SeqRatio == nil || *params.SeqRatio <= 0 || *params.SeqRatio > 1 {
-		defaultSeqRatio := 0.2
+	if params.Priority == nil || *params.Priority == "" {
+		defaultPriority := models.PriorityNormal
+		params.Priority = &defaultPriority
+	}
+	if params.SeqRatio == nil {
+		defaultSeqRatio := s.defaults.SeqRatio
 		params.SeqRatio = &defaultSeqRatio
-		fmt.Printf("[DEBUG] CreateJob - Set default SeqRatio: %f\n", defaultSeqRatio)
+		s.logger.Debug("CreateJob: set default", "param", "seq_ratio", "value", defaultSeqRatio)
 	}
-	if params.CisThreshold == nil || *params.CisThreshold <= 0 {
-		defaultCisThreshold := 3.3
+	if params.CisThreshold == nil {
+		defaultCisThreshold := s.defaults.CisThreshold
 		params.CisThreshold = &defaultCisThreshold
-		fmt.Printf("[DEBUG] CreateJob - Set default CisThreshold: %f\n", defaultCisThreshold)
+		s.logger.Debug("CreateJob: set default", "param", "cis_threshold", "value", defaultCisThreshold)
 	}
 	if params.NegativePDBID == nil {
 		emptyStr := ""
 		params.NegativePDBID = &emptyStr
-		fmt.Printf("[DEBUG] CreateJob - Set default NegativePDBID: (empty)\n")
+		s.logger.Debug("CreateJob: set default", "param", "negative_pdbid", "value", "")
 	}
 	if params.Export == nil {
-		defaultExport := true
+		defaultExport := s.defaults.Export
 		params.Export = &defaultExport
-		fmt.Printf("[DEBUG] CreateJob - Set default Export: %t\n", defaultExport)
+		s.logger.Debug("CreateJob: set default", "param", "export", "value", defaultExport)
 	}
 	if params.Heatmap == nil {
-		defaultHeatmap := true
+		defaultHeatmap := s.defaults.Heatmap
 		params.Heatmap = &defaultHeatmap
-		fmt.Printf("[DEBUG] CreateJob - Set default Heatmap: %t\n", defaultHeatmap)
+		s.logger.Debug("CreateJob: set default", "param", "heatmap", "value", defaultHeatmap)
 	}
 	if params.ProcCis == nil {
-		defaultProcCis := true
+		defaultProcCis := s.defaults.ProcCis
 		params.ProcCis = &defaultProcCis
-		fmt.Printf("[DEBUG] CreateJob - Set default ProcCis: %t\n", defaultProcCis)
+		s.logger.Debug("CreateJob: set default", "param", "proc_cis", "value", defaultProcCis)
 	}
 	if params.Overwrite == nil {
-		defaultOverwrite := true
+		defaultOverwrite := s.defaults.Overwrite
 		params.Overwrite = &defaultOverwrite
-		fmt.Printf("[DEBUG] CreateJob - Set default Overwrite: %t\n", defaultOverwrite)
-	}
-
-	// ジョブID生成
-	jobID := uuid.New().String()
-
-	// ジョブディレクトリ作成
-	jobDir := filepath.Join(s.storageDir, jobID)
-	if err := os.MkdirAll(jobDir, 0o755); err != nil {
-		return nil, fmt.Errorf("failed to create job directory: %w", err)
-	}
-
-	// ステータス初期化
-	status := models.JobStatus{
-		JobID:     jobID,
-		Status:    "pending",
-		Progress:  0,
-		Message:   "Job created",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		s.logger.Debug("CreateJob: set default", "param", "overwrite", "value", defaultOverwrite)
 	}
 
-	if err := s.saveJobStatus(jobID, status); err != nil {
-		return nil, err
-	}
-
-	// 非同期で解析実行
-	go s.executeDSAAnalysis(jobID, params)
-
-	return &models.JobResponse{
-		JobID:     jobID,
-		Status:    status.Status,
-		CreatedAt: status.CreatedAt,
-	}, nil
+	return params
 }
 
 // GetJobStatus はジョブの状態を取得
 func (s *JobService) GetJobStatus(jobID string) (*models.JobStatus, error) {
-	statusPath := filepath.Join(s.storageDir, jobID, "status.json")
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.readStatus(jobID)
+}
 
-	data, err := os.ReadFile(statusPath)
+// readStatus は status.json を読み込む（呼び出し側で s.mu を保持すること）
+func (s *JobService) readStatus(jobID string) (*models.JobStatus, error) {
+	if !IsValidJobID(jobID) {
+		return nil, fmt.Errorf("%w: invalid job id: %s", ErrJobNotFound, jobID)
+	}
+
+	data, err := s.storage.ReadFile(jobID, "status.json")
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("job not found: %s", jobID)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
 		}
 		return nil, fmt.Errorf("failed to read status: %w", err)
 	}
@@ -233,69 +382,172 @@ func (s *JobService) GetJobStatus(jobID string) (*models.JobStatus, error) {
 		return nil, fmt.Errorf("failed to parse status: %w", err)
 	}
 
+	setStatusTimings(&status, time.Now())
+	s.setQueuePosition(&status)
 	return &status, nil
 }
 
+// setStatusTimings は経過時間と終了日時を埋める
+// 終了状態なら最後の更新（終了時点）で止め、pending ではまだ走っていないので埋めない
+func setStatusTimings(status *models.JobStatus, now time.Time) {
+	status.DurationSeconds = nil
+	status.CompletedAt = nil
+	if status.Status == "pending" {
+		return
+	}
+
+	end := now
+	if IsTerminalStatus(status.Status) {
+		completedAt := status.UpdatedAt
+		status.CompletedAt = &completedAt
+		end = completedAt
+	}
+	seconds := int64(end.Sub(status.CreatedAt) / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+	status.DurationSeconds = &seconds
+}
+
+// ListJobs はストレージ内の全ジョブのステータスを作成日時の降順で返す
+// status が空でなければそのステータスのジョブのみ、limit > 0 なら先頭 limit 件のみ返す
+// 有効な status.json が無いジョブはスキップする
+func (s *JobService) ListJobs(status string, limit int) ([]models.JobStatus, error) {
+	jobIDs, err := s.storage.Jobs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs in storage: %w", err)
+	}
+
+	jobs := []models.JobStatus{}
+	for _, jobID := range jobIDs {
+		jobStatus, err := s.GetJobStatus(jobID)
+		if err != nil {
+			continue
+		}
+		if status != "" && jobStatus.Status != status {
+			continue
+		}
+		jobs = append(jobs, *jobStatus)
+	}
+
+	// 同時刻のジョブは job_id の降順（カーソルでのページングに必要な全順序）
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+		}
+		return jobs[i].JobID > jobs[j].JobID
+	})
+
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+
+	return jobs, nil
+}
+
 // GetResult はジョブの結果を取得
 func (s *JobService) GetResult(jobID string) (*models.NotebookDSAResult, error) {
-	// デバッグ: ジョブIDをログ出力
-	fmt.Printf("[DEBUG] GetResult - JobID: %s\n", jobID)
+	s.logger.Debug("GetResult", "job_id", jobID)
 
 	// ステータス確認
 	status, err := s.GetJobStatus(jobID)
 	if err != nil {
-		fmt.Printf("[DEBUG] GetResult - Failed to get job status: %v\n", err)
+		s.logger.Debug("GetResult: failed to get job status", "job_id", jobID, "error", err)
 		return nil, err
 	}
 
-	fmt.Printf("[DEBUG] GetResult - Job status: %s\n", status.Status)
+	s.logger.Debug("GetResult: job status", "job_id", jobID, "status", status.Status)
 
 	if status.Status != "completed" {
-		return nil, fmt.Errorf("job not completed: %s", status.Status)
+		return nil, &JobNotCompletedError{Status: status.Status}
 	}
 
-	// Notebook DSAはsummary.csvを出力するため、まずsummary.csvを確認
-	summaryPath := filepath.Join(s.storageDir, jobID, "summary.csv")
-	resultPath := filepath.Join(s.storageDir, jobID, "result.json")
+	// 同じ UpdatedAt の解析済み結果があれば再パースしない
+	if result, ok := s.resultCache.get(jobID, status.UpdatedAt); ok {
+		s.logger.Debug("GetResult: cache hit", "job_id", jobID)
+		return result, nil
+	}
 
-	// result.jsonが存在する場合はそれを読み込む
-	if _, err := os.Stat(resultPath); err == nil {
-		fmt.Printf("[DEBUG] GetResult - Found result.json at: %s\n", resultPath)
-		data, err := os.ReadFile(resultPath)
-		if err != nil {
-			fmt.Printf("[DEBUG] GetResult - Failed to read result.json: %v\n", err)
-			return nil, fmt.Errorf("failed to read result: %w", err)
-		}
+	result, err := s.loadResult(jobID)
+	if err != nil {
+		return nil, err
+	}
+	s.resultCache.put(jobID, status.UpdatedAt, result)
 
+	// キャッシュ上の値を呼び出し側が書き換えないようにコピーを返す
+	copied := *result
+	return &copied, nil
+}
+
+// loadResult は result.json、無ければ summary.csv などの CSV から結果を読み込む
+func (s *JobService) loadResult(jobID string) (*models.NotebookDSAResult, error) {
+	// result.jsonが存在する場合はそれを読み込む
+	data, err := s.storage.ReadFile(jobID, "result.json")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.logger.Debug("GetResult: failed to read result.json", "job_id", jobID, "error", err)
+		return nil, fmt.Errorf("failed to read result: %w", err)
+	}
+	if err == nil {
+		s.logger.Debug("GetResult: found result.json", "job_id", jobID)
+		// Python の json.dumps は NaN/Infinity をそのまま書くので null に置き換えてから読む（null は NaN に戻る）
 		var result models.NotebookDSAResult
-		if err := json.Unmarshal(data, &result); err != nil {
-			fmt.Printf("[DEBUG] GetResult - Failed to parse result.json: %v\n", err)
+		if err := json.Unmarshal(replaceNonFiniteLiterals(data), &result); err != nil {
+			s.logger.Debug("GetResult: failed to parse result.json", "job_id", jobID, "error", err)
 			return nil, fmt.Errorf("failed to parse result: %w", err)
 		}
 
-		fmt.Printf("[DEBUG] GetResult - Successfully loaded result.json\n")
+		s.logger.Debug("GetResult: loaded result.json", "job_id", jobID, "schema_version", result.SchemaVersion)
+		s.migrateResult(jobID, &result)
 		return &result, nil
 	}
 
-	// result.jsonが存在しない場合は、summary.csvから結果を構築
-	if _, err := os.Stat(summaryPath); err == nil {
-		fmt.Printf("[DEBUG] GetResult - Found summary.csv at: %s (converting to NotebookDSAResult)\n", summaryPath)
-		return s.convertSummaryCSVToResult(jobID, summaryPath)
+	// result.jsonが存在しない場合は、summary.csvから結果を構築（Notebook DSAはsummary.csvを出力する）
+	if _, err := s.storage.Stat(jobID, s.layout.Summary); err == nil {
+		s.logger.Debug("GetResult: converting summary.csv", "job_id", jobID)
+		return s.convertSummaryCSVToResult(jobID)
 	}
 
 	// どちらも存在しない場合
-	fmt.Printf("[DEBUG] GetResult - Neither result.json nor summary.csv found\n")
-	return nil, fmt.Errorf("result file not found. Checked: %s and %s", resultPath, summaryPath)
+	s.logger.Debug("GetResult: neither result.json nor summary.csv found", "job_id", jobID)
+	return nil, fmt.Errorf("result file not found for job %s: checked result.json and %s", jobID, s.layout.Summary)
+}
+
+// GetRawSummary はsummary.csvの全列をヘッダー→値のマップとして返す（値は未パースの文字列）
+func (s *JobService) GetRawSummary(jobID string) (map[string]string, error) {
+	file, err := s.storage.Open(jobID, s.layout.Summary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", s.layout.Summary, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summary.csv header: %w", err)
+	}
+	data, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summary.csv row: %w", err)
+	}
+
+	raw := make(map[string]string, len(headers))
+	for i, h := range headers {
+		if i < len(data) {
+			raw[strings.TrimSpace(h)] = data[i]
+		}
+	}
+
+	return raw, nil
 }
 
 // convertSummaryCSVToResult はsummary.csvからNotebookDSAResultを構築
-func (s *JobService) convertSummaryCSVToResult(jobID string, summaryPath string) (*models.NotebookDSAResult, error) {
-	fmt.Printf("[DEBUG] convertSummaryCSVToResult - Reading summary.csv from: %s\n", summaryPath)
+func (s *JobService) convertSummaryCSVToResult(jobID string) (*models.NotebookDSAResult, error) {
+	s.logger.Debug("convertSummaryCSVToResult: reading summary.csv", "job_id", jobID)
 
 	// summary.csvを読み込む
-	file, err := os.Open(summaryPath)
+	file, err := s.storage.Open(jobID, s.layout.Summary)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open summary.csv: %w", err)
+		return nil, fmt.Errorf("failed to open %s: %w", s.layout.Summary, err)
 	}
 	defer file.Close()
 
@@ -309,97 +561,68 @@ func (s *JobService) convertSummaryCSVToResult(jobID string, summaryPath string)
 		return nil, fmt.Errorf("summary.csv has insufficient rows: %d", len(records))
 	}
 
-	// ヘッダーとデータ行を取得
+	// ヘッダーとデータ行を取得し、列名は s.layout.SummaryColumns の別名で引く
 	headers := records[0]
-	data := records[1]
+	row := newSummaryRow(headers, records[1])
+	cols := s.layout.SummaryColumns
+
+	uniprotID := row.String(cols.UniProtID)
+	seqRatio := row.Float(cols.SeqRatio)
+	entries := row.Int(cols.Entries)
+	chains := row.Int(cols.Chains)
+	length := row.Int(cols.Length)
+	lengthPercent := row.Float(cols.LengthPercent)
+	resolution := row.Float(cols.Resolution)
+	umf := row.Float(cols.UMF)
+	meanCisDist := row.Float(cols.MeanCisDist)
+	stdCisDist := row.Float(cols.StdCisDist)
+	meanCisScore := row.Float(cols.MeanCisScore)
+	cisNum := row.Int(cols.Cis)
+	mix := row.Int(cols.Mix)
+
+	// 列名が変わると 0 のまま黙って返ってしまうので、見つからない・読めない列は警告する
+	if len(row.missing) > 0 || len(row.invalid) > 0 {
+		s.logger.Warn("convertSummaryCSVToResult: unexpected summary columns",
+			"job_id", jobID, "missing", row.missing, "invalid", row.invalid, "headers", headers)
+	}
+
+	s.logger.Debug("convertSummaryCSVToResult: parsed summary",
+		"job_id", jobID, "uniprot_id", uniprotID, "entries", entries, "chains", chains, "length", length)
 
-	// ヘッダーからインデックスを取得
-	headerMap := make(map[string]int)
-	for i, h := range headers {
-		headerMap[strings.TrimSpace(h)] = i
+	// 距離データとcisデータを読み込んでPairScoreを構築
+	files, err := s.storage.List(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job files: %w", err)
 	}
-
-	// データを取得
-	getString := func(key string) string {
-		if idx, ok := headerMap[key]; ok && idx < len(data) {
-			return strings.TrimSpace(data[idx])
-		}
-		return ""
+	hasFile := make(map[string]bool, len(files))
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		hasFile[f.Name] = true
+		names = append(names, f.Name)
 	}
+	distanceName := s.layout.Distance(uniprotID)
 
-	getInt := func(key string) int {
-		val := getString(key)
-		if val == "" {
-			return 0
-		}
-		if i, err := strconv.Atoi(val); err == nil {
-			return i
-		}
-		return 0
+	// cisファイルを検索（パターン: {uniprotID}_{seqRatio}_cis_nor+sub.csv）
+	// seqRatio の書き方（"0.2" / "0.25" など）に依らず、候補の中から数値の一致するものを選ぶ
+	cisName, hasCis := s.layout.FindCis(names, uniprotID, seqRatio)
+	if hasCis && cisName != s.layout.Cis(uniprotID, seqRatio) {
+		s.logger.Debug("convertSummaryCSVToResult: found cis file", "job_id", jobID, "file", cisName)
 	}
 
-	getFloat := func(key string) float64 {
-		val := getString(key)
-		if val == "" {
-			return 0.0
-		}
-		if f, err := strconv.ParseFloat(val, 64); err == nil {
-			return f
-		}
-		return 0.0
-	}
-
-	uniprotID := getString("uniprotid")
-	seqRatio := getFloat("seq_ratio")
-	entries := getInt("Entries")
-	chains := getInt("Chains")
-	length := getInt("Length")
-	lengthPercent := getFloat("Length(%)")
-	resolution := getFloat("Resolution")
-	umf := getFloat("UMF")
-	meanCisDist := getFloat("mean_cisDist")
-	stdCisDist := getFloat("std_cisDist")
-	meanCisScore := getFloat("mean_cisScore")
-	cisNum := getInt("cis")
-	mix := getInt("mix")
-
-	fmt.Printf("[DEBUG] convertSummaryCSVToResult - Parsed data: uniprotID=%s, entries=%d, chains=%d, length=%d\n", 
-		uniprotID, entries, chains, length)
-
-	// 距離データとcisデータを読み込んでPairScoreを構築
-	jobDir := filepath.Dir(summaryPath)
-	distancePath := filepath.Join(jobDir, fmt.Sprintf("distance_%s.csv", uniprotID))
-	
-	// cisファイルを検索（パターン: {uniprotID}_{seqRatio}_cis_nor+sub.csv）
-	// seqRatioは0.2の場合、ファイル名は "C6H0Y9_0.2_cis_nor+sub.csv" のようになる
-	cisPath := ""
-	cisPattern := fmt.Sprintf("%s_%.1f_cis_nor+sub.csv", uniprotID, seqRatio)
-	cisPath = filepath.Join(jobDir, cisPattern)
-	
-	// ファイルが存在しない場合は、ワイルドカードで検索
-	if _, err := os.Stat(cisPath); err != nil {
-		// ディレクトリ内のファイルを検索
-		if entries, err := os.ReadDir(jobDir); err == nil {
-			for _, entry := range entries {
-				if !entry.IsDir() && strings.Contains(entry.Name(), uniprotID) && 
-				   strings.Contains(entry.Name(), "_cis_") && strings.HasSuffix(entry.Name(), ".csv") {
-					cisPath = filepath.Join(jobDir, entry.Name())
-					fmt.Printf("[DEBUG] convertSummaryCSVToResult - Found cis file: %s\n", cisPath)
-					break
-				}
-			}
-		}
+	// 距離データのみのペアの残基名補完と残基ごとのスコアに使う配列（読めない場合はプレースホルダーのまま）
+	trimsequenceName := s.layout.TrimSequence(uniprotID)
+	trimSequence, trimSequenceErr := s.readJobTrimSequence(jobID, trimsequenceName)
+	if trimSequenceErr != nil {
+		s.logger.Debug("convertSummaryCSVToResult: trimsequence not available", "job_id", jobID, "error", trimSequenceErr)
 	}
-	
-	trimsequencePath := filepath.Join(jobDir, fmt.Sprintf("trimsequence_%s.csv", uniprotID))
 
 	// PairScoreを構築（cisデータから）
 	var pairScores []models.PairScore
 	var cisPairs []string
 
-	if _, err := os.Stat(cisPath); err == nil {
-		fmt.Printf("[DEBUG] convertSummaryCSVToResult - Reading cis data from: %s\n", cisPath)
-		cisFile, err := os.Open(cisPath)
+	if hasCis {
+		s.logger.Debug("convertSummaryCSVToResult: reading cis data", "job_id", jobID, "file", cisName)
+		cisFile, err := s.storage.Open(jobID, cisName)
 		if err == nil {
 			defer cisFile.Close()
 			cisReader := csv.NewReader(cisFile)
@@ -482,149 +705,61 @@ func (s *JobService) convertSummaryCSVToResult(jobID string, summaryPath string)
 	}
 
 	// 距離データからもPairScoreを構築（cisデータにないペアも含める）
-	if _, err := os.Stat(distancePath); err == nil {
-		fmt.Printf("[DEBUG] convertSummaryCSVToResult - Reading distance data from: %s\n", distancePath)
-		// 距離データはheaderなしなので、手動でパース
-		// フォーマット: residue_num1,residue_num2,distance1,distance2,...
-		distanceFile, err := os.Open(distancePath)
-		if err == nil {
-			defer distanceFile.Close()
-			distanceReader := csv.NewReader(distanceFile)
-			distanceRecords, err := distanceReader.ReadAll()
-			if err == nil {
-				// 既存のpairScoresのマップを作成（重複チェック用）
-				pairMap := make(map[string]bool)
-				for _, ps := range pairScores {
-					key := fmt.Sprintf("%d,%d", ps.I, ps.J)
-					pairMap[key] = true
-				}
-
-				// 距離データから平均と標準偏差を計算
-				for _, row := range distanceRecords {
-					if len(row) < 2 {
-						continue
-					}
-
-					iIdx, err1 := strconv.Atoi(row[0])
-					jIdx, err2 := strconv.Atoi(row[1])
-					if err1 != nil || err2 != nil {
-						continue
-					}
-
-					key := fmt.Sprintf("%d,%d", iIdx, jIdx)
-					if pairMap[key] {
-						continue // 既にcisデータから追加済み
-					}
-
-					// 距離値を取得（3列目以降）
-					var distances []float64
-					for i := 2; i < len(row); i++ {
-						if f, err := strconv.ParseFloat(row[i], 64); err == nil {
-							distances = append(distances, f)
-						}
-					}
-
-					if len(distances) == 0 {
-						continue
-					}
-
-					// 平均と標準偏差を計算
-					var sum float64
-					for _, d := range distances {
-						sum += d
-					}
-					mean := sum / float64(len(distances))
-
-					var variance float64
-					for _, d := range distances {
-						variance += (d - mean) * (d - mean)
-					}
-					std := math.Sqrt(variance / float64(len(distances)))
-
-					// scoreを計算（mean / std、stdが0の場合は0.0001）
-					score := mean / std
-					if std == 0 {
-						score = mean / 0.0001
-					}
-
-					// 残基ペア名を取得（trimsequenceから推測するか、デフォルト値を使用）
-					residuePair := fmt.Sprintf("RES-%d, RES-%d", iIdx, jIdx)
+	if hasFile[distanceName] {
+		s.logger.Debug("convertSummaryCSVToResult: reading distance data", "job_id", jobID, "file", distanceName)
+		// 既存のpairScoresのマップを作成（重複チェック用）
+		pairMap := make(map[string]bool)
+		for _, ps := range pairScores {
+			pairMap[fmt.Sprintf("%d,%d", ps.I, ps.J)] = true
+		}
 
-					pairScores = append(pairScores, models.PairScore{
-						I:            iIdx,
-						J:            jIdx,
-						ResiduePair:  residuePair,
-						DistanceMean: mean,
-						DistanceStd:  std,
-						Score:        score,
-					})
-				}
-			}
+		distancePairs, err := s.readJobDistancePairs(jobID, distanceName, pairMap, trimSequence)
+		if err != nil {
+			s.logger.Warn("convertSummaryCSVToResult: failed to read distance data", "job_id", jobID, "file", distanceName, "error", err)
 		}
+		pairScores = append(pairScores, distancePairs...)
 	}
 
 	// PerResidueScoreを構築（trimsequenceから）
 	var perResidueScores []models.PerResidueScore
-	if _, err := os.Stat(trimsequencePath); err == nil {
-		fmt.Printf("[DEBUG] convertSummaryCSVToResult - Reading trimsequence from: %s\n", trimsequencePath)
-		trimFile, err := os.Open(trimsequencePath)
-		if err == nil {
-			defer trimFile.Close()
-			trimReader := csv.NewReader(trimFile)
-			trimRecords, err := trimReader.ReadAll()
-			if err == nil && len(trimRecords) > 0 {
-				// 最初の列がUniProt配列
-				for idx, row := range trimRecords {
-					if len(row) == 0 {
-						continue
-					}
-					residueName := strings.TrimSpace(row[0])
-					// 3文字コードから1文字コードに変換（簡易版）
-					residueName1 := residueName
-					if len(residueName) == 3 {
-						// 簡易変換（完全な変換テーブルは実装しない）
-						residueName1 = residueName
-					}
-
-					// この残基に関連するペアスコアの平均を計算
-					var scores []float64
-					for _, ps := range pairScores {
-						if ps.I == idx+1 || ps.J == idx+1 {
-							if !math.IsNaN(ps.Score) && !math.IsInf(ps.Score, 0) {
-								scores = append(scores, ps.Score)
-							}
-						}
-					}
-
-					avgScore := 0.0
-					if len(scores) > 0 {
-						var sum float64
-						for _, s := range scores {
-							sum += s
-						}
-						avgScore = sum / float64(len(scores))
+	if trimSequenceErr == nil {
+		// 最初の列がUniProt配列（3文字コード）
+		for idx, residueName := range trimSequence {
+			residueName1 := toOneLetter(residueName)
+
+			// この残基に関連するペアスコアの平均を計算
+			var scores []float64
+			for _, ps := range pairScores {
+				if ps.I == idx+1 || ps.J == idx+1 {
+					if !math.IsNaN(ps.Score) && !math.IsInf(ps.Score, 0) {
+						scores = append(scores, ps.Score)
 					}
+				}
+			}
 
-					perResidueScores = append(perResidueScores, models.PerResidueScore{
-						Index:         idx,
-						ResidueNumber: idx + 1,
-						ResidueName:   residueName1,
-						Score:         avgScore,
-					})
+			avgScore := 0.0
+			if len(scores) > 0 {
+				var sum float64
+				for _, s := range scores {
+					sum += s
 				}
+				avgScore = sum / float64(len(scores))
 			}
+
+			perResidueScores = append(perResidueScores, models.PerResidueScore{
+				Index:         idx,
+				ResidueNumber: idx + 1,
+				ResidueName:   residueName1,
+				Score:         avgScore,
+			})
 		}
 	}
 
 	// PDB IDリストを取得（distanceデータの列名から、またはatom_coordディレクトリから）
 	var pdbIDs []string
-	atomCoordDir := filepath.Join(jobDir, "atom_coord")
-	if entries, err := os.ReadDir(atomCoordDir); err == nil {
-		for _, entry := range entries {
-			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".csv") {
-				pdbID := strings.TrimSuffix(entry.Name(), ".csv")
-				pdbIDs = append(pdbIDs, strings.ToUpper(pdbID))
-			}
+	for _, f := range files {
+		if pdbID, ok := s.layout.AtomCoordPDBID(f.Name); ok {
+			pdbIDs = append(pdbIDs, pdbID)
 		}
 	}
 	if len(pdbIDs) == 0 {
@@ -637,51 +772,37 @@ func (s *JobService) convertSummaryCSVToResult(jobID string, summaryPath string)
 	if heatmapSize == 0 {
 		heatmapSize = 100 // デフォルト値
 	}
-	// NaNを表現するために、nil可能なfloat64ポインタスライスを使用
-	heatmapValues := make([][]*float64, heatmapSize)
-	for i := range heatmapValues {
-		heatmapValues[i] = make([]*float64, heatmapSize)
-		// 初期値はnil（JSONではnullとして表現される）
-	}
-
-	// pairScoresからヒートマップを構築
-	for _, ps := range pairScores {
-		i := ps.I - 1 // 0-based
-		j := ps.J - 1 // 0-based
-		if i >= 0 && i < heatmapSize && j >= 0 && j < heatmapSize {
-			if !math.IsNaN(ps.Score) && !math.IsInf(ps.Score, 0) {
-				scoreVal := ps.Score
-				heatmapValues[i][j] = &scoreVal
-			}
-			// NaNまたはInfの場合はnilのまま（JSONではnull）
+	// 残基数の上限を超える場合は N×N の行列を確保せず、ヒートマップ無しで返す
+	var heatmap *models.Heatmap
+	if s.heatmapTooLarge(heatmapSize) {
+		s.logger.Warn("convertSummaryCSVToResult: skipping heatmap, too many residues",
+			"job_id", jobID, "residues", heatmapSize, "max_residues", s.maxResidues)
+	} else {
+		// NaNを表現するために、nil可能なfloat64ポインタスライスを使用
+		heatmapValues := make([][]*float64, heatmapSize)
+		for i := range heatmapValues {
+			heatmapValues[i] = make([]*float64, heatmapSize)
+			// 初期値はnil（JSONではnullとして表現される）
 		}
-	}
 
-	// 統計を計算
-	pairScoreMean := 0.0
-	pairScoreStd := 0.0
-	if len(pairScores) > 0 {
-		var scores []float64
+		// pairScoresからヒートマップを構築
 		for _, ps := range pairScores {
-			if !math.IsNaN(ps.Score) && !math.IsInf(ps.Score, 0) {
-				scores = append(scores, ps.Score)
-			}
-		}
-		if len(scores) > 0 {
-			var sum float64
-			for _, s := range scores {
-				sum += s
-			}
-			pairScoreMean = sum / float64(len(scores))
-
-			var variance float64
-			for _, s := range scores {
-				variance += (s - pairScoreMean) * (s - pairScoreMean)
+			i := ps.I - 1 // 0-based
+			j := ps.J - 1 // 0-based
+			if i >= 0 && i < heatmapSize && j >= 0 && j < heatmapSize {
+				if !math.IsNaN(ps.Score) && !math.IsInf(ps.Score, 0) {
+					scoreVal := ps.Score
+					heatmapValues[i][j] = &scoreVal
+				}
+				// NaNまたはInfの場合はnilのまま（JSONではnull）
 			}
-			pairScoreStd = math.Sqrt(variance / float64(len(scores)))
 		}
+		heatmap = &models.Heatmap{Size: heatmapSize, Values: heatmapValues}
 	}
 
+	// 統計を計算
+	pairScoreMean, pairScoreStd := pairScoreStats(pairScores)
+
 	// フル配列長を計算（length / lengthPercent * 100）
 	fullSequenceLength := 0
 	if lengthPercent > 0 {
@@ -694,6 +815,57 @@ func (s *JobService) convertSummaryCSVToResult(jobID string, summaryPath string)
 		top5ResolutionMean = &resolution
 	}
 
+	// 投入時のパラメータ（params.json）から cis 閾値と手法を復元
+	// 読めない場合（古いジョブなど）は -defaults-file の既定値
+	cisThreshold := s.defaults.CisThreshold
+	method := s.defaults.Method
+	referenceOffset := 0
+	var requestedPDBIDs []string
+	if params, err := s.GetJobParams(jobID); err == nil {
+		if params.CisThreshold != nil {
+			cisThreshold = *params.CisThreshold
+		}
+		if params.Method != nil && *params.Method != "" {
+			method = *params.Method
+		}
+		if params.ReferenceOffset != nil {
+			referenceOffset = *params.ReferenceOffset
+		}
+		requestedPDBIDs = params.PDBIDs
+	} else {
+		s.logger.Debug("convertSummaryCSVToResult: params not available, using defaults", "job_id", jobID, "error", err)
+	}
+
+	// reference_offset で残基番号を UniProt 上の位置に合わせる（Index と配列長・カバー率はそのまま）
+	// ずらすと全長を超える場合は指定が合っていないので番号は変えない
+	if referenceOffset > 0 {
+		if fullSequenceLength > 0 && referenceOffset+len(perResidueScores) > fullSequenceLength {
+			s.logger.Warn("convertSummaryCSVToResult: reference_offset exceeds full sequence length, ignoring",
+				"job_id", jobID, "reference_offset", referenceOffset, "residues", len(perResidueScores), "full_sequence_length", fullSequenceLength)
+		} else {
+			for i := range perResidueScores {
+				perResidueScores[i].ResidueNumber += referenceOffset
+			}
+		}
+	}
+
+	// 除外した PDB エントリ（Python が structures CSV を書いたジョブのみ分かる）
+	excludedPDBs := []string{}
+	excludedStructures := []models.ExcludedStructure{}
+	if structures, err := s.readJobStructures(jobID, uniprotID); err == nil {
+		excludedStructures = excludedFromStructures(structures)
+		for _, st := range excludedStructures {
+			excludedPDBs = append(excludedPDBs, st.PDBID)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		s.logger.Warn("convertSummaryCSVToResult: failed to read structures", "job_id", jobID, "error", err)
+	}
+
+	// pdb_ids を指定したジョブは指定順に並べる（atom_coord が残っていなければ、指定のうち除外されなかったもの）
+	if len(requestedPDBIDs) > 0 {
+		pdbIDs = orderedPDBIDs(requestedPDBIDs, pdbIDs, excludedPDBs)
+	}
+
 	// CisInfoを構築
 	cisInfo := models.CisInfo{
 		CisDistMean:  meanCisDist,
@@ -702,18 +874,20 @@ func (s *JobService) convertSummaryCSVToResult(jobID string, summaryPath string)
 		CisNum:       cisNum,
 		Mix:          mix,
 		CisPairs:     cisPairs,
-		Threshold:    3.3, // デフォルト値（実際の値は取得できない場合がある）
+		Threshold:    cisThreshold,
 	}
 
 	// NotebookDSAResultを構築
 	result := &models.NotebookDSAResult{
+		SchemaVersion:        models.ResultSchemaVersion,
 		UniProtID:            uniprotID,
 		NumStructures:        entries,
 		NumResidues:          length,
 		PDBIDs:               pdbIDs,
-		ExcludedPDBs:         []string{},
+		ExcludedPDBs:         excludedPDBs,
+		ExcludedStructures:   excludedStructures,
 		SeqRatio:             seqRatio,
-		Method:               "X-ray", // デフォルト値
+		Method:               method,
 		FullSequenceLength:   fullSequenceLength,
 		ResidueCoveragePercent: lengthPercent,
 		NumChains:            chains,
@@ -723,68 +897,103 @@ func (s *JobService) convertSummaryCSVToResult(jobID string, summaryPath string)
 		PairScoreStd:         pairScoreStd,
 		PairScores:           pairScores,
 		PerResidueScores:     perResidueScores,
-		Heatmap: &models.Heatmap{
-			Size:   heatmapSize,
-			Values: heatmapValues,
-		},
+		Heatmap:              heatmap,
 		CisInfo: cisInfo,
 	}
 
-	fmt.Printf("[DEBUG] convertSummaryCSVToResult - Successfully converted summary.csv to NotebookDSAResult\n")
-	fmt.Printf("[DEBUG] convertSummaryCSVToResult - Result: uniprotID=%s, numStructures=%d, numResidues=%d, pairScores=%d\n",
-		result.UniProtID, result.NumStructures, result.NumResidues, len(result.PairScores))
+	s.logger.Debug("convertSummaryCSVToResult: converted summary.csv",
+		"job_id", jobID, "uniprot_id", result.UniProtID, "num_structures", result.NumStructures,
+		"num_residues", result.NumResidues, "pair_scores", len(result.PairScores))
 
 	return result, nil
 }
 
 // executeDSAAnalysis はPython CLIを実行（非同期）
-func (s *JobService) executeDSAAnalysis(jobID string, params models.AnalysisParams) {
-	// ステータス更新: processing
-	s.updateJobStatus(jobID, "processing", 0, "Starting analysis...")
-
-	// 出力パス（結果 JSON と heatmap.png は同じ job ディレクトリに置く前提）
-	jobDir := filepath.Join(s.storageDir, jobID)
-	if err := os.MkdirAll(jobDir, 0o755); err != nil {
-		s.updateJobStatus(jobID, "failed", 0, fmt.Sprintf("failed to create job dir: %v", err))
+func (s *JobService) executeDSAAnalysis(jobID string, params models.AnalysisParams, logger *slog.Logger) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.owned, jobID)
+		s.mu.Unlock()
+	}()
+
+	// 開始前にキャンセルされたジョブ、シャットダウン中のジョブは実行しない
+	if status, err := s.GetJobStatus(jobID); err == nil && status.Status == "cancelled" {
+		return
+	}
+	if s.isClosing() {
+		return
+	}
+
+	// 実行枠を確保（空きが無ければ待ち行列で待機）
+	if !s.acquireWorker(jobID, params.Priority) {
+		return
+	}
+	defer s.releaseWorker()
+	if s.isClosing() {
+		return
+	}
+
+	startedAt := time.Now()
+	s.mu.Lock()
+	s.running[jobID] = startedAt
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, jobID)
+		s.mu.Unlock()
+	}()
+
+	// ステータス更新: processing（実行枠を待つ間にキャンセルされていれば実行しない）
+	if err := s.updateJobStatus(jobID, "processing", 0, "Starting analysis..."); err != nil {
+		logger.Info("executeDSAAnalysis: not started", "error", err)
+		return
+	}
+
+	// 出力パス（成果物はすべて job ディレクトリ直下に置き、名前は s.layout に従う）
+	jobDir, err := s.jobDir(jobID)
+	if err == nil {
+		err = os.MkdirAll(jobDir, 0o755)
+	}
+	if err != nil {
+		s.failJob(jobID, fmt.Sprintf("failed to create job dir: %v", err), FailureInternalError, "")
 		return
 	}
+	defer s.removeWorkDir(jobID)
+	// 構造ファイルはどう終わっても削除する（-keep-pdb なら残す）
+	defer s.removePDBFiles(jobID)
 
 	resultPath := filepath.Join(jobDir, "result.json")
 
 	// 絶対パス化（Python 側に cwd 依存しないパスを渡す）
 	absResultPath, err := filepath.Abs(resultPath)
 	if err != nil {
-		s.updateJobStatus(jobID, "failed", 0, fmt.Sprintf("failed to resolve result path: %v", err))
+		s.failJob(jobID, fmt.Sprintf("failed to resolve result path: %v", err), FailureInternalError, "")
 		return
 	}
 
-	// ================================
-	//  🔴 ここが「Python 実行環境あわせ」の肝
-	// ================================
-	// 1) python バイナリは起動時フラグ -python で /opt/anaconda3/bin/python を渡す
-	// 2) PYTHON_ENGINE_DIR 環境変数に python-engine ディレクトリを設定しておく
-	//    例: export PYTHON_ENGINE_DIR="/Users/xxx/Desktop/protein-flexibility-platform/python-engine"
-	pythonWorkDir := os.Getenv("PYTHON_ENGINE_DIR")
-	if pythonWorkDir == "" {
-		// 一旦カレントのままでも動くようにフォールバック
-		pythonWorkDir, _ = os.Getwd()
-	}
+	// python バイナリは起動時フラグ -python、作業ディレクトリは -python-engine-dir
+	// （未指定時は PYTHON_ENGINE_DIR 環境変数）で指定する
 
 	// Notebook DSA CLIコマンド構築
 	args := []string{
 		"-m", "flex_analyzer.cli", "notebook",
 		"--uniprot-ids", params.UniProtIDs,
 		"--method", *params.Method,
-		"--seq-ratio", fmt.Sprintf("%.2f", *params.SeqRatio),
+		"--seq-ratio", formatSeqRatio(*params.SeqRatio),
 		"--cis-threshold", fmt.Sprintf("%.2f", *params.CisThreshold),
 		"--output-dir", filepath.Dir(absResultPath),
-		"--pdb-dir", filepath.Join(filepath.Dir(absResultPath), "pdb_files"),
+		// 名前は pdb_files だが、エンジンは常に mmCIF（{pdbid}.cif）を取得して置く
+		"--pdb-dir", filepath.Join(filepath.Dir(absResultPath), s.layout.PDBDir),
 	}
 	
 	// negative_pdbidが指定されている場合のみ追加
 	if params.NegativePDBID != nil && *params.NegativePDBID != "" {
 		args = append(args, "--negative-pdbid", *params.NegativePDBID)
 	}
+	// 解析する PDB エントリを明示した場合のみ追加（UniProt からの自動選択をしない）
+	if len(params.PDBIDs) > 0 {
+		args = append(args, "--pdb-ids", strings.Join(params.PDBIDs, " "))
+	}
 	
 	// オプションフラグ
 	if *params.Export {
@@ -807,118 +1016,270 @@ func (s *JobService) executeDSAAnalysis(jobID string, params models.AnalysisPara
 	} else {
 		args = append(args, "--no-overwrite")
 	}
+	args = append(args, s.inputLimitArgs()...)
 	args = append(args, "--verbose")
 
 	// デバッグ: 実行するコマンドをログ出力
-	fmt.Printf("[DEBUG] executeDSAAnalysis - Command: %s %v\n", s.pythonBin, args)
-	fmt.Printf("[DEBUG] executeDSAAnalysis - Working directory: %s\n", "/Users/kondoubyakko/Desktop/protein-flexibility-platform/python-engine")
+	logger.Debug("executeDSAAnalysis: command", "python", s.pythonBin, "args", args, "dir", s.pythonEngineDir)
 
-	// タイムアウト設定（30分 = 1800秒）
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	// タイムアウト設定（-job-timeout、デフォルト30分）
+	ctx, cancel := context.WithTimeout(context.Background(), s.JobTimeout())
 	defer cancel()
-	
-	cmd := exec.CommandContext(ctx, s.pythonBin, args...)
-	cmd.Dir = "/Users/kondoubyakko/Desktop/protein-flexibility-platform/python-engine"
-	env := os.Environ()
-	env = append(env, "PYTHONPATH=./src")
-	cmd.Env = env
+	s.registerCancel(jobID, cancel)
+	defer s.unregisterCancel(jobID)
+	// 登録前にキャンセルされていれば Python を起動しない（CancelJob は cancelled を書いてからキャンセル関数を探す）
+	if status, err := s.GetJobStatus(jobID); err == nil && IsTerminalStatus(status.Status) {
+		logger.Info("executeDSAAnalysis: job cancelled before start")
+		return
+	}
+
+	argv, meta := s.wrapCommand(append([]string{s.pythonBin}, args...))
+	meta.FileLayoutVersion = s.layout.Version
+	if err := s.saveJobMetadata(jobID, meta); err != nil {
+		logger.Warn("executeDSAAnalysis: failed to save metadata", "error", err)
+	}
+	env := s.pythonEnv()
+	if err := s.saveJobCommand(jobID, newJobCommand(argv, s.pythonEngineDir, env)); err != nil {
+		logger.Warn("executeDSAAnalysis: failed to save command", "error", err)
+	}
 
 	// 標準出力/エラー出力をキャプチャ
-	fmt.Printf("[DEBUG] executeDSAAnalysis - Starting Python command execution...\n")
-	output, err := cmd.CombinedOutput()
+	logger.Info("executeDSAAnalysis: starting Python command", "uniprot_ids", params.UniProtIDs)
+	// 出力から段階を推定して進捗を更新（進捗は戻さない）
+	lastProgress := 0
+	// 成否にかかわらず、出力は届いた順に output.log として残す
+	var outputLog jobLog
+	onLine := func(line string) {
+		outputLog.writeLine(line)
+		progress, message, ok := parseProgress(line)
+		if !ok || progress <= lastProgress || ctx.Err() != nil {
+			return
+		}
+		lastProgress = progress
+		s.updateJobStatus(jobID, "processing", progress, message)
+	}
+	// 一時的なダウンロード失敗なら待ち時間を倍々にしながら再実行する
+	retries, delay := s.downloadRetryPolicy()
+	var stdout, stderr []byte
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			outputLog.writeLine(fmt.Sprintf("--- retry %d/%d ---", attempt-1, retries))
+		}
+		stdout, stderr, err = s.runner.Run(ctx, argv, s.pythonEngineDir, env, onLine)
+		if ctx.Err() != context.Canceled {
+			s.metrics.observeExit(err)
+		}
+		if err == nil || ctx.Err() != nil || attempt > retries || !isTransientDownloadFailure(string(stdout), string(stderr)) {
+			break
+		}
+
+		logger.Warn("executeDSAAnalysis: transient download failure, retrying", "attempt", attempt, "retries", retries, "delay", delay, "exception", pythonErrorLine(string(stderr)))
+		s.updateJobStatus(jobID, "processing", lastProgress, fmt.Sprintf("retrying (attempt %d/%d)", attempt, retries))
+		if !sleepContext(ctx, delay) {
+			break
+		}
+		delay *= 2
+	}
 
 	// デバッグ: 出力をログ出力（最初の1000文字のみ）
-	outputStr := string(output)
-	if len(outputStr) > 1000 {
-		fmt.Printf("[DEBUG] executeDSAAnalysis - Output (first 1000 chars): %s\n", outputStr[:1000])
-		fmt.Printf("[DEBUG] executeDSAAnalysis - Output length: %d\n", len(outputStr))
-	} else {
-		fmt.Printf("[DEBUG] executeDSAAnalysis - Full output: %s\n", outputStr)
+	stdoutStr, stderrStr := string(stdout), string(stderr)
+	outputHead := stdoutStr
+	if len(outputHead) > 1000 {
+		outputHead = outputHead[:1000]
+	}
+	logger.Debug("executeDSAAnalysis: output", "stdout_length", len(stdoutStr), "stderr_length", len(stderrStr), "head", outputHead)
+	if err := writeBytes(s.storage, jobID, jobLogFile, outputLog.bytes()); err != nil {
+		logger.Warn("executeDSAAnalysis: failed to save output.log", "error", err)
 	}
 
 	if err != nil {
-		var errorMsg string
+		// キャンセルされた場合はステータスを上書きしない
+		if ctx.Err() == context.Canceled {
+			logger.Info("executeDSAAnalysis: job cancelled")
+			return
+		}
+
+		// 途中までの出力も調査用に保存先へ残す
+		if err := s.persistWorkDir(jobID); err != nil {
+			logger.Warn("executeDSAAnalysis: failed to store partial output", "error", err)
+		}
+
+		var errorMsg, failure, reason string
 		// タイムアウトエラーのチェック
 		if ctx.Err() == context.DeadlineExceeded {
-			errorMsg = "Python CLI execution timed out after 30 minutes"
-			fmt.Printf("[DEBUG] executeDSAAnalysis - Timeout error: %v\n", err)
-			s.updateJobStatus(jobID, "failed", 0, errorMsg)
+			errorMsg = fmt.Sprintf("Python CLI execution timed out after %s", s.JobTimeout())
+			failure = FailureTimeout
+			logger.Error("executeDSAAnalysis: timed out", "error", err)
 		} else {
-			// その他のエラー
-			outputPreview := outputStr
-			if len(outputStr) > 2000 {
-				outputPreview = outputStr[len(outputStr)-2000:]
+			// その他のエラー: トレースバック末尾の例外行を要約として使う（全文は error.json に残す）
+			exception := pythonErrorLine(stderrStr)
+			errorMsg = fmt.Sprintf("Python CLI failed (%v)", err)
+			if exception != "" {
+				errorMsg += ": " + exception
+			}
+			reason = failureReason(stdoutStr, stderrStr)
+			failure = failureCategory(reason, stdoutStr, stderrStr)
+			logger.Error("executeDSAAnalysis: Python CLI failed", "error", err, "exception", exception, "failure_reason", failure, "reason", reason)
+			switch reason {
+			case FailureReasonNoSuitableStructures:
+				// 入力の問題なので、例外行ではなく条件の見直しを促すメッセージにする
+				errorMsg = noSuitableStructuresMessage
+			case FailureReasonInputTooLarge:
+				// 例外メッセージに実際の件数と上限が入っている
+				errorMsg = inputTooLargeMessage
+				if detail := inputTooLargeDetail(stderrStr); detail != "" {
+					errorMsg += ": " + detail
+				}
 			}
-			errorMsg = fmt.Sprintf("Python CLI failed: %v\nOutput (last 2000 chars): %s", err, outputPreview)
-			fmt.Printf("[DEBUG] executeDSAAnalysis - Execution error: %v\n", err)
-			s.updateJobStatus(jobID, "failed", 0, errorMsg)
 		}
 
 		// エラーファイル保存
 		errorData := models.ErrorResponse{
 			Error: errorMsg,
 			PartialResult: map[string]interface{}{
-				"output": outputStr,
+				"stdout": stdoutStr,
+				"stderr": stderrStr,
 			},
 		}
 		errorJSON, _ := json.MarshalIndent(errorData, "", "  ")
-		_ = os.WriteFile(filepath.Join(jobDir, "error.json"), errorJSON, 0o644)
+		if err := writeBytes(s.storage, jobID, "error.json", errorJSON); err != nil {
+			logger.Warn("executeDSAAnalysis: failed to save error.json", "error", err)
+		}
 
+		// failed を見たクライアントが error.json を読めるよう、ステータスは最後に書く
+		s.failJob(jobID, errorMsg, failure, reason)
 		return
 	}
 
-	fmt.Printf("[DEBUG] executeDSAAnalysis - Python command completed successfully\n")
+	logger.Info("executeDSAAnalysis: Python command completed")
+
+	// ローカル以外の保存先なら出力をアップロードしてから完了にする
+	if err := s.persistWorkDir(jobID); err != nil {
+		logger.Error("executeDSAAnalysis: failed to store output", "error", err)
+		s.failJob(jobID, fmt.Sprintf("failed to store results: %v", err), FailureInternalError, "")
+		return
+	}
 
-	// Notebook DSAはsummary.csvを出力するため、result.jsonが存在しない可能性がある
-	// summary.csvから結果を読み込んでresult.jsonに変換するか、summary.csvの存在を確認
-	summaryPath := filepath.Join(filepath.Dir(absResultPath), "summary.csv")
-	if _, err := os.Stat(summaryPath); err == nil {
-		fmt.Printf("[DEBUG] executeDSAAnalysis - Found summary.csv at: %s\n", summaryPath)
-		// summary.csvが存在する場合は、それをresult.jsonとして保存するか、
-		// またはGetResult関数でsummary.csvを読み込むように変更する必要がある
-		// ここでは、summary.csvの存在を確認してログ出力するだけ
+	// 結果を現在の形式で result.json に残す（以降の GetResult は CSV を読み直さない）
+	if err := s.saveResultJSON(jobID); err != nil {
+		logger.Warn("executeDSAAnalysis: failed to save result.json", "error", err)
 	}
 
 	// 完了
+	s.metrics.observeDuration(time.Since(startedAt))
 	s.updateJobStatus(jobID, "completed", 100, "Analysis completed")
 }
 
-// updateJobStatus はジョブステータスを更新
-func (s *JobService) updateJobStatus(jobID, status string, progress int, message string) {
+// updateJobStatus はジョブステータスを更新（終了済みのジョブなら ErrJobFinished）
+func (s *JobService) updateJobStatus(jobID, status string, progress int, message string) error {
+	return s.writeJobStatus(models.JobStatus{JobID: jobID, Status: status, Progress: progress, Message: message})
+}
+
+// failJob はジョブを failed にする（failure は FailureTimeout などの大分類、reason は判別できた場合の詳細な理由）
+func (s *JobService) failJob(jobID, message, failure, reason string) {
+	s.writeJobStatus(models.JobStatus{JobID: jobID, Status: "failed", Message: message, FailureReason: failure, Reason: reason})
+}
+
+// writeJobStatus はジョブステータスを保存して購読者・callback_url に知らせる（UpdatedAt と CreatedAt はここで埋める）
+// 終了状態（completed/failed/cancelled/interrupted）のジョブは書き換えず ErrJobFinished を返す
+// キャンセル後に届いた進捗行や待ち行列の更新が cancelled を上書きしないよう、判定はロックの中で行う
+func (s *JobService) writeJobStatus(jobStatus models.JobStatus) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	jobStatus := models.JobStatus{
-		JobID:     jobID,
-		Status:    status,
-		Progress:  progress,
-		Message:   message,
-		UpdatedAt: time.Now(),
-	}
+	jobID, status := jobStatus.JobID, jobStatus.Status
+	jobStatus.UpdatedAt = time.Now()
 
 	// 既存のCreatedAtを保持
-	existingStatus, err := s.GetJobStatus(jobID)
+	existingStatus, err := s.readStatus(jobID)
 	if err == nil {
+		if IsTerminalStatus(existingStatus.Status) {
+			s.logger.Debug("writeJobStatus: job already finished", "job_id", jobID, "status", existingStatus.Status, "new_status", status)
+			return fmt.Errorf("%w: %s is %s", ErrJobFinished, jobID, existingStatus.Status)
+		}
 		jobStatus.CreatedAt = existingStatus.CreatedAt
 	} else {
 		jobStatus.CreatedAt = time.Now()
 	}
 
-	_ = s.saveJobStatus(jobID, jobStatus)
+	if err := s.saveJobStatus(jobID, jobStatus); err != nil {
+		return err
+	}
+	setStatusTimings(&jobStatus, jobStatus.UpdatedAt)
+	s.setQueuePosition(&jobStatus)
+	s.publish(jobStatus)
+
+	// 終了状態になったら callback_url に通知（ロックを持ったまま待たない）
+	if IsTerminalStatus(status) {
+		s.metrics.jobFinished(status)
+		go s.notifyWebhook(jobStatus)
+	}
+	return nil
 }
 
 // saveJobStatus はジョブステータスをファイルに保存
 func (s *JobService) saveJobStatus(jobID string, status models.JobStatus) error {
-	statusPath := filepath.Join(s.storageDir, jobID, "status.json")
+	// 経過時間と待ち行列の順番は読むたびに計算し直すので保存しない
+	status.DurationSeconds = nil
+	status.CompletedAt = nil
+	status.QueuePosition = nil
 
 	data, err := json.MarshalIndent(status, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal status: %w", err)
 	}
 
-	if err := os.WriteFile(statusPath, data, 0o644); err != nil {
+	// 読み手が書きかけのファイルを見ないよう、Storage 側で置き換える（ローカルなら一時ファイル + rename）
+	if err := writeBytes(s.storage, jobID, "status.json", data); err != nil {
 		return fmt.Errorf("failed to write status: %w", err)
 	}
 
 	return nil
 }
+
+// saveJobParams はジョブのパラメータをファイルに保存
+func (s *JobService) saveJobParams(jobID string, params models.AnalysisParams) error {
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal params: %w", err)
+	}
+
+	if err := writeBytes(s.storage, jobID, "params.json", data); err != nil {
+		return fmt.Errorf("failed to write params: %w", err)
+	}
+
+	return nil
+}
+
+// GetJobParams は保存済みのジョブパラメータを取得
+func (s *JobService) GetJobParams(jobID string) (*models.AnalysisParams, error) {
+	if !IsValidJobID(jobID) {
+		return nil, fmt.Errorf("invalid job id: %s", jobID)
+	}
+
+	data, err := s.storage.ReadFile(jobID, "params.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read params: %w", err)
+	}
+
+	var params models.AnalysisParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse params: %w", err)
+	}
+
+	return &params, nil
+}
+
+// saveJobMetadata はジョブのメタデータをファイルに保存
+func (s *JobService) saveJobMetadata(jobID string, meta models.JobMetadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	if err := writeBytes(s.storage, jobID, "metadata.json", data); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	return nil
+}
\ No newline at end of file
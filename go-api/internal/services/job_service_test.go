@@ -0,0 +1,397 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestConvertSummaryCSVBackfillsDistanceOnlyPairNames(t *testing.T) {
+	jobDir := filepath.Join(t.TempDir(), "job")
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	summaryPath := filepath.Join(jobDir, "summary.csv")
+
+	writeFile(t, summaryPath, "uniprotid,seq_ratio,Entries,Length\nP12345,0.2,3,3\n")
+	writeFile(t, filepath.Join(jobDir, "trimsequence_P12345.csv"), "P12345,1A00 A\nALA,ALA\nGLY,GLY\n")
+	writeFile(t, filepath.Join(jobDir, "distance_P12345.csv"), "1,2,3.8,3.9\n1,3,6.1,6.3\n")
+
+	s := NewJobService(filepath.Dir(jobDir), "", "", nil, nil)
+	result, err := s.convertSummaryCSVToResult("job")
+	if err != nil {
+		t.Fatalf("convertSummaryCSVToResult: %v", err)
+	}
+
+	want := map[[2]int]string{
+		{1, 2}: "A-1, G-2",
+		{1, 3}: "A-1, RES-3", // 配列外の位置はプレースホルダーのまま
+	}
+	if len(result.PairScores) != len(want) {
+		t.Fatalf("got %d pairs, want %d", len(result.PairScores), len(want))
+	}
+	for _, ps := range result.PairScores {
+		if got := ps.ResiduePair; got != want[[2]int{ps.I, ps.J}] {
+			t.Errorf("pair (%d,%d): got %q, want %q", ps.I, ps.J, got, want[[2]int{ps.I, ps.J}])
+		}
+	}
+}
+
+// waitForStatus はジョブが終了状態になるまで待つ
+func waitForStatus(t *testing.T, s *JobService, jobID string) *models.JobStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := s.GetJobStatus(jobID)
+		if err == nil && (status.Status == "completed" || status.Status == "failed" || status.Status == "cancelled") {
+			// 終了状態を書いた後もジョブの goroutine は後片付け（作業ディレクトリの削除など）を続けるので、
+			// 抜けるまで待ってから返す（TempDir の削除や後片付けの確認と競合しないように）
+			for s.runningCount() > 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish in time", jobID)
+	return nil
+}
+
+// runningCount は実行中の解析数
+func (s *JobService) runningCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.running)
+}
+
+func TestJobLifecycleWithFakeRunner(t *testing.T) {
+	runner := &FakeRunner{Files: summaryFixture()}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+
+	status := waitForStatus(t, s, job.JobID)
+	if status.Status != "completed" || status.Progress != 100 {
+		t.Fatalf("got status %q (%d%%), want completed (100%%)", status.Status, status.Progress)
+	}
+
+	calls := runner.Calls()
+	if len(calls) != 1 || argValue(calls[0], "--uniprot-ids") != "P12345" {
+		t.Fatalf("unexpected runner calls: %v", calls)
+	}
+
+	result, err := s.GetResult(job.JobID)
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+	if result.UniProtID != "P12345" || result.NumStructures != 3 || len(result.PairScores) != 3 {
+		t.Errorf("unexpected result: uniprot=%s structures=%d pairs=%d", result.UniProtID, result.NumStructures, len(result.PairScores))
+	}
+	summary, err := s.GetResultSummary(job.JobID)
+	if err != nil {
+		t.Fatalf("GetResultSummary: %v", err)
+	}
+	if summary.UniProtID != result.UniProtID || summary.NumStructures != result.NumStructures || summary.UMF != result.UMF {
+		t.Errorf("summary does not match result: %+v", summary)
+	}
+}
+
+func TestResultReflectsSubmittedParams(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+
+	method := "NMR"
+	cisThreshold := 3.0
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345", Method: &method, CisThreshold: &cisThreshold})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	result, err := s.GetResult(job.JobID)
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+	if result.Method != method || result.CisInfo.Threshold != cisThreshold {
+		t.Errorf("got method=%q threshold=%v, want %q/%v", result.Method, result.CisInfo.Threshold, method, cisThreshold)
+	}
+}
+
+func TestStatusFileNeverReadPartially(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Block: true}, nil)
+	job, _, err := s.prepareJob(models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("prepareJob: %v", err)
+	}
+	statusPath := filepath.Join(s.StorageDir(), job.JobID, "status.json")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			s.updateJobStatus(job.JobID, "processing", i%100, strings.Repeat("x", i*50))
+		}
+	}()
+
+	// ロックを取らない別プロセスの読み手を想定して、ファイルを直接読む
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		data, err := os.ReadFile(statusPath)
+		if err != nil {
+			t.Fatalf("read status.json: %v", err)
+		}
+		var status models.JobStatus
+		if err := json.Unmarshal(data, &status); err != nil {
+			t.Fatalf("partial status.json observed: %v", err)
+		}
+	}
+}
+
+func TestLoadResultAcceptsNonFiniteLiterals(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	jobDir := filepath.Join(s.StorageDir(), "job")
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(jobDir, "result.json"), `{"uniprot_id":"P12345","umf":NaN,"pair_scores":[{"i":1,"j":2,"score":Infinity}]}`)
+
+	result, err := s.loadResult("job")
+	if err != nil {
+		t.Fatalf("loadResult: %v", err)
+	}
+	if !math.IsNaN(result.UMF) || !math.IsNaN(result.PairScores[0].Score) {
+		t.Errorf("non-finite values were not read as NaN: %+v", result)
+	}
+	if _, err := json.Marshal(result); err != nil {
+		t.Errorf("result with NaN could not be encoded: %v", err)
+	}
+}
+
+func TestSetStatusTimings(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(12 * time.Minute)
+
+	tests := []struct {
+		status        string
+		updatedAt     time.Time
+		wantDuration  int64 // -1 なら省略
+		wantCompleted bool
+	}{
+		{"pending", created, -1, false},
+		{"processing", created.Add(time.Minute), 720, false},
+		{"completed", created.Add(5 * time.Minute), 300, true},
+		{"failed", created.Add(90 * time.Second), 90, true},
+	}
+	for _, tt := range tests {
+		status := models.JobStatus{Status: tt.status, CreatedAt: created, UpdatedAt: tt.updatedAt}
+		setStatusTimings(&status, now)
+
+		if tt.wantDuration < 0 {
+			if status.DurationSeconds != nil {
+				t.Errorf("%s: duration = %d, want omitted", tt.status, *status.DurationSeconds)
+			}
+		} else if status.DurationSeconds == nil || *status.DurationSeconds != tt.wantDuration {
+			t.Errorf("%s: duration = %v, want %d", tt.status, status.DurationSeconds, tt.wantDuration)
+		}
+		if got := status.CompletedAt != nil; got != tt.wantCompleted {
+			t.Errorf("%s: completed_at set = %v, want %v", tt.status, got, tt.wantCompleted)
+		} else if got && !status.CompletedAt.Equal(tt.updatedAt) {
+			t.Errorf("%s: completed_at = %v, want %v", tt.status, *status.CompletedAt, tt.updatedAt)
+		}
+	}
+}
+
+func TestStatusTimingsAreNotPersisted(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+
+	status := waitForStatus(t, s, job.JobID)
+	if status.DurationSeconds == nil || status.CompletedAt == nil {
+		t.Fatalf("finished status has no timings: %+v", status)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.StorageDir(), job.JobID, "status.json"))
+	if err != nil {
+		t.Fatalf("read status.json: %v", err)
+	}
+	if strings.Contains(string(data), "duration_seconds") || strings.Contains(string(data), "completed_at") {
+		t.Errorf("computed timings were written to status.json: %s", data)
+	}
+}
+
+func TestJobFailureWritesErrorFile(t *testing.T) {
+	runner := &FakeRunner{
+		Output: "partial stdout",
+		Stderr: "Traceback (most recent call last):\n  File \"cli.py\", line 1, in <module>\nRuntimeError: boom\n",
+		Err:    errors.New("exit status 1"),
+	}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+
+	status := waitForStatus(t, s, job.JobID)
+	if status.Status != "failed" {
+		t.Fatalf("got status %q, want failed", status.Status)
+	}
+	if want := "Python CLI failed (exit status 1): RuntimeError: boom"; status.Message != want {
+		t.Errorf("got message %q, want %q", status.Message, want)
+	}
+	data, err := os.ReadFile(filepath.Join(s.StorageDir(), job.JobID, "error.json"))
+	if err != nil {
+		t.Fatalf("error.json not written: %v", err)
+	}
+	var errorData models.ErrorResponse
+	if err := json.Unmarshal(data, &errorData); err != nil {
+		t.Fatalf("invalid error.json: %v", err)
+	}
+	if errorData.Error != status.Message || errorData.PartialResult["stderr"] != runner.Stderr || errorData.PartialResult["stdout"] != runner.Output {
+		t.Errorf("unexpected error.json: %+v", errorData)
+	}
+	var notCompleted *JobNotCompletedError
+	if _, err := s.GetResult(job.JobID); !errors.As(err, &notCompleted) || notCompleted.Status != "failed" {
+		t.Errorf("GetResult for a failed job: got %v, want JobNotCompletedError{failed}", err)
+	}
+	if _, err := s.GetResult("00000000-0000-0000-0000-000000000000"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("GetResult for an unknown job: got %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	runner := &FakeRunner{Block: true}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+
+	// Runner が呼ばれるまで待つ
+	deadline := time.Now().Add(5 * time.Second)
+	for len(runner.Calls()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := s.CancelJob(job.JobID); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	if status := waitForStatus(t, s, job.JobID); status.Status != "cancelled" {
+		t.Fatalf("got status %q, want cancelled", status.Status)
+	}
+	// キャンセル後もジョブは output.log を書くので、一時ディレクトリの削除前に終了を待つ
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.waitIdle(ctx); err != nil {
+		t.Fatalf("job did not exit after cancel: %v", err)
+	}
+
+	if err := s.CancelJob(job.JobID); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("second cancel: got %v, want ErrJobFinished", err)
+	}
+	if err := s.CancelJob("00000000-0000-0000-0000-000000000000"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("unknown job: got %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestTerminalStatusIsNotOverwritten(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	jobID := "00000000-0000-0000-0000-000000000001"
+
+	if err := s.updateJobStatus(jobID, "processing", 40, "Computing distances"); err != nil {
+		t.Fatalf("updateJobStatus: %v", err)
+	}
+	if err := s.updateJobStatus(jobID, "cancelled", 40, "Job cancelled by user"); err != nil {
+		t.Fatalf("updateJobStatus: %v", err)
+	}
+
+	// キャンセル後に届いた進捗行・失敗・待ち行列の更新は捨てる
+	if err := s.updateJobStatus(jobID, "processing", 65, "Computing distances"); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("processing after cancel: got %v, want ErrJobFinished", err)
+	}
+	if err := s.updateJobStatus(jobID, "pending", 0, "queued, 0 ahead"); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("pending after cancel: got %v, want ErrJobFinished", err)
+	}
+	s.failJob(jobID, "Python CLI failed", FailureEngineError, "")
+
+	status, err := s.GetJobStatus(jobID)
+	if err != nil {
+		t.Fatalf("GetJobStatus: %v", err)
+	}
+	if status.Status != "cancelled" || status.Progress != 40 {
+		t.Errorf("got %q (%d%%), want cancelled (40%%)", status.Status, status.Progress)
+	}
+}
+
+func TestWorkerPoolQueuesBeyondMaxConcurrent(t *testing.T) {
+	runner := &FakeRunner{Block: true}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	if err := s.SetMaxConcurrent(1); err != nil {
+		t.Fatalf("SetMaxConcurrent: %v", err)
+	}
+
+	first, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(runner.Calls()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	second, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "Q67890"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	for s.QueueDepth() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	status, err := s.GetJobStatus(second.JobID)
+	if err != nil {
+		t.Fatalf("GetJobStatus: %v", err)
+	}
+	if status.Status != "pending" || status.Message != "queued, 0 ahead" {
+		t.Fatalf("got %q %q, want pending \"queued, 0 ahead\"", status.Status, status.Message)
+	}
+
+	// 先行ジョブが終わると待機中のジョブが実行される
+	if err := s.CancelJob(first.JobID); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	for len(runner.Calls()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := len(runner.Calls()); got != 2 {
+		t.Fatalf("runner called %d times, want 2", got)
+	}
+	if err := s.CancelJob(second.JobID); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	waitForStatus(t, s, second.JobID)
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultJobTimeout は Python CLI 1回の実行の制限時間のデフォルト
+const defaultJobTimeout = 30 * time.Minute
+
+// SetJobTimeout は Python CLI 1回の実行の制限時間を設定（超えたジョブは timeout で失敗する）
+func (s *JobService) SetJobTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return fmt.Errorf("job timeout must be positive: %s", timeout)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobTimeout = timeout
+	return nil
+}
+
+// JobTimeout は Python CLI 1回の実行の制限時間を返す
+func (s *JobService) JobTimeout() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.jobTimeout
+}
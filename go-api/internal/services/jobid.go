@@ -0,0 +1,53 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// ジョブIDの形式
+const (
+	JobIDFormatUUID  = "uuid"  // 例: 3f2b8c1e-...（36文字）
+	JobIDFormatShort = "short" // 8バイト乱数の base32（13文字）
+)
+
+var (
+	shortIDEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+	uuidPattern     = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	shortIDPattern  = regexp.MustCompile(`^[a-z2-7]{13}$`)
+)
+
+// SetJobIDFormat は新規ジョブに使うIDの形式を設定
+func (s *JobService) SetJobIDFormat(format string) error {
+	switch format {
+	case JobIDFormatUUID, JobIDFormatShort:
+		s.jobIDFormat = format
+		return nil
+	default:
+		return fmt.Errorf("unknown job id format: %s (expected %q or %q)", format, JobIDFormatUUID, JobIDFormatShort)
+	}
+}
+
+// newJobID は設定された形式でジョブIDを生成
+func (s *JobService) newJobID() (string, error) {
+	if s.jobIDFormat != JobIDFormatShort {
+		return uuid.New().String(), nil
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job id: %w", err)
+	}
+	return shortIDEncoding.EncodeToString(b), nil
+}
+
+// IsValidJobID はジョブIDの形式を検証
+// 形式を切り替えた後も既存ジョブを読めるよう、設定に関わらず両方の形式を受け付ける
+// 形式が正しければ "/" や ".." を含まないので、そのままパスに使える
+func IsValidJobID(jobID string) bool {
+	return uuidPattern.MatchString(jobID) || shortIDPattern.MatchString(jobID)
+}
@@ -0,0 +1,42 @@
+package services
+
+import "fmt"
+
+// 入力の大きさの既定上限（0 は無制限）
+// ヒートマップは残基数 N に対して N×N のセルを持つので、3000 残基でも 900 万セルになる
+const (
+	defaultMaxResidues   = 3000
+	defaultMaxStructures = 300
+)
+
+// SetInputLimits は解析する UniProt ID の残基数・PDB エントリ数の上限を設定（0 で無制限）
+// 上限を超える入力は Python CLI がダウンロード前に断り、ジョブは input_too_large で失敗する
+// 残基数の上限を超える結果（上限を下げる前のジョブなど）はヒートマップを持たずに返す
+func (s *JobService) SetInputLimits(maxResidues, maxStructures int) error {
+	if maxResidues < 0 {
+		return fmt.Errorf("max residues must be >= 0: %d", maxResidues)
+	}
+	if maxStructures < 0 {
+		return fmt.Errorf("max structures must be >= 0: %d", maxStructures)
+	}
+	s.maxResidues = maxResidues
+	s.maxStructures = maxStructures
+	return nil
+}
+
+// inputLimitArgs は上限を Python CLI の引数にする（無制限の上限は渡さない）
+func (s *JobService) inputLimitArgs() []string {
+	var args []string
+	if s.maxResidues > 0 {
+		args = append(args, "--max-residues", fmt.Sprint(s.maxResidues))
+	}
+	if s.maxStructures > 0 {
+		args = append(args, "--max-structures", fmt.Sprint(s.maxStructures))
+	}
+	return args
+}
+
+// heatmapTooLarge は残基数 n のヒートマップ（n×n）を組み立てずに省くべきか
+func (s *JobService) heatmapTooLarge(n int) bool {
+	return s.maxResidues > 0 && n > s.maxResidues
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestInputLimitArgs(t *testing.T) {
+	runner := &FakeRunner{Files: summaryFixture()}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	if err := s.SetInputLimits(1500, 0); err != nil {
+		t.Fatalf("SetInputLimits: %v", err)
+	}
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	args := runner.Calls()[0]
+	if got := argValue(args, "--max-residues"); got != "1500" {
+		t.Errorf("--max-residues = %q, want 1500", got)
+	}
+	// 無制限の上限は渡さない
+	if strings.Contains(strings.Join(args, " "), "--max-structures") {
+		t.Errorf("unexpected --max-structures in %v", args)
+	}
+
+	if err := s.SetInputLimits(-1, 0); err == nil {
+		t.Error("SetInputLimits(-1, 0) should fail")
+	}
+}
+
+func TestHeatmapOmittedOverMaxResidues(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	// summaryFixture の Length は 3 残基
+	if err := s.SetInputLimits(2, 0); err != nil {
+		t.Fatalf("SetInputLimits: %v", err)
+	}
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	if status := waitForStatus(t, s, job.JobID); status.Status != "completed" {
+		t.Fatalf("got status %q, want completed", status.Status)
+	}
+
+	result, err := s.GetResult(job.JobID)
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+	if result.Heatmap != nil {
+		t.Errorf("heatmap should be omitted, got size %d", result.Heatmap.Size)
+	}
+	if result.NumResidues != 3 || len(result.PairScores) != 3 {
+		t.Errorf("scalar results missing: residues=%d pairs=%d", result.NumResidues, len(result.PairScores))
+	}
+	if _, err := s.GetHeatmapValues(job.JobID, 0, nil); !errors.Is(err, ErrNoHeatmap) {
+		t.Errorf("GetHeatmapValues: got %v, want ErrNoHeatmap", err)
+	}
+}
+
+func TestInputTooLargeFailureReason(t *testing.T) {
+	runner := &FakeRunner{
+		Output: "Processing P12345 ...\nToo large: 4000 residues (max 3000)",
+		Stderr: "\nInputTooLargeError: P12345 (4000 residues (max 3000))\nAborted!",
+		Err:    errors.New("exit status 1"),
+	}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	status := waitForStatus(t, s, job.JobID)
+	if status.Status != "failed" || status.Reason != FailureReasonInputTooLarge {
+		t.Fatalf("got %q reason %q, want failed with %q", status.Status, status.Reason, FailureReasonInputTooLarge)
+	}
+	if status.FailureReason != FailureInvalidInput {
+		t.Errorf("failure_reason = %q, want %q", status.FailureReason, FailureInvalidInput)
+	}
+	if want := inputTooLargeMessage + ": P12345 (4000 residues (max 3000))"; status.Message != want {
+		t.Errorf("message = %q, want %q", status.Message, want)
+	}
+	// 再実行しても結果は変わらないので再試行しない
+	if got := len(runner.Calls()); got != 1 {
+		t.Errorf("runner called %d times, want 1", got)
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// ストレージ空き容量の閾値（全体に対する割合）
+const (
+	storageDegradedRatio   = 0.10
+	storageOverloadedRatio = 0.05
+)
+
+// LoadStatus は実行中ジョブ数・キュー長・ストレージ空き容量から負荷状況を算出
+func (s *JobService) LoadStatus() (*models.LoadStatus, error) {
+	s.mu.RLock()
+	running := len(s.running)
+	queued := len(s.waiting)
+	s.mu.RUnlock()
+	maxConcurrent := cap(s.workers)
+
+	free, total, err := diskUsage(s.storageDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat storage: %w", err)
+	}
+
+	load := &models.LoadStatus{
+		Status:            "ok",
+		RunningJobs:       running,
+		MaxConcurrency:    maxConcurrent,
+		QueueDepth:        queued,
+		StorageFreeBytes:  free,
+		StorageTotalBytes: total,
+	}
+
+	freeRatio := 1.0
+	if total > 0 {
+		freeRatio = float64(free) / float64(total)
+	}
+	switch {
+	case freeRatio < storageOverloadedRatio, queued >= maxConcurrent*queueOverloadFactor:
+		load.Status = "overloaded"
+	case freeRatio < storageDegradedRatio, queued > 0:
+		load.Status = "degraded"
+	}
+
+	load.AcceptingNewJobs = load.Status != "overloaded"
+	return load, nil
+}
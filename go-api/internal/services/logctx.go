@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID はリクエスト ID を ctx に載せる
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext は ctx のリクエスト ID を返す（無ければ ""）
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// jobLogger は job_id と、ctx にあれば request_id を付けたロガーを返す
+// ジョブの非同期処理はこのロガーを持ち回り、ログ行を元のリクエストと関連付ける
+func (s *JobService) jobLogger(ctx context.Context, jobID string) *slog.Logger {
+	logger := s.logger.With("job_id", jobID)
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		logger = logger.With("request_id", requestID)
+	}
+	return logger
+}
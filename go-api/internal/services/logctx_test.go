@@ -0,0 +1,57 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// syncBuffer は複数 goroutine から書き込まれるログ用のバッファ
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestJobLogLinesCarryRequestID(t *testing.T) {
+	var logs syncBuffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, logger)
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	job, err := s.CreateJob(ctx, models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	var found int
+	for _, line := range strings.Split(logs.String(), "\n") {
+		if !strings.Contains(line, "executeDSAAnalysis:") {
+			continue
+		}
+		found++
+		if !strings.Contains(line, "job_id="+job.JobID) || !strings.Contains(line, "request_id=req-123") {
+			t.Errorf("log line missing job_id/request_id: %s", line)
+		}
+	}
+	if found == 0 {
+		t.Fatal("no executeDSAAnalysis log lines captured")
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// jobDurationBuckets はジョブ実行時間ヒストグラムのバケット上限（秒）
+// タイムアウト（30分）までを p50/p95 が読める粒度で区切る
+var jobDurationBuckets = []float64{10, 30, 60, 120, 180, 300, 450, 600, 900, 1200, 1800}
+
+// metrics は /metrics で公開するジョブの統計（メモリ上のみ）
+// 実行中・待機中の件数は RegisterMetrics で JobService から読む
+type metrics struct {
+	jobsCreated   prometheus.Counter
+	jobsCompleted prometheus.Counter
+	jobsFailed    prometheus.Counter
+	exitCodes     *prometheus.CounterVec
+	duration      prometheus.Histogram
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		jobsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "flex_jobs_created_total",
+			Help: "Jobs created.",
+		}),
+		jobsCompleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "flex_jobs_completed_total",
+			Help: "Jobs that finished successfully.",
+		}),
+		jobsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "flex_jobs_failed_total",
+			Help: "Jobs that failed.",
+		}),
+		exitCodes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "flex_python_exit_codes_total",
+			Help: "Python CLI exit codes.",
+		}, []string{"code"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "flex_job_duration_seconds",
+			Help:    "Time from processing to completed.",
+			Buckets: jobDurationBuckets,
+		}),
+	}
+}
+
+// RegisterMetrics はジョブの統計を Prometheus のレジストリに登録する（main では prometheus.DefaultRegisterer）
+func (s *JobService) RegisterMetrics(reg prometheus.Registerer) error {
+	m := s.metrics
+	collectors := []prometheus.Collector{
+		m.jobsCreated, m.jobsCompleted, m.jobsFailed, m.exitCodes, m.duration,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "flex_jobs_running",
+			Help: "Python analyses currently running.",
+		}, func() float64 {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			return float64(len(s.running))
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "flex_jobs_queued",
+			Help: "Jobs waiting for a worker slot.",
+		}, func() float64 {
+			return float64(s.QueueDepth())
+		}),
+	}
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *metrics) jobCreated() {
+	m.jobsCreated.Inc()
+}
+
+// jobFinished は終了状態への遷移を数える
+func (m *metrics) jobFinished(status string) {
+	switch status {
+	case "completed":
+		m.jobsCompleted.Inc()
+	case "failed":
+		m.jobsFailed.Inc()
+	}
+}
+
+// observeDuration は processing から completed までの時間を記録
+func (m *metrics) observeDuration(d time.Duration) {
+	m.duration.Observe(d.Seconds())
+}
+
+// observeExit は Python プロセスの終了コードを記録（起動できなかった場合は記録しない）
+func (m *metrics) observeExit(err error) {
+	code := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return
+		}
+		code = exitErr.ExitCode()
+	}
+	m.exitCodes.WithLabelValues(strconv.Itoa(code)).Inc()
+}
+
+// durationStats は記録した実行時間の件数と合計（秒）を返す
+func (m *metrics) durationStats() (count uint64, sum float64) {
+	var pb dto.Metric
+	if err := m.duration.Write(&pb); err != nil {
+		return 0, 0
+	}
+	return pb.GetHistogram().GetSampleCount(), pb.GetHistogram().GetSampleSum()
+}
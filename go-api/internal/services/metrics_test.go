@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestMetricsCountJobs(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	reg := prometheus.NewRegistry()
+	if err := s.RegisterMetrics(reg); err != nil {
+		t.Fatalf("RegisterMetrics: %v", err)
+	}
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	failing := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Err: errors.New("exit status 1")}, nil)
+	job, err = failing.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, failing, job.JobID)
+
+	want := `
+# HELP flex_jobs_completed_total Jobs that finished successfully.
+# TYPE flex_jobs_completed_total counter
+flex_jobs_completed_total 1
+# HELP flex_jobs_created_total Jobs created.
+# TYPE flex_jobs_created_total counter
+flex_jobs_created_total 1
+# HELP flex_jobs_failed_total Jobs that failed.
+# TYPE flex_jobs_failed_total counter
+flex_jobs_failed_total 0
+# HELP flex_jobs_queued Jobs waiting for a worker slot.
+# TYPE flex_jobs_queued gauge
+flex_jobs_queued 0
+# HELP flex_jobs_running Python analyses currently running.
+# TYPE flex_jobs_running gauge
+flex_jobs_running 0
+# HELP flex_python_exit_codes_total Python CLI exit codes.
+# TYPE flex_python_exit_codes_total counter
+flex_python_exit_codes_total{code="0"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"flex_jobs_created_total", "flex_jobs_completed_total", "flex_jobs_failed_total",
+		"flex_jobs_running", "flex_jobs_queued", "flex_python_exit_codes_total"); err != nil {
+		t.Error(err)
+	}
+	if count, _ := s.metrics.durationStats(); count != 1 {
+		t.Errorf("flex_job_duration_seconds_count = %d, want 1", count)
+	}
+
+	if got := testutil.ToFloat64(failing.metrics.jobsFailed); got != 1 {
+		t.Errorf("flex_jobs_failed_total = %v, want 1", got)
+	}
+	if count, _ := failing.metrics.durationStats(); count != 0 {
+		t.Errorf("flex_job_duration_seconds_count for a failed job = %d, want 0", count)
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// GetPairScores はペアスコアを Score 降順（NaN は最後）でページングして返す
+// minScore が nil でなければ Score >= minScore のペアのみ対象（NaN は除く）。limit <= 0 は残り全件
+func (s *JobService) GetPairScores(jobID string, offset, limit int, minScore *float64) (*models.PairScoresPage, error) {
+	result, err := s.GetResult(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]models.PairScore, 0, len(result.PairScores))
+	for _, ps := range result.PairScores {
+		// NaN はどの min_score も満たさない
+		if minScore != nil && !(ps.Score >= *minScore) {
+			continue
+		}
+		scores = append(scores, ps)
+	}
+
+	// 最も柔軟なペアが先頭に来るように
+	sortPairScores(scores)
+
+	total := len(scores)
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	return &models.PairScoresPage{
+		JobID:      jobID,
+		Total:      total,
+		Offset:     offset,
+		Limit:      limit,
+		PairScores: scores[offset:end],
+	}, nil
+}
+
+// sortPairScores は scores を Score 降順に並べ替える
+// NaN は大小を比べられず比較関数の順序が崩れるので、明示的に最後に置く
+func sortPairScores(scores []models.PairScore) {
+	sort.SliceStable(scores, func(a, b int) bool {
+		sa, sb := scores[a].Score, scores[b].Score
+		if math.IsNaN(sb) {
+			return !math.IsNaN(sa)
+		}
+		return sa > sb
+	})
+}
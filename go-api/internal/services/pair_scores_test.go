@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestGetPairScoresSortsAndPaginates(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	all, err := s.GetPairScores(job.JobID, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("GetPairScores: %v", err)
+	}
+	if all.Total != 3 || len(all.PairScores) != 3 {
+		t.Fatalf("got total=%d len=%d, want 3/3", all.Total, len(all.PairScores))
+	}
+	for i := 1; i < len(all.PairScores); i++ {
+		if all.PairScores[i-1].Score < all.PairScores[i].Score {
+			t.Fatalf("pair scores not sorted descending: %+v", all.PairScores)
+		}
+	}
+
+	page, err := s.GetPairScores(job.JobID, 1, 1, nil)
+	if err != nil {
+		t.Fatalf("GetPairScores: %v", err)
+	}
+	if page.Total != 3 || len(page.PairScores) != 1 || page.PairScores[0] != all.PairScores[1] {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	minScore := all.PairScores[0].Score
+	filtered, err := s.GetPairScores(job.JobID, 0, 10, &minScore)
+	if err != nil {
+		t.Fatalf("GetPairScores: %v", err)
+	}
+	if filtered.Total < 1 || filtered.PairScores[0] != all.PairScores[0] {
+		t.Fatalf("unexpected filtered page: %+v", filtered)
+	}
+	for _, ps := range filtered.PairScores {
+		if ps.Score < minScore {
+			t.Fatalf("score %v below min_score %v", ps.Score, minScore)
+		}
+	}
+}
+
+func TestGetPairScoresOrdersNaNLast(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	jobID := "00000000-0000-0000-0000-000000000001"
+	jobDir := filepath.Join(s.StorageDir(), jobID)
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(jobDir, "status.json"), `{"job_id":"`+jobID+`","status":"completed","progress":100,"message":""}`)
+	writeFile(t, filepath.Join(jobDir, "result.json"), `{"uniprot_id":"P12345","pair_scores":[`+
+		`{"i":1,"j":2,"score":0.5},{"i":1,"j":3,"score":NaN},{"i":2,"j":3,"score":2.5},{"i":2,"j":4,"score":NaN},{"i":3,"j":4,"score":1.5}]}`)
+
+	all, err := s.GetPairScores(jobID, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("GetPairScores: %v", err)
+	}
+	want := []float64{2.5, 1.5, 0.5}
+	if all.Total != 5 {
+		t.Fatalf("got total=%d, want 5", all.Total)
+	}
+	for i, score := range want {
+		if all.PairScores[i].Score != score {
+			t.Errorf("pair_scores[%d].score = %v, want %v", i, all.PairScores[i].Score, score)
+		}
+	}
+	for _, ps := range all.PairScores[len(want):] {
+		if !math.IsNaN(ps.Score) {
+			t.Errorf("got %v after the finite scores, want NaN", ps.Score)
+		}
+	}
+
+	// NaN は min_score を満たさない
+	minScore := 1.0
+	filtered, err := s.GetPairScores(jobID, 0, 0, &minScore)
+	if err != nil {
+		t.Fatalf("GetPairScores: %v", err)
+	}
+	if filtered.Total != 2 || filtered.PairScores[0].Score != 2.5 || filtered.PairScores[1].Score != 1.5 {
+		t.Errorf("unexpected filtered page: %+v", filtered)
+	}
+}
+
+func TestGetPerResidueScoresBounds(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	scores, err := s.GetPerResidueScores(job.JobID)
+	if err != nil {
+		t.Fatalf("GetPerResidueScores: %v", err)
+	}
+	if len(scores.PerResidueScores) != 3 {
+		t.Fatalf("got %d residues, want 3", len(scores.PerResidueScores))
+	}
+	for _, rs := range scores.PerResidueScores {
+		if rs.Score < scores.ScoreMin || rs.Score > scores.ScoreMax {
+			t.Errorf("score %v outside [%v, %v]", rs.Score, scores.ScoreMin, scores.ScoreMax)
+		}
+	}
+	if scores.ScoreMin >= scores.ScoreMax {
+		t.Errorf("expected distinct bounds, got [%v, %v]", scores.ScoreMin, scores.ScoreMax)
+	}
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// ErrNoPartialResult は完了せずに終わったジョブに、結果を組み立てられる CSV が残っていない場合のエラー
+var ErrNoPartialResult = errors.New("no partial result available")
+
+// getPartialResult は失敗・キャンセル・中断したジョブが残した summary.csv などから結果を組み立てる
+// 途中の出力なので値が欠けていることがあり、キャッシュもしない（Partial を立てて返す）
+func (s *JobService) getPartialResult(jobID string) (*models.NotebookDSAResult, error) {
+	result, err := s.convertSummaryCSVToResult(jobID)
+	if err != nil {
+		s.logger.Debug("getPartialResult: cannot build result", "job_id", jobID, "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrNoPartialResult, err)
+	}
+	result.Partial = true
+	return result, nil
+}
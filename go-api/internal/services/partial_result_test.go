@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// runFailedJob は files を出力した後に失敗するジョブを作る
+func runFailedJob(t *testing.T, files map[string]string) (*JobService, string) {
+	t.Helper()
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: files, Err: errors.New("exit status 1")}, nil)
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	if status := waitForStatus(t, s, job.JobID); status.Status != "failed" {
+		t.Fatalf("got status %q, want failed", status.Status)
+	}
+	return s, job.JobID
+}
+
+func TestGetProjectedResultPartial(t *testing.T) {
+	s, jobID := runFailedJob(t, summaryFixture())
+
+	if _, err := s.GetProjectedResult(jobID, ResultProjection{}); !errors.Is(err, ErrJobNotCompleted) {
+		t.Fatalf("without Partial: got %v, want ErrJobNotCompleted", err)
+	}
+
+	result, err := s.GetProjectedResult(jobID, ResultProjection{Partial: true})
+	if err != nil {
+		t.Fatalf("GetProjectedResult: %v", err)
+	}
+	if !result.Partial || result.UniProtID != "P12345" || len(result.PairScores) != 3 {
+		t.Errorf("unexpected partial result: partial=%v uniprot=%q pairs=%d", result.Partial, result.UniProtID, len(result.PairScores))
+	}
+
+	// 途中の結果はキャッシュしない（通常の取得は引き続き未完了扱い）
+	if _, err := s.GetResult(jobID); !errors.Is(err, ErrJobNotCompleted) {
+		t.Errorf("GetResult after partial: got %v, want ErrJobNotCompleted", err)
+	}
+}
+
+func TestGetProjectedResultPartialWithoutOutput(t *testing.T) {
+	s, jobID := runFailedJob(t, map[string]string{"summary.csv": "uniprotid,seq_ratio,Entries,Chains,Length,Length(%),UMF\n"})
+
+	if _, err := s.GetProjectedResult(jobID, ResultProjection{Partial: true}); !errors.Is(err, ErrNoPartialResult) {
+		t.Errorf("got %v, want ErrNoPartialResult", err)
+	}
+}
+
+func TestGetProjectedResultPartialCompleted(t *testing.T) {
+	s, jobID := runStructuresJob(t, summaryFixture())
+
+	result, err := s.GetProjectedResult(jobID, ResultProjection{Partial: true})
+	if err != nil {
+		t.Fatalf("GetProjectedResult: %v", err)
+	}
+	if result.Partial {
+		t.Error("completed job result should not be marked partial")
+	}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SetKeepPDBFiles はジョブの終了後も構造ファイル（FileLayout.PDBDir）を残すかを設定（調査用、既定は削除）
+// 構造ファイルは結果の組み立て・配信に使わず、ジョブのディスク使用量の大半を占める
+func (s *JobService) SetKeepPDBFiles(keep bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keepPDBFiles = keep
+}
+
+// KeepPDBFiles はジョブの終了後も構造ファイルを残すかを返す
+func (s *JobService) KeepPDBFiles() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keepPDBFiles
+}
+
+// isPDBFile は作業ディレクトリからの相対パス rel が構造ファイルか（残さない設定なら保存先にアップロードしない）
+func (s *JobService) isPDBFile(rel string) bool {
+	return strings.HasPrefix(filepath.ToSlash(rel), s.layout.PDBDir+"/")
+}
+
+// removePDBFiles はジョブの作業ディレクトリから構造ファイルを削除する
+// 完了・失敗・キャンセルのどれで終わっても executeDSAAnalysis の終了時に呼ぶ（KeepPDBFiles なら何もしない）
+func (s *JobService) removePDBFiles(jobID string) {
+	if s.KeepPDBFiles() {
+		return
+	}
+	jobDir, err := s.jobDir(jobID)
+	if err == nil {
+		err = os.RemoveAll(filepath.Join(jobDir, s.layout.PDBDir))
+	}
+	if err != nil {
+		s.logger.Warn("removePDBFiles: failed to remove structure files", "job_id", jobID, "error", err)
+	}
+}
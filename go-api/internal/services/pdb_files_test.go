@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestPDBFilesRemovedAfterJob(t *testing.T) {
+	files := summaryFixture()
+	files["pdb_files/1a00.cif"] = "data_1A00\n"
+
+	for _, keep := range []bool{false, true} {
+		s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: files}, nil)
+		s.SetKeepPDBFiles(keep)
+		job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+		if err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+		if status := waitForStatus(t, s, job.JobID); status.Status != "completed" {
+			t.Fatalf("got status %q, want completed", status.Status)
+		}
+
+		jobDir, _ := s.jobDir(job.JobID)
+		waitForPDBFiles(t, filepath.Join(jobDir, "pdb_files"), keep)
+		if _, err := os.Stat(filepath.Join(jobDir, "summary.csv")); err != nil {
+			t.Errorf("keep=%v: summary.csv should be kept: %v", keep, err)
+		}
+	}
+}
+
+func TestPDBFilesRemovedAfterCancel(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: map[string]string{"pdb_files/1a00.cif": "data_1A00\n"}, Block: true}, nil)
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	jobDir, _ := s.jobDir(job.JobID)
+	waitForPDBFiles(t, filepath.Join(jobDir, "pdb_files"), true)
+
+	if err := s.CancelJob(job.JobID); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	waitForPDBFiles(t, filepath.Join(jobDir, "pdb_files"), false)
+}
+
+// waitForPDBFiles は dir の有無が exists になるまで待つ（削除は終了ステータスを書いた後に行われる）
+func waitForPDBFiles(t *testing.T, dir string, exists bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(dir); (err == nil) == exists {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s: exists should be %v", dir, exists)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestExplicitPDBIDs(t *testing.T) {
+	files := summaryFixture()
+	files["atom_coord/2b00.csv"] = ""
+	files["structures_P12345.csv"] = "pdb_id,method,resolution,num_chains,chains_used,mutation,included,reason\n" +
+		"2B00,X-ray,2.0,1,1,normal,True,\n" +
+		"1A00,NMR,,1,1,normal,True,\n" +
+		"3C00,,,0,0,,False,not_in_uniprot\n"
+	runner := &FakeRunner{Files: files}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345", PDBIDs: []string{"2b00", " 1A00", "2B00", "3c00"}})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	if status := waitForStatus(t, s, job.JobID); status.Status != "completed" {
+		t.Fatalf("got status %q, want completed", status.Status)
+	}
+
+	// 大文字・重複なしで指定順に渡す
+	if got := argValue(runner.Calls()[0], "--pdb-ids"); got != "2B00 1A00 3C00" {
+		t.Errorf("--pdb-ids = %q, want \"2B00 1A00 3C00\"", got)
+	}
+
+	result, err := s.GetResult(job.JobID)
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+	if strings.Join(result.PDBIDs, ",") != "2B00,1A00" {
+		t.Errorf("pdb_ids = %v, want [2B00 1A00] in the requested order", result.PDBIDs)
+	}
+	if len(result.ExcludedStructures) != 1 || result.ExcludedStructures[0].Reason != "not_in_uniprot" {
+		t.Errorf("excluded_structures = %+v, want 3C00 not_in_uniprot", result.ExcludedStructures)
+	}
+}
+
+func TestOrderedPDBIDs(t *testing.T) {
+	requested := []string{"2B00", "1A00", "3C00"}
+	if got := orderedPDBIDs(requested, []string{"1A00", "2B00"}, nil); strings.Join(got, ",") != "2B00,1A00" {
+		t.Errorf("with atom_coord: got %v", got)
+	}
+	// atom_coord が残っていなければ、除外されなかった指定
+	if got := orderedPDBIDs(requested, nil, []string{"3C00"}); strings.Join(got, ",") != "2B00,1A00" {
+		t.Errorf("without atom_coord: got %v", got)
+	}
+}
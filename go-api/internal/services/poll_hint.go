@@ -0,0 +1,57 @@
+package services
+
+import (
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// 未完了のジョブを問い合わせ直すまでの間隔
+const (
+	defaultPollInterval = 5 * time.Second // 実行時間の実績がまだ無い場合
+	minPollInterval     = 2 * time.Second
+	maxPollInterval     = 60 * time.Second
+)
+
+// averageDuration は完了したジョブの平均実行時間（実績が無ければ 0）
+func (m *metrics) averageDuration() time.Duration {
+	count, sum := m.durationStats()
+	if count == 0 {
+		return 0
+	}
+	return time.Duration(sum / float64(count) * float64(time.Second))
+}
+
+// PollHint は未完了のジョブ status をいつ問い合わせ直せばよいかの目安を返す
+// 残り時間は起動後に完了したジョブの平均実行時間から、実行中なら経過時間を引いて見積もる（実績が無ければ nil）
+// 再試行間隔は残り時間の 1/4 を 2 秒から 60 秒に収めたもの、見積もれなければ 5 秒
+func (s *JobService) PollHint(status *models.JobStatus) models.PollHint {
+	hint := models.PollHint{
+		Status:            status.Status,
+		Progress:          status.Progress,
+		RetryAfterSeconds: int(defaultPollInterval / time.Second),
+	}
+
+	average := s.metrics.averageDuration()
+	if average <= 0 {
+		return hint
+	}
+
+	remaining := average
+	s.mu.RLock()
+	startedAt, running := s.running[status.JobID]
+	s.mu.RUnlock()
+	if running {
+		remaining -= time.Since(startedAt)
+	}
+	if remaining < 0 {
+		// 平均より長くかかっている（もうすぐ終わるはずなので短い間隔で問い合わせてもらう）
+		remaining = 0
+	}
+	seconds := int64(remaining.Round(time.Second) / time.Second)
+	hint.EstimatedRemainingSeconds = &seconds
+
+	interval := min(max(remaining/4, minPollInterval), maxPollInterval)
+	hint.RetryAfterSeconds = int(interval.Round(time.Second) / time.Second)
+	return hint
+}
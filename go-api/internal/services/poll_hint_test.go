@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestPollHint(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	status := &models.JobStatus{JobID: "job", Status: "pending", Progress: 0}
+
+	hint := s.PollHint(status)
+	if hint.EstimatedRemainingSeconds != nil || hint.RetryAfterSeconds != 5 {
+		t.Fatalf("without history: got %+v, want no estimate and 5s", hint)
+	}
+
+	s.metrics.observeDuration(100 * time.Second)
+	s.metrics.observeDuration(140 * time.Second)
+	hint = s.PollHint(status)
+	if hint.EstimatedRemainingSeconds == nil || *hint.EstimatedRemainingSeconds != 120 || hint.RetryAfterSeconds != 30 {
+		t.Errorf("pending: got %+v, want 120s remaining and 30s", hint)
+	}
+
+	// 平均より長く実行中なら残り 0 秒、間隔は下限
+	s.mu.Lock()
+	s.running["job"] = time.Now().Add(-10 * time.Minute)
+	s.mu.Unlock()
+	status.Status = "processing"
+	hint = s.PollHint(status)
+	if hint.EstimatedRemainingSeconds == nil || *hint.EstimatedRemainingSeconds != 0 || hint.RetryAfterSeconds != 2 {
+		t.Errorf("overdue: got %+v, want 0s remaining and 2s", hint)
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// pythonCandidates は試す Python バイナリの候補（設定値 → PATH 上の python3 → python）
+func (s *JobService) pythonCandidates() []string {
+	candidates := []string{s.pythonBin}
+	if path, err := exec.LookPath("python3"); err == nil {
+		candidates = append(candidates, path)
+	}
+	candidates = append(candidates, "python")
+
+	// 重複を除く
+	seen := make(map[string]bool)
+	var result []string
+	for _, c := range candidates {
+		if c != "" && !seen[c] {
+			seen[c] = true
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// ResolvePython は flex_analyzer を import できる Python バイナリを探して設定する
+// どの候補でも import できない場合は各候補の失敗理由をまとめたエラーを返す
+func (s *JobService) ResolvePython(ctx context.Context) error {
+	var failures []string
+	for _, bin := range s.pythonCandidates() {
+		stderr, err := s.importEngine(ctx, bin)
+		if err == nil {
+			if bin != s.pythonBin {
+				s.logger.Warn("ResolvePython: falling back to another Python binary", "configured", s.pythonBin, "using", bin)
+			}
+			s.pythonBin = bin
+			s.recordPythonCheck(nil)
+			return nil
+		}
+		s.logger.Debug("ResolvePython: candidate failed", "python", bin, "error", err, "stderr", strings.TrimSpace(string(stderr)))
+		failures = append(failures, fmt.Sprintf("%s: %v", bin, err))
+	}
+	return fmt.Errorf("no Python binary can import flex_analyzer in %s (%s)", s.pythonEngineDir, strings.Join(failures, "; "))
+}
+
+// importEngine は bin で flex_analyzer を import できるか確認し、標準エラー出力を返す
+func (s *JobService) importEngine(ctx context.Context, bin string) ([]byte, error) {
+	_, stderr, err := s.runner.Run(ctx, []string{bin, "-c", "import flex_analyzer"}, s.pythonEngineDir, s.pythonEnv(), nil)
+	return stderr, err
+}
+
+// PythonBin は解析に使う Python バイナリを返す
+func (s *JobService) PythonBin() string {
+	return s.pythonBin
+}
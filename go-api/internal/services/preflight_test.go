@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// importRunner は指定したバイナリでのみ import が成功する Runner
+type importRunner struct {
+	working string
+}
+
+func (r importRunner) Run(ctx context.Context, args []string, dir string, env []string, onLine func(line string)) ([]byte, []byte, error) {
+	if args[0] == r.working {
+		return nil, nil, nil
+	}
+	return nil, []byte("ModuleNotFoundError: No module named 'flex_analyzer'"), errors.New("exit status 1")
+}
+
+func TestResolvePythonFallsBack(t *testing.T) {
+	s := NewJobService(t.TempDir(), "/nonexistent/python", "", importRunner{working: "python"}, nil)
+	if err := s.ResolvePython(context.Background()); err != nil {
+		t.Fatalf("ResolvePython: %v", err)
+	}
+	if got := s.PythonBin(); got != "python" {
+		t.Errorf("got python %q, want fallback %q", got, "python")
+	}
+
+	s = NewJobService(t.TempDir(), "/nonexistent/python", "", importRunner{}, nil)
+	if err := s.ResolvePython(context.Background()); err == nil {
+		t.Error("ResolvePython should fail when no candidate can import flex_analyzer")
+	}
+}
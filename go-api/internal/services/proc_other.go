@@ -0,0 +1,8 @@
+//go:build !unix
+
+package services
+
+import "os/exec"
+
+// setProcessGroup は未対応プラットフォームでは何もしない（exec.CommandContext の既定の kill に任せる）
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package services
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup は子プロセスを独立したプロセスグループで起動し、
+// キャンセル時にグループごと kill されるようにする
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		// 負の PID でプロセスグループ全体にシグナルを送る
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
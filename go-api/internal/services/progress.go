@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// progressMarker は Python CLI（--verbose）の出力に現れる段階の目印
+type progressMarker struct {
+	contains string
+	progress int
+	message  string
+}
+
+// 出力に現れる順に並べる
+var progressMarkers = []progressMarker{
+	{"Notebook DSA Analysis Tool", 5, "Starting analysis"},
+	{"### Preparation", 10, "Fetching UniProt and PDB metadata"},
+	{"### normal & mutant", 60, "Aligning sequences"},
+	{"chains are being processed", 65, "Computing distances"},
+	{"Distance-Score plot saved", 85, "Generating distance-score plot"},
+	{"heatmap saved", 90, "Generating heatmap"},
+	{"Finished", 95, "Writing summary"},
+}
+
+// PDB ダウンロード・判定の進捗行 例: " (3/12) judge: 1A00 normal"
+var judgeLinePattern = regexp.MustCompile(`\((\d+)/(\d+)\) judge:`)
+
+// PDB 処理の進捗を割り当てる範囲（%）
+const (
+	downloadProgressStart = 15
+	downloadProgressEnd   = 55
+)
+
+// parseProgress は出力1行から推定進捗（%）とメッセージを返す。該当しなければ ok=false
+func parseProgress(line string) (progress int, message string, ok bool) {
+	if m := judgeLinePattern.FindStringSubmatch(line); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		total, _ := strconv.Atoi(m[2])
+		if total > 0 {
+			progress = downloadProgressStart + (downloadProgressEnd-downloadProgressStart)*n/total
+			return progress, fmt.Sprintf("Downloading structures (%d/%d)", n, total), true
+		}
+	}
+
+	for _, marker := range progressMarkers {
+		if strings.Contains(line, marker.contains) {
+			return marker.progress, marker.message, true
+		}
+	}
+	return 0, "", false
+}
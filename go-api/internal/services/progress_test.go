@@ -0,0 +1,27 @@
+package services
+
+import "testing"
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		line     string
+		progress int
+		message  string
+		ok       bool
+	}{
+		{"### Preparation #########################################", 10, "Fetching UniProt and PDB metadata", true},
+		{" (1/4) judge: 1A00 normal", 25, "Downloading structures (1/4)", true},
+		{" (4/4) judge: 1A03 substitution", 55, "Downloading structures (4/4)", true},
+		{"12 chains are being processed ...", 65, "Computing distances", true},
+		{"Processing P12345 Finished", 95, "Writing summary", true},
+		{"Homo sapiens hemoglobin", 0, "", false},
+	}
+
+	for _, tt := range tests {
+		progress, message, ok := parseProgress(tt.line)
+		if progress != tt.progress || message != tt.message || ok != tt.ok {
+			t.Errorf("parseProgress(%q) = (%d, %q, %t), want (%d, %q, %t)",
+				tt.line, progress, message, ok, tt.progress, tt.message, tt.ok)
+		}
+	}
+}
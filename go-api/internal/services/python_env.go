@@ -0,0 +1,34 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envKeyPattern は環境変数名として受け付ける形式
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pythonEnv は Python CLI（解析・事前確認・dry run のすべて）を起動するときの環境変数
+// 作業ディレクトリは python-engine なので PYTHONPATH は ./src を指す
+// SetPythonEnv の値は最後に足すので、同じキーなら os/exec の規則で上書きになる（PYTHONPATH も差し替えられる）
+func (s *JobService) pythonEnv() []string {
+	env := append(os.Environ(), "PYTHONPATH=./src")
+	return append(env, s.pythonExtraEnv...)
+}
+
+// SetPythonEnv は Python CLI に追加で渡す環境変数を "KEY=VALUE" の形で設定
+// PDB のキャッシュ先や API キーなど、エンジン側が必要とする値をデプロイごとに渡すために使う
+func (s *JobService) SetPythonEnv(vars []string) error {
+	env := make([]string, 0, len(vars))
+	for _, kv := range vars {
+		key, _, ok := strings.Cut(kv, "=")
+		if !ok || !envKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid environment variable %q: want KEY=VALUE", kv)
+		}
+		env = append(env, kv)
+	}
+	s.pythonExtraEnv = env
+	return nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestSetPythonEnv(t *testing.T) {
+	runner := &FakeRunner{Files: summaryFixture()}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	if err := s.SetPythonEnv([]string{"PYTHONPATH=/opt/engine/src", "PDB_CACHE=/data/pdb", "EMPTY="}); err != nil {
+		t.Fatalf("SetPythonEnv: %v", err)
+	}
+
+	env := s.pythonEnv()
+	if !slices.Contains(env, "PDB_CACHE=/data/pdb") || !slices.Contains(env, "EMPTY=") {
+		t.Errorf("extra variables missing from %v", env)
+	}
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	// 後から足した値が既定の PYTHONPATH より優先される
+	cmd, err := s.GetJobCommand(job.JobID)
+	if err != nil {
+		t.Fatalf("GetJobCommand: %v", err)
+	}
+	if cmd.Env["PYTHONPATH"] != "/opt/engine/src" {
+		t.Errorf("PYTHONPATH = %q, want /opt/engine/src", cmd.Env["PYTHONPATH"])
+	}
+	if _, ok := cmd.Env["PDB_CACHE"]; ok {
+		t.Error("extra variable was recorded in command.json")
+	}
+}
+
+func TestSetPythonEnvRejectsInvalid(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{}, nil)
+	for _, kv := range []string{"NOVALUE", "=value", "1ABC=x", "BAD-KEY=x"} {
+		if err := s.SetPythonEnv([]string{kv}); err == nil {
+			t.Errorf("%q was accepted", kv)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import "strings"
+
+// pythonErrorLine は Python の標準エラー出力から失敗理由の1行を取り出す
+// トレースバックがあれば末尾の例外行（"ValueError: ..." など）、なければ最後の空でない行を返す
+func pythonErrorLine(stderr string) string {
+	lines := strings.Split(strings.ReplaceAll(stderr, "\r\n", "\n"), "\n")
+
+	start := 0
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "Traceback (most recent call last):") {
+			start = i + 1
+			break
+		}
+	}
+
+	// トレースバックのフレームはインデントされているので、インデントのない行が例外行
+	traceback := start > 0
+	for i := len(lines) - 1; i >= start; i-- {
+		line := strings.TrimRight(lines[i], " \t")
+		if line == "" {
+			continue
+		}
+		if traceback && (line[0] == ' ' || line[0] == '\t') {
+			continue
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package services
+
+import "testing"
+
+func TestPythonErrorLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   string
+	}{
+		{
+			name: "traceback",
+			stderr: "INFO: fetching P12345\n" +
+				"Traceback (most recent call last):\n" +
+				"  File \"cli.py\", line 10, in main\n" +
+				"    run()\n" +
+				"ValueError: no structures found for P12345\n",
+			want: "ValueError: no structures found for P12345",
+		},
+		{
+			name: "chained exceptions use the last traceback",
+			stderr: "Traceback (most recent call last):\n  File \"a.py\", line 1\nKeyError: 'x'\n\n" +
+				"During handling of the above exception, another exception occurred:\n\n" +
+				"Traceback (most recent call last):\n  File \"b.py\", line 2\nflex_analyzer.errors.PipelineError: bad input\n",
+			want: "flex_analyzer.errors.PipelineError: bad input",
+		},
+		{
+			name:   "no traceback",
+			stderr: "usage: cli.py [-h]\ncli.py: error: unrecognized arguments: --bogus\n",
+			want:   "cli.py: error: unrecognized arguments: --bogus",
+		},
+		{name: "empty", stderr: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pythonErrorLine(tt.stderr); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// Python の import 確認は重いので、結果をこの間隔でキャッシュ・更新する
+const (
+	pythonCheckInterval = time.Minute
+	pythonCheckTimeout  = 30 * time.Second
+)
+
+// StartReadinessChecks は ctx が終わるまで定期的に flex_analyzer の import を確認する
+func (s *JobService) StartReadinessChecks(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(pythonCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.refreshPythonCheck(ctx)
+			}
+		}
+	}()
+}
+
+// Readiness はストレージへの書き込みと Python エンジンの import 可否を確認する
+// Python の確認結果はキャッシュを使い、未確認の場合のみその場で実行する
+func (s *JobService) Readiness(ctx context.Context) *models.Readiness {
+	checks := []models.ReadinessCheck{
+		readinessCheck("storage", s.checkStorageWritable()),
+		readinessCheck("python", s.pythonCheck(ctx)),
+	}
+
+	ready := true
+	for _, check := range checks {
+		ready = ready && check.OK
+	}
+	return &models.Readiness{Ready: ready, Checks: checks}
+}
+
+func readinessCheck(name string, err error) models.ReadinessCheck {
+	check := models.ReadinessCheck{Name: name, OK: err == nil}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// checkStorageWritable はストレージディレクトリに一時ファイルを書いて消せるか確認する
+func (s *JobService) checkStorageWritable() error {
+	f, err := os.CreateTemp(s.storageDir, ".ready-*")
+	if err != nil {
+		return fmt.Errorf("storage is not writable: %w", err)
+	}
+	_, writeErr := f.WriteString("ok")
+	closeErr := f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		return fmt.Errorf("failed to remove probe file: %w", err)
+	}
+	if writeErr != nil {
+		return fmt.Errorf("storage is not writable: %w", writeErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("storage is not writable: %w", closeErr)
+	}
+	return nil
+}
+
+// pythonCheck はキャッシュされた import 確認の結果を返す（未確認なら確認する）
+func (s *JobService) pythonCheck(ctx context.Context) error {
+	s.readyMu.Lock()
+	checked := !s.pythonCheckedAt.IsZero()
+	err := s.pythonErr
+	s.readyMu.Unlock()
+	if checked {
+		return err
+	}
+	return s.refreshPythonCheck(ctx)
+}
+
+// refreshPythonCheck は flex_analyzer の import を確認して結果をキャッシュする
+func (s *JobService) refreshPythonCheck(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, pythonCheckTimeout)
+	defer cancel()
+
+	bin := s.PythonBin()
+	stderr, err := s.importEngine(ctx, bin)
+	if err != nil {
+		s.logger.Warn("refreshPythonCheck: flex_analyzer import failed", "python", bin, "error", err, "stderr", strings.TrimSpace(string(stderr)))
+		err = fmt.Errorf("%s cannot import flex_analyzer: %w", bin, err)
+	}
+	s.recordPythonCheck(err)
+	return err
+}
+
+func (s *JobService) recordPythonCheck(err error) {
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+	s.pythonErr = err
+	s.pythonCheckedAt = time.Now()
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadiness(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", importRunner{working: "python3"}, nil)
+	if got := s.Readiness(context.Background()); !got.Ready {
+		t.Fatalf("expected ready, got %+v", got.Checks)
+	}
+
+	// ストレージが存在せず、Python も import できない
+	s = NewJobService(filepath.Join(t.TempDir(), "missing"), "python3", "", importRunner{}, nil)
+	got := s.Readiness(context.Background())
+	if got.Ready {
+		t.Fatal("expected not ready")
+	}
+	for _, check := range got.Checks {
+		if check.OK || check.Error == "" {
+			t.Errorf("check %s: got ok=%v error=%q, want a failure", check.Name, check.OK, check.Error)
+		}
+	}
+}
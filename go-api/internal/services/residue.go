@@ -0,0 +1,88 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// threeToOne はアミノ酸3文字コードから1文字コードへの変換テーブル
+var threeToOne = map[string]string{
+	"ALA": "A", "ARG": "R", "ASN": "N", "ASP": "D", "CYS": "C",
+	"GLN": "Q", "GLU": "E", "GLY": "G", "HIS": "H", "ILE": "I",
+	"LEU": "L", "LYS": "K", "MET": "M", "PHE": "F", "PRO": "P",
+	"SER": "S", "THR": "T", "TRP": "W", "TYR": "Y", "VAL": "V",
+	"SEC": "U", "PYL": "O",
+	// 曖昧コード
+	"ASX": "B", "GLX": "Z", "UNK": "X",
+	// よく見られる修飾残基（親残基にマップ）
+	"MSE": "M", // セレノメチオニン
+	"SEP": "S", // ホスホセリン
+	"TPO": "T", // ホスホスレオニン
+	"PTR": "Y", // ホスホチロシン
+	"HYP": "P", // ヒドロキシプロリン
+	"MLY": "K", // ジメチルリシン
+	"KCX": "K", // カルバミル化リシン
+	"CSO": "C", // S-ヒドロキシシステイン
+	"CME": "C", // S,S-(2-ヒドロキシエチル)チオシステイン
+	// CHARMM のヒスチジンプロトン化状態
+	"HSD": "H", "HSE": "H", "HSP": "H",
+}
+
+// toOneLetter は3文字コードを1文字コードに変換（未知のコードは "X"）
+func toOneLetter(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) == 1 {
+		return code
+	}
+	if one, ok := threeToOne[code]; ok {
+		return one
+	}
+	return "X"
+}
+
+// readJobTrimSequence は Storage 上の trimsequence CSV を開いて readTrimSequence で読む
+func (s *JobService) readJobTrimSequence(jobID, name string) ([]string, error) {
+	file, err := s.storage.Open(jobID, name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readTrimSequence(file)
+}
+
+// readTrimSequence はtrimsequence CSVの先頭列（UniProt配列、3文字コード）を読み込む
+// 1行目はヘッダーなので読み飛ばす
+func readTrimSequence(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var sequence []string
+	for i, row := range records {
+		if i == 0 {
+			continue
+		}
+		if len(row) == 0 {
+			sequence = append(sequence, "")
+			continue
+		}
+		sequence = append(sequence, strings.TrimSpace(row[0]))
+	}
+	return sequence, nil
+}
+
+// residueLabel は1-basedの残基番号に対応するラベル（例: "A-12"）を返す
+// 配列上の位置が得られない場合はプレースホルダー "RES-12" を返す
+func residueLabel(sequence []string, residueNum int) string {
+	if residueNum >= 1 && residueNum <= len(sequence) {
+		if code := sequence[residueNum-1]; code != "" && code != "NA" {
+			return fmt.Sprintf("%s-%d", toOneLetter(code), residueNum)
+		}
+	}
+	return fmt.Sprintf("RES-%d", residueNum)
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"math"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// GetPerResidueScores は残基ごとのスコアと、カラースケール用の最小値・最大値を返す
+func (s *JobService) GetPerResidueScores(jobID string) (*models.PerResidueScores, error) {
+	result, err := s.GetResult(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := &models.PerResidueScores{
+		JobID:            jobID,
+		PerResidueScores: result.PerResidueScores,
+	}
+	if scores.PerResidueScores == nil {
+		scores.PerResidueScores = []models.PerResidueScore{}
+	}
+
+	first := true
+	for _, rs := range result.PerResidueScores {
+		if math.IsNaN(rs.Score) || math.IsInf(rs.Score, 0) {
+			continue
+		}
+		if first || rs.Score < scores.ScoreMin {
+			scores.ScoreMin = rs.Score
+		}
+		if first || rs.Score > scores.ScoreMax {
+			scores.ScoreMax = rs.Score
+		}
+		first = false
+	}
+
+	return scores, nil
+}
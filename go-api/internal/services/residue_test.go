@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestToOneLetter(t *testing.T) {
+	cases := map[string]string{
+		"ALA": "A", "arg": "R", " GLY ": "G", "TRP": "W", "VAL": "V",
+		"SEC": "U", "PYL": "O",
+		"MSE": "M", "SEP": "S", "HYP": "P",
+		"UNK": "X", "ZZZ": "X", "": "X",
+		"A": "A",
+	}
+	for in, want := range cases {
+		if got := toOneLetter(in); got != want {
+			t.Errorf("toOneLetter(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestConvertSummaryCSVUsesOneLetterResidueNames(t *testing.T) {
+	jobDir := filepath.Join(t.TempDir(), "job")
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	summaryPath := filepath.Join(jobDir, "summary.csv")
+
+	writeFile(t, summaryPath, "uniprotid,seq_ratio,Entries,Length\nP12345,0.2,3,3\n")
+	writeFile(t, filepath.Join(jobDir, "trimsequence_P12345.csv"), "P12345,1A00 A\nMSE,MSE\nGLY,GLY\nXYZ,XYZ\n")
+	writeFile(t, filepath.Join(jobDir, "distance_P12345.csv"), "1,2,3.8,3.9\n")
+
+	s := NewJobService(filepath.Dir(jobDir), "", "", nil, nil)
+	result, err := s.convertSummaryCSVToResult("job")
+	if err != nil {
+		t.Fatalf("convertSummaryCSVToResult: %v", err)
+	}
+
+	want := []string{"M", "G", "X"}
+	if len(result.PerResidueScores) != len(want) {
+		t.Fatalf("got %d residues, want %d", len(result.PerResidueScores), len(want))
+	}
+	for i, rs := range result.PerResidueScores {
+		if rs.ResidueName != want[i] || rs.ResidueNumber != i+1 {
+			t.Errorf("residue %d: got %q (#%d), want %q (#%d)", i, rs.ResidueName, rs.ResidueNumber, want[i], i+1)
+		}
+	}
+}
+
+func TestConvertSummaryCSVAppliesReferenceOffset(t *testing.T) {
+	tests := []struct {
+		offset    int
+		wantFirst int
+	}{
+		{2, 3}, // 3 残基・カバー率 50% なので全長 6、位置 3-5
+		{4, 1}, // 4+3 は全長 6 を超えるので無視する
+	}
+	for _, tt := range tests {
+		offset := tt.offset
+		s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+		job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345", ReferenceOffset: &offset})
+		if err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+		waitForStatus(t, s, job.JobID)
+
+		result, err := s.GetResult(job.JobID)
+		if err != nil {
+			t.Fatalf("GetResult: %v", err)
+		}
+		if len(result.PerResidueScores) != 3 || result.FullSequenceLength != 6 {
+			t.Fatalf("offset %d: got %d residues, full length %d", offset, len(result.PerResidueScores), result.FullSequenceLength)
+		}
+		for i, rs := range result.PerResidueScores {
+			if rs.Index != i || rs.ResidueNumber != tt.wantFirst+i {
+				t.Errorf("offset %d: residue %d has index %d, number %d, want number %d", offset, i, rs.Index, rs.ResidueNumber, tt.wantFirst+i)
+			}
+		}
+	}
+}
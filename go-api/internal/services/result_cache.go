@@ -0,0 +1,113 @@
+package services
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// defaultResultCacheSize はキャッシュする結果の件数のデフォルト
+const defaultResultCacheSize = 32
+
+// resultCache は job_id ごとの解析済み結果を保持する LRU キャッシュ
+// status.json の UpdatedAt が変わったエントリは無効として扱う
+type resultCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List               // 先頭が最近使ったもの
+	entries  map[string]*list.Element // job_id → order の要素
+}
+
+type resultCacheEntry struct {
+	jobID     string
+	updatedAt time.Time
+	result    *models.NotebookDSAResult
+}
+
+func newResultCache(capacity int) *resultCache {
+	return &resultCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get は updatedAt が一致するエントリの結果（コピー）を返す
+func (c *resultCache) get(jobID string, updatedAt time.Time) (*models.NotebookDSAResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[jobID]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*resultCacheEntry)
+	if !entry.updatedAt.Equal(updatedAt) {
+		c.order.Remove(elem)
+		delete(c.entries, jobID)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	copied := *entry.result
+	return &copied, true
+}
+
+// put は結果を保存し、容量を超えたら最も古く使われたものを捨てる
+func (c *resultCache) put(jobID string, updatedAt time.Time, result *models.NotebookDSAResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity <= 0 {
+		return
+	}
+
+	if elem, ok := c.entries[jobID]; ok {
+		elem.Value = &resultCacheEntry{jobID: jobID, updatedAt: updatedAt, result: result}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[jobID] = c.order.PushFront(&resultCacheEntry{jobID: jobID, updatedAt: updatedAt, result: result})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resultCacheEntry).jobID)
+	}
+}
+
+// remove はエントリを削除
+func (c *resultCache) remove(jobID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[jobID]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, jobID)
+	}
+}
+
+// resize は容量を変更し、超過分を古い順に捨てる
+func (c *resultCache) resize(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capacity = capacity
+	for c.order.Len() > 0 && c.order.Len() > capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resultCacheEntry).jobID)
+	}
+}
+
+// SetResultCacheSize は解析済み結果をキャッシュする件数を設定（0 で無効）
+func (s *JobService) SetResultCacheSize(n int) error {
+	if n < 0 {
+		return fmt.Errorf("result cache size must be >= 0: %d", n)
+	}
+	s.resultCache.resize(n)
+	return nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestResultCacheEvictsAndInvalidates(t *testing.T) {
+	c := newResultCache(2)
+	t0 := time.Now()
+
+	c.put("a", t0, &models.NotebookDSAResult{UniProtID: "A"})
+	c.put("b", t0, &models.NotebookDSAResult{UniProtID: "B"})
+	if _, ok := c.get("a", t0); !ok {
+		t.Fatal("a should be cached")
+	}
+
+	// b が最も古く使われたので追い出される
+	c.put("c", t0, &models.NotebookDSAResult{UniProtID: "C"})
+	if _, ok := c.get("b", t0); ok {
+		t.Error("b should have been evicted")
+	}
+	if r, ok := c.get("c", t0); !ok || r.UniProtID != "C" {
+		t.Errorf("c: got %v, %v", r, ok)
+	}
+
+	// UpdatedAt が変わったら無効
+	if _, ok := c.get("a", t0.Add(time.Second)); ok {
+		t.Error("a should be invalidated by a newer UpdatedAt")
+	}
+	if _, ok := c.get("a", t0); ok {
+		t.Error("invalidated entry should be dropped")
+	}
+}
+
+func TestGetResultReturnsIndependentCopies(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	first, err := s.GetResult(job.JobID)
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+	first.RawSummary = map[string]string{"mutated": "yes"}
+
+	second, err := s.GetResult(job.JobID)
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+	if second.RawSummary != nil {
+		t.Error("mutating a returned result leaked into the cache")
+	}
+	if second.UniProtID != first.UniProtID {
+		t.Errorf("cached result differs: %q vs %q", second.UniProtID, first.UniProtID)
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// ErrUnsupportedSchemaVersion は ?schema= に知らない・このサーバーより新しいバージョンを指定された場合のエラー
+var ErrUnsupportedSchemaVersion = errors.New("unsupported result schema version")
+
+// minResultSchemaVersion は ?schema= で返せる一番古いバージョン（0 は版の付いていない形式なので指定できない）
+const minResultSchemaVersion = 1
+
+// resultDowngrades はバージョン N の結果を N-1 の形にする処理（キーは N）
+// ResultSchemaVersion を上げたら、ここにも 1 つ前の形に戻す処理を足す
+var resultDowngrades = map[int]func(fields map[string]json.RawMessage){
+	// v2 で excluded_structures を追加した
+	2: func(fields map[string]json.RawMessage) {
+		delete(fields, "excluded_structures")
+	},
+}
+
+// ParseResultSchemaVersion は ?schema= の値（"v1" または "1"）をバージョン番号にする
+func ParseResultSchemaVersion(value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(value, "v"))
+	if err != nil || n < minResultSchemaVersion || n > models.ResultSchemaVersion {
+		return 0, fmt.Errorf("%w: %q (supported: v%d to v%d)", ErrUnsupportedSchemaVersion, value, minResultSchemaVersion, models.ResultSchemaVersion)
+	}
+	return n, nil
+}
+
+// DowngradeResult は現在の形の結果を version の形の JSON オブジェクトにする
+// 新しいバージョンから順に resultDowngrades を当て、schema_version を version にする
+func DowngradeResult(result *models.NotebookDSAResult, version int) (map[string]json.RawMessage, error) {
+	if version < minResultSchemaVersion || version > models.ResultSchemaVersion {
+		return nil, fmt.Errorf("%w: v%d", ErrUnsupportedSchemaVersion, version)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	for v := models.ResultSchemaVersion; v > version; v-- {
+		if downgrade, ok := resultDowngrades[v]; ok {
+			downgrade(fields)
+		}
+	}
+	fields["schema_version"] = json.RawMessage(strconv.Itoa(version))
+	return fields, nil
+}
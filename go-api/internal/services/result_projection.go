@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// defaultResultTopPairs は GET /result で返すペア数の既定上限
+// 900 残基で約 40 万ペアになるが、大半は揺らぎの小さいペアなので上位だけで十分
+const defaultResultTopPairs = 50000
+
+// ResultProjection は結果を返すときの絞り込み（ディスク上の結果はそのまま）
+type ResultProjection struct {
+	TopPairs int  // > 0 なら PairScores を Score 上位 TopPairs 件に絞る（0 は全件）
+	Partial  bool // true なら完了せずに終わったジョブでも途中の CSV から組み立てる
+}
+
+// SetResultTopPairs は GET /result で返すペア数の既定上限を設定（0 で全件）
+func (s *JobService) SetResultTopPairs(n int) error {
+	if n < 0 {
+		return fmt.Errorf("result top pairs must be >= 0: %d", n)
+	}
+	s.resultTopPairs = n
+	return nil
+}
+
+// ResultTopPairs は GET /result で返すペア数の既定上限を返す
+func (s *JobService) ResultTopPairs() int {
+	return s.resultTopPairs
+}
+
+// GetProjectedResult は proj に従って絞り込んだ結果を返す
+// スカラー値と per-residue スコアはそのままで、PairScores のみ Score 上位に絞る
+// 絞り込んだ場合は PairScoresTotal に元の件数を入れる
+func (s *JobService) GetProjectedResult(jobID string, proj ResultProjection) (*models.NotebookDSAResult, error) {
+	result, err := s.GetResult(jobID)
+	var notCompleted *JobNotCompletedError
+	if proj.Partial && errors.As(err, &notCompleted) && IsTerminalStatus(notCompleted.Status) {
+		result, err = s.getPartialResult(jobID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if proj.TopPairs > 0 && len(result.PairScores) > proj.TopPairs {
+		total := len(result.PairScores)
+		result.PairScores = topPairScores(result.PairScores, proj.TopPairs)
+		result.PairScoresTotal = &total
+	}
+	return result, nil
+}
+
+// topPairScores は Score 降順の上位 k 件を新しいスライスで返す（NaN は最後）
+func topPairScores(scores []models.PairScore, k int) []models.PairScore {
+	sorted := append([]models.PairScore(nil), scores...)
+	sortPairScores(sorted)
+	return sorted[:k:k]
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestTopPairScores(t *testing.T) {
+	scores := []models.PairScore{
+		{I: 1, J: 2, Score: 1},
+		{I: 1, J: 3, Score: math.NaN()},
+		{I: 2, J: 3, Score: 5},
+		{I: 2, J: 4, Score: 3},
+	}
+
+	top := topPairScores(scores, 3)
+	want := []float64{5, 3, 1}
+	if len(top) != len(want) {
+		t.Fatalf("got %d pairs, want %d", len(top), len(want))
+	}
+	for i, ps := range top {
+		if ps.Score != want[i] {
+			t.Errorf("top[%d].Score = %v, want %v", i, ps.Score, want[i])
+		}
+	}
+	if scores[0].Score != 1 || !math.IsNaN(scores[1].Score) {
+		t.Error("input slice was reordered")
+	}
+}
+
+func TestGetProjectedResultKeepsMetadata(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	full, err := s.GetProjectedResult(job.JobID, ResultProjection{})
+	if err != nil {
+		t.Fatalf("GetProjectedResult: %v", err)
+	}
+	if len(full.PairScores) != 3 || full.PairScoresTotal != nil {
+		t.Fatalf("unprojected result has %d pairs (total %v), want 3 and no total", len(full.PairScores), full.PairScoresTotal)
+	}
+
+	top, err := s.GetProjectedResult(job.JobID, ResultProjection{TopPairs: 1})
+	if err != nil {
+		t.Fatalf("GetProjectedResult: %v", err)
+	}
+	if len(top.PairScores) != 1 || top.PairScoresTotal == nil || *top.PairScoresTotal != 3 {
+		t.Errorf("got %d pairs (total %v), want 1 of 3", len(top.PairScores), top.PairScoresTotal)
+	}
+	if top.UMF != full.UMF || len(top.PerResidueScores) != len(full.PerResidueScores) {
+		t.Error("projection changed scalar metadata or per-residue scores")
+	}
+
+	// キャッシュ上の結果は絞り込まれていない
+	again, err := s.GetResult(job.JobID)
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+	if len(again.PairScores) != 3 {
+		t.Errorf("cached result was trimmed to %d pairs", len(again.PairScores))
+	}
+}
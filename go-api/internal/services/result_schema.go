@@ -0,0 +1,158 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// saveResultJSON は CSV から組み立てた結果を現在の形式（schema_version 付き）で result.json に保存
+func (s *JobService) saveResultJSON(jobID string) error {
+	result, err := s.convertSummaryCSVToResult(jobID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	if err := writeBytes(s.storage, jobID, "result.json", data); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	return nil
+}
+
+// migrateResult は古い形式（schema_version が無い・小さい）の result.json を現在の形式に揃える
+// ファイルは書き換えず、読み込んだ結果だけを補う（結果はキャッシュされるので移行は読み込みごとに1回）
+func (s *JobService) migrateResult(jobID string, result *models.NotebookDSAResult) {
+	switch {
+	case result.SchemaVersion == models.ResultSchemaVersion:
+		return
+	case result.SchemaVersion > models.ResultSchemaVersion:
+		s.logger.Warn("migrateResult: result.json is newer than this server", "job_id", jobID, "schema_version", result.SchemaVersion)
+		return
+	}
+
+	from := result.SchemaVersion
+	if result.SchemaVersion < 1 {
+		s.migrateResultV0(jobID, result)
+	}
+	if result.SchemaVersion < 2 {
+		s.migrateResultV1(jobID, result)
+	}
+	result.SchemaVersion = models.ResultSchemaVersion
+	s.logger.Debug("migrateResult: migrated result", "job_id", jobID, "from", from, "to", result.SchemaVersion)
+}
+
+// migrateResultV0 は schema_version 導入前の result.json で欠けていることがある値を補う
+// CSV が残っていればそこから組み立てた値を使い、無ければ result 自身から計算できるものだけ計算する
+func (s *JobService) migrateResultV0(jobID string, result *models.NotebookDSAResult) {
+	if fresh, err := s.convertSummaryCSVToResult(jobID); err == nil {
+		fillMissingResultFields(result, fresh)
+	} else {
+		s.logger.Debug("migrateResult: CSVs not available, deriving from result.json only", "job_id", jobID, "error", err)
+	}
+
+	if result.PairScoreMean == 0 && result.PairScoreStd == 0 {
+		result.PairScoreMean, result.PairScoreStd = pairScoreStats(result.PairScores)
+	}
+	if result.NumResidues == 0 {
+		result.NumResidues = len(result.PerResidueScores)
+	}
+	if result.PDBIDs == nil {
+		result.PDBIDs = []string{}
+	}
+	if result.ExcludedPDBs == nil {
+		result.ExcludedPDBs = []string{}
+	}
+	if result.Method == "" || result.CisInfo.Threshold == 0 {
+		if params, err := s.GetJobParams(jobID); err == nil {
+			if result.Method == "" && params.Method != nil {
+				result.Method = *params.Method
+			}
+			if result.CisInfo.Threshold == 0 && params.CisThreshold != nil {
+				result.CisInfo.Threshold = *params.CisThreshold
+			}
+		}
+	}
+}
+
+// migrateResultV1 は excluded_structures 導入前の result.json に除外理由を補う
+// structures CSV が残っていればそこから、無ければ excluded_pdbs から理由無しで組み立てる
+func (s *JobService) migrateResultV1(jobID string, result *models.NotebookDSAResult) {
+	if result.ExcludedStructures != nil {
+		return
+	}
+	if structures, err := s.readJobStructures(jobID, result.UniProtID); err == nil {
+		result.ExcludedStructures = excludedFromStructures(structures)
+		return
+	}
+	result.ExcludedStructures = make([]models.ExcludedStructure, 0, len(result.ExcludedPDBs))
+	for _, pdbID := range result.ExcludedPDBs {
+		result.ExcludedStructures = append(result.ExcludedStructures, models.ExcludedStructure{PDBID: pdbID})
+	}
+}
+
+// fillMissingResultFields は result のゼロ値のフィールドを fresh の値で埋める（既にある値は変えない）
+func fillMissingResultFields(result, fresh *models.NotebookDSAResult) {
+	if result.FullSequenceLength == 0 {
+		result.FullSequenceLength = fresh.FullSequenceLength
+	}
+	if result.ResidueCoveragePercent == 0 {
+		result.ResidueCoveragePercent = fresh.ResidueCoveragePercent
+	}
+	if result.NumChains == 0 {
+		result.NumChains = fresh.NumChains
+	}
+	if result.Top5ResolutionMean == nil {
+		result.Top5ResolutionMean = fresh.Top5ResolutionMean
+	}
+	if len(result.PDBIDs) == 0 {
+		result.PDBIDs = fresh.PDBIDs
+	}
+	if result.Method == "" {
+		result.Method = fresh.Method
+	}
+	if len(result.PairScores) == 0 {
+		result.PairScores = fresh.PairScores
+	}
+	if len(result.PerResidueScores) == 0 {
+		result.PerResidueScores = fresh.PerResidueScores
+	}
+	if result.Heatmap == nil {
+		result.Heatmap = fresh.Heatmap
+	}
+	if result.CisInfo.Threshold == 0 {
+		result.CisInfo.Threshold = fresh.CisInfo.Threshold
+	}
+	if result.CisInfo.CisPairs == nil {
+		result.CisInfo.CisPairs = fresh.CisInfo.CisPairs
+	}
+}
+
+// pairScoreStats は有限の Score の平均と母標準偏差を返す（無ければ 0, 0）
+func pairScoreStats(pairs []models.PairScore) (float64, float64) {
+	var sum float64
+	n := 0
+	for _, ps := range pairs {
+		if !math.IsNaN(ps.Score) && !math.IsInf(ps.Score, 0) {
+			sum += ps.Score
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	mean := sum / float64(n)
+
+	var variance float64
+	for _, ps := range pairs {
+		if !math.IsNaN(ps.Score) && !math.IsInf(ps.Score, 0) {
+			variance += (ps.Score - mean) * (ps.Score - mean)
+		}
+	}
+	return mean, math.Sqrt(variance / float64(n))
+}
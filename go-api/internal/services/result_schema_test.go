@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestCompletedJobSavesVersionedResult(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	if status := waitForStatus(t, s, job.JobID); status.Status != "completed" {
+		t.Fatalf("got status %q, want completed", status.Status)
+	}
+
+	data, err := s.storage.ReadFile(job.JobID, "result.json")
+	if err != nil {
+		t.Fatalf("result.json was not saved: %v", err)
+	}
+	var saved models.NotebookDSAResult
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("result.json: %v", err)
+	}
+	if saved.SchemaVersion != models.ResultSchemaVersion || saved.UniProtID != "P12345" || len(saved.PairScores) != 3 {
+		t.Errorf("unexpected result.json: version %d, uniprot %q, %d pairs", saved.SchemaVersion, saved.UniProtID, len(saved.PairScores))
+	}
+}
+
+func TestMigrateUnversionedResult(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	jobDir := filepath.Join(s.StorageDir(), "job")
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range summaryFixture() {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(jobDir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(jobDir, name), content)
+	}
+	// 導入前の形式: schema_version も派生フィールドも無い
+	writeFile(t, filepath.Join(jobDir, "result.json"), `{"uniprot_id":"P12345","num_structures":3,"umf":2.5,"pair_scores":[{"i":1,"j":2,"score":1},{"i":1,"j":3,"score":3}]}`)
+
+	result, err := s.loadResult("job")
+	if err != nil {
+		t.Fatalf("loadResult: %v", err)
+	}
+	if result.SchemaVersion != models.ResultSchemaVersion {
+		t.Errorf("schema_version = %d, want %d", result.SchemaVersion, models.ResultSchemaVersion)
+	}
+	// 保存済みの値はそのまま、欠けていた値は CSV から補う
+	if result.UMF != 2.5 || len(result.PairScores) != 2 {
+		t.Errorf("existing values were overwritten: umf %v, %d pairs", result.UMF, len(result.PairScores))
+	}
+	if result.FullSequenceLength != 6 || result.ResidueCoveragePercent != 50 || result.NumChains != 3 {
+		t.Errorf("derived fields not filled: %+v", result)
+	}
+	if len(result.PerResidueScores) != 3 || result.Heatmap == nil || len(result.PDBIDs) != 1 {
+		t.Errorf("per-residue scores, heatmap or PDB IDs not filled: %+v", result)
+	}
+	if result.PairScoreMean != 2 || result.PairScoreStd != 1 {
+		t.Errorf("pair score stats = %v, %v, want 2, 1", result.PairScoreMean, result.PairScoreStd)
+	}
+}
+
+func TestMigrateUnversionedResultWithoutCSVs(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	jobDir := filepath.Join(s.StorageDir(), "job")
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(jobDir, "result.json"), `{"uniprot_id":"P12345","pair_scores":[{"i":1,"j":2,"score":1},{"i":1,"j":3,"score":NaN}],"per_residue_scores":[{"index":0},{"index":1}]}`)
+
+	result, err := s.loadResult("job")
+	if err != nil {
+		t.Fatalf("loadResult: %v", err)
+	}
+	if result.PairScoreMean != 1 || result.PairScoreStd != 0 || result.NumResidues != 2 {
+		t.Errorf("derived values = mean %v, std %v, residues %d", result.PairScoreMean, result.PairScoreStd, result.NumResidues)
+	}
+	if result.PDBIDs == nil || result.ExcludedPDBs == nil || result.ExcludedStructures == nil {
+		t.Error("missing lists should become empty, not null")
+	}
+	if !math.IsNaN(result.PairScores[1].Score) {
+		t.Error("NaN score was changed")
+	}
+}
+
+func TestMigrateResultV1AddsExclusionReasons(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	jobDir := filepath.Join(s.StorageDir(), "job")
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(jobDir, "result.json"), `{"schema_version":1,"uniprot_id":"P12345","excluded_pdbs":["2B00"]}`)
+
+	// structures CSV が無ければ理由無し
+	result, err := s.loadResult("job")
+	if err != nil {
+		t.Fatalf("loadResult: %v", err)
+	}
+	if len(result.ExcludedStructures) != 1 || result.ExcludedStructures[0] != (models.ExcludedStructure{PDBID: "2B00"}) {
+		t.Errorf("without CSV: excluded_structures = %+v", result.ExcludedStructures)
+	}
+
+	writeFile(t, filepath.Join(jobDir, "structures_P12345.csv"), "pdb_id,included,reason\n1A00,True,\n2B00,False,seq_ratio\n")
+	result, err = s.loadResult("job")
+	if err != nil {
+		t.Fatalf("loadResult: %v", err)
+	}
+	if len(result.ExcludedStructures) != 1 || result.ExcludedStructures[0] != (models.ExcludedStructure{PDBID: "2B00", Reason: "seq_ratio"}) {
+		t.Errorf("with CSV: excluded_structures = %+v", result.ExcludedStructures)
+	}
+}
+
+func TestDowngradeResult(t *testing.T) {
+	result := &models.NotebookDSAResult{
+		SchemaVersion:      models.ResultSchemaVersion,
+		UniProtID:          "P12345",
+		ExcludedPDBs:       []string{"2B00"},
+		ExcludedStructures: []models.ExcludedStructure{{PDBID: "2B00", Reason: "chimera"}},
+	}
+
+	v1, err := DowngradeResult(result, 1)
+	if err != nil {
+		t.Fatalf("DowngradeResult: %v", err)
+	}
+	if _, ok := v1["excluded_structures"]; ok {
+		t.Error("v1 should not have excluded_structures")
+	}
+	if string(v1["schema_version"]) != "1" || string(v1["excluded_pdbs"]) != `["2B00"]` {
+		t.Errorf("got schema_version %s, excluded_pdbs %s", v1["schema_version"], v1["excluded_pdbs"])
+	}
+	// 元の結果は変えない
+	if result.SchemaVersion != models.ResultSchemaVersion || len(result.ExcludedStructures) != 1 {
+		t.Errorf("result was modified: %+v", result)
+	}
+
+	current, err := DowngradeResult(result, models.ResultSchemaVersion)
+	if err != nil {
+		t.Fatalf("DowngradeResult: %v", err)
+	}
+	if _, ok := current["excluded_structures"]; !ok {
+		t.Error("current version should keep excluded_structures")
+	}
+}
+
+func TestParseResultSchemaVersion(t *testing.T) {
+	for _, value := range []string{"v1", "1", fmt.Sprintf("v%d", models.ResultSchemaVersion)} {
+		if _, err := ParseResultSchemaVersion(value); err != nil {
+			t.Errorf("%q: %v", value, err)
+		}
+	}
+	for _, value := range []string{"v0", fmt.Sprintf("v%d", models.ResultSchemaVersion+1), "latest", ""} {
+		if _, err := ParseResultSchemaVersion(value); !errors.Is(err, ErrUnsupportedSchemaVersion) {
+			t.Errorf("%q: got %v, want ErrUnsupportedSchemaVersion", value, err)
+		}
+	}
+}
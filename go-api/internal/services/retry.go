@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// ErrJobInProgress はジョブがまだ終了していない（pending/processing）場合のエラー
+var ErrJobInProgress = errors.New("job still in progress")
+
+// RetryJob は終了したジョブの保存済みパラメータ（params.json）で新しいジョブを作成する
+func (s *JobService) RetryJob(ctx context.Context, jobID string) (*models.JobResponse, error) {
+	status, err := s.GetJobStatus(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsTerminalStatus(status.Status) {
+		return nil, fmt.Errorf("%w: %s is %s", ErrJobInProgress, jobID, status.Status)
+	}
+
+	params, err := s.GetJobParams(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.CreateJob(ctx, *params)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("RetryJob: job resubmitted", "request_id", RequestIDFromContext(ctx), "job_id", jobID, "new_job_id", job.JobID)
+	return job, nil
+}
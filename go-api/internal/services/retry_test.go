@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestRetryJobReusesSavedParams(t *testing.T) {
+	runner := &FakeRunner{Err: errors.New("exit status 1")}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+
+	seqRatio := 0.5
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345", SeqRatio: &seqRatio})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	retried, err := s.RetryJob(context.Background(), job.JobID)
+	if err != nil {
+		t.Fatalf("RetryJob: %v", err)
+	}
+	if retried.JobID == job.JobID {
+		t.Fatal("RetryJob should create a new job")
+	}
+	waitForStatus(t, s, retried.JobID)
+
+	calls := runner.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("got %d runner calls, want 2", len(calls))
+	}
+	for _, flag := range []string{"--uniprot-ids", "--seq-ratio"} {
+		if a, b := argValue(calls[0], flag), argValue(calls[1], flag); a != b {
+			t.Errorf("%s: original %q, retry %q", flag, a, b)
+		}
+	}
+}
+
+func TestRetryJobRejectsRunningJob(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Block: true}, nil)
+	job, _, err := s.prepareJob(models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("prepareJob: %v", err)
+	}
+
+	if _, err := s.RetryJob(context.Background(), job.JobID); !errors.Is(err, ErrJobInProgress) {
+		t.Fatalf("RetryJob on a pending job: got %v, want ErrJobInProgress", err)
+	}
+}
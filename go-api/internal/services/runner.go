@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"sync"
+)
+
+// Runner は解析コマンドを実行する（テストでは Python を起動しない実装に差し替える）
+type Runner interface {
+	// Run は args[0] を作業ディレクトリ dir・環境変数 env で実行し、標準出力と標準エラー出力を別々に返す
+	// onLine が nil でなければ、どちらかの出力を1行読むたびに呼ばれる
+	Run(ctx context.Context, args []string, dir string, env []string, onLine func(line string)) (stdout, stderr []byte, err error)
+}
+
+// ExecRunner は os/exec で実際にプロセスを起動する Runner
+type ExecRunner struct{}
+
+// Run はプロセスグループ単位で起動し、ctx のキャンセル時はグループごと kill する
+func (ExecRunner) Run(ctx context.Context, args []string, dir string, env []string, onLine func(line string)) ([]byte, []byte, error) {
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("no command specified")
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	setProcessGroup(cmd)
+	cmd.Dir = dir
+	cmd.Env = env
+
+	// 別々の Writer には os/exec が別の goroutine から Write するので、onLine の呼び出しは mu で直列化する
+	var mu sync.Mutex
+	stdout := &lineWriter{onLine: onLine, mu: &mu}
+	stderr := &lineWriter{onLine: onLine, mu: &mu}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	stdout.flush()
+	stderr.flush()
+	return stdout.buf.Bytes(), stderr.buf.Bytes(), err
+}
+
+// lineWriter は出力を全て保持しつつ、改行ごとに onLine を呼ぶ
+type lineWriter struct {
+	buf     bytes.Buffer
+	pending []byte
+	onLine  func(line string)
+	mu      *sync.Mutex // onLine を共有する Writer 間の排他
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	if w.onLine == nil {
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		w.onLine(string(bytes.TrimRight(w.pending[:i], "\r")))
+		w.pending = w.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush は改行で終わっていない最後の行を onLine に渡す
+func (w *lineWriter) flush() {
+	if w.onLine != nil && len(w.pending) > 0 {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.onLine(string(w.pending))
+		w.pending = nil
+	}
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Timeout は S3 への1リクエストのタイムアウト
+const s3Timeout = 60 * time.Second
+
+// unsignedPayload は本文のハッシュを署名に含めないことを示す X-Amz-Content-Sha256 の値
+// PUT の本文はストリームで送るので、送る前にハッシュを計算できない（改ざんは TLS で防ぐ）
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config は S3（互換）バケットの接続設定
+type S3Config struct {
+	Bucket   string
+	Prefix   string // バケット内のキーのプレフィックス（例: "flex-api/"）
+	Region   string
+	Endpoint string // 空なら AWS（仮想ホスト形式）、指定時は MinIO などをパス形式で使う
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3Storage は S3 の {prefix}{job_id}/{name} に保存する Storage
+// 認証は AWS Signature Version 4 で行う（依存を増やさないよう SDK は使わず、署名は sign で計算する）
+type S3Storage struct {
+	cfg     S3Config
+	baseURL *url.URL // バケットのルート（末尾の "/" なし）
+	client  *http.Client
+	now     func() time.Time
+}
+
+// NewS3Storage は設定を検証して S3Storage を作成
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is required")
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("S3 region is required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("S3 credentials are required (AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY)")
+	}
+	if cfg.Prefix != "" && !strings.HasSuffix(cfg.Prefix, "/") {
+		cfg.Prefix += "/"
+	}
+
+	base := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)
+	if cfg.Endpoint != "" {
+		base = strings.TrimRight(cfg.Endpoint, "/") + "/" + cfg.Bucket
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %s", cfg.Endpoint)
+	}
+
+	return &S3Storage{
+		cfg:     cfg,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: s3Timeout},
+		now:     time.Now,
+	}, nil
+}
+
+func (s *S3Storage) key(jobID, name string) string {
+	return s.cfg.Prefix + jobID + "/" + name
+}
+
+func (s *S3Storage) ReadFile(jobID, name string) ([]byte, error) {
+	body, err := s.Open(jobID, name)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func (s *S3Storage) Open(jobID, name string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, s.key(jobID, name), nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// WriteFile は PutObject で置き換える（S3 の PUT はオブジェクト単位でアトミック）
+// size を Content-Length にして r をそのまま本文として送る
+func (s *S3Storage) WriteFile(jobID, name string, r io.Reader, size int64) error {
+	resp, err := s.do(http.MethodPut, s.key(jobID, name), nil, r, size)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Storage) Stat(jobID, name string) (FileInfo, error) {
+	resp, err := s.do(http.MethodHead, s.key(jobID, name), nil, nil, 0)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	resp.Body.Close()
+
+	info := FileInfo{Name: name, Size: resp.ContentLength}
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modTime
+	}
+	return info, nil
+}
+
+func (s *S3Storage) List(jobID string) ([]FileInfo, error) {
+	prefix := s.key(jobID, "")
+	objects, _, err := s.listObjects(prefix, "")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []FileInfo{}
+	for _, obj := range objects {
+		files = append(files, FileInfo{
+			Name:    strings.TrimPrefix(obj.Key, prefix),
+			Size:    obj.Size,
+			ModTime: obj.LastModified,
+		})
+	}
+	return files, nil
+}
+
+// Remove は status.json を先に消してから、残りのオブジェクトを1つずつ削除する
+func (s *S3Storage) Remove(jobID string) error {
+	files, err := s.List(jobID)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Name == "status.json" && files[j].Name != "status.json"
+	})
+	for _, f := range files {
+		resp, err := s.do(http.MethodDelete, s.key(jobID, f.Name), nil, nil, 0)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", f.Name, err)
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+func (s *S3Storage) Jobs() ([]string, error) {
+	_, prefixes, err := s.listObjects(s.cfg.Prefix, "/")
+	if err != nil {
+		return nil, err
+	}
+	var jobIDs []string
+	for _, p := range prefixes {
+		jobIDs = append(jobIDs, strings.TrimSuffix(strings.TrimPrefix(p, s.cfg.Prefix), "/"))
+	}
+	return jobIDs, nil
+}
+
+// s3Object は ListObjectsV2 の Contents 要素
+type s3Object struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+}
+
+// listBucketResult は ListObjectsV2 のレスポンス
+type listBucketResult struct {
+	Contents       []s3Object `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// listObjects は ListObjectsV2 をページングしながら prefix 配下を全て列挙する
+func (s *S3Storage) listObjects(prefix, delimiter string) ([]s3Object, []string, error) {
+	var objects []s3Object
+	var prefixes []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(http.MethodGet, "", query, nil, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse S3 list response: %w", err)
+		}
+
+		objects = append(objects, page.Contents...)
+		for _, p := range page.CommonPrefixes {
+			prefixes = append(prefixes, p.Prefix)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return objects, prefixes, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// do は署名付きリクエストを送る。404 は fs.ErrNotExist、その他の 2xx 以外はエラーにする
+// body は size バイトの本文（本文の無いリクエストでは nil）
+func (s *S3Storage) do(method, key string, query url.Values, body io.Reader, size int64) (*http.Response, error) {
+	u := *s.baseURL
+	u.Path = s.baseURL.Path + "/" + key
+	u.RawPath = s.baseURL.EscapedPath() + "/" + s3EscapePath(key)
+	if query != nil {
+		u.RawQuery = s3CanonicalQuery(query)
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := sha256Hex(nil)
+	if body != nil {
+		payloadHash = unsignedPayload
+		req.ContentLength = size
+		if size == 0 {
+			// ContentLength が 0 で Body があると chunked で送られ、S3 に 411 で拒否される
+			req.Body = http.NoBody
+		}
+	}
+	s.sign(req, payloadHash)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 %s %s: %w", method, key, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: s3://%s/%s", fs.ErrNotExist, s.cfg.Bucket, key)
+	}
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("S3 %s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// sign はリクエストに AWS Signature Version 4 の Authorization ヘッダーを付ける
+// Host と、リクエストに設定済みの全ヘッダーを署名対象にする
+// payloadHash は本文の SHA-256（16 進）か unsignedPayload
+func (s *S3Storage) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Escape は SigV4 の URI エンコード（RFC 3986 の非予約文字以外を %XX にする）
+func s3Escape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+// s3EscapePath はキーの "/" を残したまま各セグメントをエンコードする
+func s3EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, seg := range segments {
+		segments[i] = s3Escape(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// s3CanonicalQuery はキー順に並べてエンコードしたクエリ文字列を返す
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, s3Escape(k)+"="+s3Escape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// shardPrefixLen はジョブディレクトリを分けるサブディレクトリ名の長さ（ID の先頭 2 文字）
+const shardPrefixLen = 2
+
+// jobPath は root 配下のジョブディレクトリのパス
+// sharded なら root/{ID の先頭 2 文字}/{ID}、そうでなければ root/{ID}
+// ハンドラーの ID 検証をすり抜けた値（"../../etc" など）でも root の外を指さないよう、組み立てた後にもう一度確かめる
+func jobPath(root, jobID string, sharded bool) (string, error) {
+	if jobID == "" || strings.ContainsAny(jobID, `/\`) {
+		return "", fmt.Errorf("%w: invalid job id: %q", ErrJobNotFound, jobID)
+	}
+	path := filepath.Join(root, jobID)
+	if sharded && len(jobID) > shardPrefixLen {
+		path = filepath.Join(root, jobID[:shardPrefixLen], jobID)
+	}
+	if !isUnder(root, path) {
+		return "", fmt.Errorf("%w: invalid job id: %q", ErrJobNotFound, jobID)
+	}
+	return path, nil
+}
+
+// isUnder は path が dir より下（dir そのものは含まない）を指すかを Clean したパスで判定する
+func isUnder(dir, path string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	if err != nil || rel == "." || rel == ".." {
+		return false
+	}
+	return !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// jobDir はジョブの作業ディレクトリ（Python の出力先）
+func (s *JobService) jobDir(jobID string) (string, error) {
+	return jobPath(s.storageDir, jobID, s.sharded)
+}
+
+// SetStorageSharding は作業ディレクトリ（とローカルの保存先）でジョブを ID 先頭 2 文字のサブディレクトリに分けるか設定
+// 有効にすると、直下に置かれた既存のジョブディレクトリを分割先へ移動する（起動時の 1 回限りの移行）
+func (s *JobService) SetStorageSharding(enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if enabled {
+		moved, err := migrateToShards(s.storageDir)
+		if err != nil {
+			return err
+		}
+		if moved > 0 {
+			s.logger.Info("SetStorageSharding: moved job directories into shards", "dir", s.storageDir, "jobs", moved)
+		}
+	}
+
+	if s.workDirIsStorage() {
+		s.storage = &LocalStorage{dir: s.storageDir, sharded: enabled}
+	}
+	s.sharded = enabled
+	return nil
+}
+
+// migrateToShards は root 直下のジョブディレクトリを root/{ID の先頭 2 文字}/ へ移動し、移動した数を返す
+// ジョブ ID の形式でない名前（シャード自体や削除途中の *.deleted など）は触らない
+func migrateToShards(root string) (int, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read storage directory: %w", err)
+	}
+
+	moved := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || !IsValidJobID(entry.Name()) {
+			continue
+		}
+		dst, err := jobPath(root, entry.Name(), true)
+		if err != nil {
+			return moved, err
+		}
+		if _, err := os.Stat(dst); err == nil {
+			return moved, fmt.Errorf("cannot move %s into shard: %s already exists", entry.Name(), dst)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return moved, fmt.Errorf("failed to create shard directory: %w", err)
+		}
+		if err := os.Rename(filepath.Join(root, entry.Name()), dst); err != nil {
+			return moved, fmt.Errorf("failed to move %s into shard: %w", entry.Name(), err)
+		}
+		moved++
+	}
+	return moved, nil
+}
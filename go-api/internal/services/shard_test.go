@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestStorageShardingMigratesFlatJobs(t *testing.T) {
+	dir := t.TempDir()
+	const flatID = "0b5f2c9e-1d2a-4c3b-9e8f-7a6b5c4d3e2f"
+	for _, sub := range []string{flatID, "not-a-job"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(dir, flatID, "status.json"), `{"job_id":"`+flatID+`","status":"completed"}`)
+	writeFile(t, filepath.Join(dir, "not-a-job", "keep.txt"), "")
+
+	s := NewJobService(dir, "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	if err := s.SetStorageSharding(true); err != nil {
+		t.Fatalf("SetStorageSharding: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "0b", flatID, "status.json")); err != nil {
+		t.Errorf("flat job was not moved into its shard: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "not-a-job", "keep.txt")); err != nil {
+		t.Errorf("non-job directory was moved: %v", err)
+	}
+	if status, err := s.GetJobStatus(flatID); err != nil || status.Status != "completed" {
+		t.Errorf("migrated job: got %+v, %v", status, err)
+	}
+
+	// 新しいジョブも分割先に作られ、一覧にも出る
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	if status := waitForStatus(t, s, job.JobID); status.Status != "completed" {
+		t.Fatalf("got status %q, want completed", status.Status)
+	}
+	if _, err := os.Stat(filepath.Join(dir, job.JobID[:2], job.JobID, "summary.csv")); err != nil {
+		t.Errorf("job output is not in its shard: %v", err)
+	}
+	jobs, err := s.ListJobs("", 0)
+	if err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Errorf("ListJobs returned %d jobs, want 2", len(jobs))
+	}
+
+	// 2 回目は何も移動しない
+	if err := s.SetStorageSharding(true); err != nil {
+		t.Errorf("second SetStorageSharding: %v", err)
+	}
+}
+
+func TestJobPathStaysUnderRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "storage")
+	for _, sharded := range []bool{false, true} {
+		for _, jobID := range []string{"", ".", "..", "../../etc", "a/../../b", `..\x`} {
+			if path, err := jobPath(root, jobID, sharded); !errors.Is(err, ErrJobNotFound) {
+				t.Errorf("jobPath(%q, sharded=%v) = %q, %v; want ErrJobNotFound", jobID, sharded, path, err)
+			}
+		}
+		if _, err := jobPath(root, "0b5f2c9e-1d2a-4c3b-9e8f-7a6b5c4d3e2f", sharded); err != nil {
+			t.Errorf("valid job id rejected (sharded=%v): %v", sharded, err)
+		}
+	}
+
+	// ファイル名の ".." でもジョブディレクトリの外には出られない
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "secret.txt"), "secret")
+	storage := NewLocalStorage(filepath.Join(dir, "storage"))
+	if _, err := storage.ReadFile("abcdefghijklm", "../../secret.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile outside the job directory: got %v, want fs.ErrNotExist", err)
+	}
+	if err := writeBytes(storage, "abcdefghijklm", "../escape.txt", []byte("x")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("WriteFile outside the job directory: got %v, want fs.ErrNotExist", err)
+	}
+	if _, err := storage.ReadFile("..", "secret.txt"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("ReadFile with job id %q: got %v, want ErrJobNotFound", "..", err)
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// shutdownPollInterval は Shutdown が実行中ジョブの終了を確認する間隔
+	shutdownPollInterval = 100 * time.Millisecond
+	// shutdownKillWait は kill したプロセスの終了を待つ最大時間
+	shutdownKillWait = 5 * time.Second
+)
+
+// Shutdown は新しいジョブの開始を止め、実行中のジョブが終わるのを ctx の期限まで待つ
+// 期限までに終わらなかったジョブは Python プロセスをグループごと kill し、
+// 最後にこのインスタンスが受け付けたジョブのうち終了状態でないものを "interrupted" にする
+// （保存先を共有する別のインスタンスのジョブには触らない）
+// 期限切れで kill した場合は ctx のエラーを返す
+func (s *JobService) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	// kill したジョブは実行を終えると owned から消えるので、先に控えておく
+	owned := make([]string, 0, len(s.owned))
+	for jobID := range s.owned {
+		owned = append(owned, jobID)
+	}
+	s.mu.Unlock()
+
+	waitErr := s.waitIdle(ctx)
+	if waitErr != nil {
+		s.mu.RLock()
+		cancels := make([]context.CancelFunc, 0, len(s.cancels))
+		for _, cancel := range s.cancels {
+			cancels = append(cancels, cancel)
+		}
+		s.mu.RUnlock()
+
+		s.logger.Warn("Shutdown: grace period expired, killing running jobs", "jobs", len(cancels))
+		for _, cancel := range cancels {
+			cancel()
+		}
+
+		killCtx, cancel := context.WithTimeout(context.Background(), shutdownKillWait)
+		defer cancel()
+		if err := s.waitIdle(killCtx); err != nil {
+			s.logger.Error("Shutdown: jobs did not exit after kill", "error", err)
+		}
+	}
+
+	// 実行待ちのまま残ったジョブも含め、UI に正しい終了状態を見せる
+	for _, jobID := range owned {
+		status, err := s.GetJobStatus(jobID)
+		if err != nil || IsTerminalStatus(status.Status) {
+			continue
+		}
+		if err := s.updateJobStatus(jobID, "interrupted", status.Progress, "Server shut down before the job finished"); err != nil {
+			continue
+		}
+		s.logger.Info("Shutdown: job interrupted", "job_id", jobID, "previous_status", status.Status)
+	}
+
+	return waitErr
+}
+
+// isClosing は Shutdown が始まっているかどうかを返す
+func (s *JobService) isClosing() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.closing
+}
+
+// waitIdle は実行中・実行枠待ちのジョブが無くなるまで待つ
+func (s *JobService) waitIdle(ctx context.Context) error {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.mu.RLock()
+		n := len(s.cancels)
+		s.mu.RUnlock()
+		if n == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
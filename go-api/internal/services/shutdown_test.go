@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestShutdownInterruptsJobsAfterGracePeriod(t *testing.T) {
+	runner := &FakeRunner{Block: true}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	if err := s.SetMaxConcurrent(1); err != nil {
+		t.Fatalf("SetMaxConcurrent: %v", err)
+	}
+
+	running, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	queued, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P67890"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+
+	// 1件目が実行中になるまで待つ
+	deadline := time.Now().Add(5 * time.Second)
+	for len(runner.Calls()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown: got %v, want DeadlineExceeded", err)
+	}
+
+	for _, jobID := range []string{running.JobID, queued.JobID} {
+		status, err := s.GetJobStatus(jobID)
+		if err != nil {
+			t.Fatalf("GetJobStatus: %v", err)
+		}
+		if status.Status != "interrupted" {
+			t.Errorf("job %s: got status %q, want interrupted", jobID, status.Status)
+		}
+	}
+	if n := len(runner.Calls()); n != 1 {
+		t.Errorf("queued job should not start during shutdown: %d runner calls", n)
+	}
+}
+
+func TestShutdownLeavesOtherInstancesJobs(t *testing.T) {
+	dir := t.TempDir()
+	s := NewJobService(dir, "python3", "", &FakeRunner{Block: true}, nil)
+
+	// 保存先を共有する別のインスタンスが受け付けたジョブ
+	other := NewJobService(dir, "python3", "", &FakeRunner{Block: true}, nil)
+	job, _, err := other.prepareJob(models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("prepareJob: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	status, err := s.GetJobStatus(job.JobID)
+	if err != nil {
+		t.Fatalf("GetJobStatus: %v", err)
+	}
+	if status.Status != "pending" {
+		t.Errorf("got status %q, want pending", status.Status)
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Storage はジョブ成果物の保存先
+// ファイルは job_id ごとのプレフィックス配下に置き、name はそこからの相対パス（"/" 区切り）
+// 存在しないファイルのエラーは errors.Is(err, fs.ErrNotExist) を満たすこと
+type Storage interface {
+	ReadFile(jobID, name string) ([]byte, error)
+	Open(jobID, name string) (io.ReadCloser, error)
+	// WriteFile は r から size バイトを読んで書き込む。読み手が書きかけの内容を見ないように置き換える
+	// 大きなファイルもメモリに読み込まずに送れるよう、内容はストリームで受け取る
+	WriteFile(jobID, name string, r io.Reader, size int64) error
+	Stat(jobID, name string) (FileInfo, error)
+	// List はジョブ配下の全ファイルを返す（ジョブが無ければ空）
+	List(jobID string) ([]FileInfo, error)
+	// Remove はジョブ配下のファイルを全て削除する
+	// 最初に status.json を読めなくして、削除途中のジョブが一覧やステータス取得に現れないようにする
+	Remove(jobID string) error
+	// Jobs は保存されている job_id の一覧を返す
+	Jobs() ([]string, error)
+}
+
+// FileInfo は Storage 上のファイルの情報
+type FileInfo struct {
+	Name    string // ジョブ配下の相対パス（"/" 区切り）
+	Size    int64
+	ModTime time.Time
+}
+
+// LocalStorage はローカルディスクの dir/{job_id}/ に保存する Storage
+// sharded なら dir/{job_id の先頭 2 文字}/{job_id}/ に保存する
+type LocalStorage struct {
+	dir     string
+	sharded bool
+}
+
+// NewLocalStorage は dir をルートとする LocalStorage を作成
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{dir: dir}
+}
+
+// jobDir はジョブのディレクトリ
+func (l *LocalStorage) jobDir(jobID string) (string, error) {
+	return jobPath(l.dir, jobID, l.sharded)
+}
+
+// path はジョブ配下のファイルのパス（name に ".." が含まれていてもジョブディレクトリの外は指さない）
+func (l *LocalStorage) path(jobID, name string) (string, error) {
+	jobDir, err := l.jobDir(jobID)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(jobDir, filepath.FromSlash(name))
+	if !isUnder(jobDir, path) {
+		return "", fmt.Errorf("%w: invalid file name: %q", fs.ErrNotExist, name)
+	}
+	return path, nil
+}
+
+func (l *LocalStorage) ReadFile(jobID, name string) ([]byte, error) {
+	path, err := l.path(jobID, name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+func (l *LocalStorage) Open(jobID, name string) (io.ReadCloser, error) {
+	path, err := l.path(jobID, name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (l *LocalStorage) WriteFile(jobID, name string, r io.Reader, size int64) error {
+	path, err := l.path(jobID, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, r, size, 0o644)
+}
+
+func (l *LocalStorage) Stat(jobID, name string) (FileInfo, error) {
+	path, err := l.path(jobID, name)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	if info.IsDir() {
+		return FileInfo{}, fmt.Errorf("%w: %s is a directory", fs.ErrNotExist, name)
+	}
+	return FileInfo{Name: name, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (l *LocalStorage) List(jobID string) ([]FileInfo, error) {
+	jobDir, err := l.jobDir(jobID)
+	if err != nil {
+		return nil, err
+	}
+	files := []FileInfo{}
+	err = filepath.WalkDir(jobDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == jobDir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(jobDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, FileInfo{Name: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Remove はジョブディレクトリを別名に rename してから削除する（削除途中の状態を読み手に見せない）
+func (l *LocalStorage) Remove(jobID string) error {
+	jobDir, err := l.jobDir(jobID)
+	if err != nil {
+		return err
+	}
+	trashDir := jobDir + ".deleted"
+	if err := os.Rename(jobDir, trashDir); err != nil {
+		return fmt.Errorf("failed to move job directory: %w", err)
+	}
+	if err := os.RemoveAll(trashDir); err != nil {
+		return fmt.Errorf("failed to remove job directory: %w", err)
+	}
+	return nil
+}
+
+func (l *LocalStorage) Jobs() ([]string, error) {
+	if l.sharded {
+		return l.shardedJobs()
+	}
+	return listDirs(l.dir)
+}
+
+// shardedJobs は各シャード（先頭 2 文字のディレクトリ）配下のジョブをまとめて返す
+func (l *LocalStorage) shardedJobs() ([]string, error) {
+	shards, err := listDirs(l.dir)
+	if err != nil {
+		return nil, err
+	}
+	var jobIDs []string
+	for _, shard := range shards {
+		if len(shard) != shardPrefixLen {
+			continue
+		}
+		ids, err := listDirs(filepath.Join(l.dir, shard))
+		if err != nil {
+			return nil, err
+		}
+		jobIDs = append(jobIDs, ids...)
+	}
+	return jobIDs, nil
+}
+
+// listDirs は dir 直下のディレクトリ名を返す
+func listDirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// writeBytes はメモリ上の内容を Storage に書き込む
+func writeBytes(storage Storage, jobID, name string, data []byte) error {
+	return storage.WriteFile(jobID, name, bytes.NewReader(data), int64(len(data)))
+}
+
+// writeFileAtomic は r の内容を path + ".tmp" に書き込んでから path へ rename する
+// rename は同一ファイルシステム上でアトミックなので、読み手は旧内容か新内容のどちらかだけを見る
+// r から読めたのが size バイトでなければ、途中までの内容で置き換えないようにエラーにする
+func writeFileAtomic(path string, r io.Reader, size int64, perm os.FileMode) error {
+	tmpPath := path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n != size {
+		err = fmt.Errorf("wrote %d bytes to %s, want %d", n, filepath.Base(path), size)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// SetStorage はジョブ成果物の保存先を設定（既定は作業ディレクトリのローカルディスク）
+func (s *JobService) SetStorage(storage Storage) error {
+	if storage == nil {
+		return fmt.Errorf("storage must not be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.storage = storage
+	return nil
+}
+
+// workDirIsStorage は作業ディレクトリがそのまま保存先かどうか
+func (s *JobService) workDirIsStorage() bool {
+	local, ok := s.storage.(*LocalStorage)
+	return ok && filepath.Clean(local.dir) == filepath.Clean(s.storageDir)
+}
+
+// persistWorkDir は Python が作業ディレクトリに書き出したファイルを保存先にアップロードする
+func (s *JobService) persistWorkDir(jobID string) error {
+	if s.workDirIsStorage() {
+		return nil
+	}
+
+	workDir, err := s.jobDir(jobID)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return err
+		}
+		// 構造ファイルは終了時に削除するので、残す設定でなければアップロードしない
+		if !s.KeepPDBFiles() && s.isPDBFile(rel) {
+			return nil
+		}
+		// Python の出力は数百 MB になることがあるので、読み込まずにそのまま送る
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if err := s.storage.WriteFile(jobID, filepath.ToSlash(rel), f, info.Size()); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", rel, err)
+		}
+		return nil
+	})
+}
+
+// removeWorkDir は保存先が別にある場合、ジョブ終了後に作業ディレクトリを削除する
+func (s *JobService) removeWorkDir(jobID string) {
+	if s.workDirIsStorage() {
+		return
+	}
+	workDir, err := s.jobDir(jobID)
+	if err == nil {
+		err = os.RemoveAll(workDir)
+	}
+	if err != nil {
+		s.logger.Warn("removeWorkDir: failed to remove work directory", "job_id", jobID, "error", err)
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// JobStructures.Source の値
+const (
+	StructureSourceEngine  = "engine"
+	StructureSourceDerived = "derived"
+)
+
+// GetJobStructures は完了したジョブで対象になった PDB エントリを返す
+// structures CSV が無い古いジョブは trimsequence の列名と atom_coord から解析に使ったエントリのみ推定する
+func (s *JobService) GetJobStructures(jobID string) (*models.JobStructures, error) {
+	result, err := s.GetResult(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.JobStructures{JobID: jobID, UniProtID: result.UniProtID}
+	structures, err := s.readJobStructures(jobID, result.UniProtID)
+	switch {
+	case err == nil:
+		resp.Source = StructureSourceEngine
+		resp.Structures = structures
+	case errors.Is(err, fs.ErrNotExist):
+		resp.Source = StructureSourceDerived
+		resp.Structures = s.deriveStructures(jobID, result)
+	default:
+		return nil, err
+	}
+	if resp.Structures == nil {
+		resp.Structures = []models.StructureDetail{}
+	}
+	return resp, nil
+}
+
+// readJobStructures は Storage 上の structures CSV を開いて readStructures で読む
+func (s *JobService) readJobStructures(jobID, uniprotID string) ([]models.StructureDetail, error) {
+	name := s.layout.Structures(uniprotID)
+	file, err := s.storage.Open(jobID, name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	structures, err := readStructures(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return structures, nil
+}
+
+// readStructures は structures CSV（1行目はヘッダー）を読む
+// 列は名前で引くので、順序の入れ替えや列の追加があっても読める
+func readStructures(r io.Reader) ([]models.StructureDetail, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("missing header")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["pdb_id"]; !ok {
+		return nil, errors.New("missing pdb_id column")
+	}
+
+	var structures []models.StructureDetail
+	for _, row := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		detail := models.StructureDetail{
+			PDBID:    strings.ToUpper(field("pdb_id")),
+			Method:   field("method"),
+			Mutation: field("mutation"),
+			Reason:   field("reason"),
+		}
+		if detail.PDBID == "" {
+			continue
+		}
+		if v, err := strconv.ParseFloat(field("resolution"), 64); err == nil {
+			detail.Resolution = &v
+		}
+		detail.NumChains, _ = strconv.Atoi(field("num_chains"))
+		detail.ChainsUsed, _ = strconv.Atoi(field("chains_used"))
+		// Python の bool は "True"/"False" で書かれる
+		if included, err := strconv.ParseBool(field("included")); err == nil {
+			detail.Included = included
+		} else {
+			detail.Included = detail.Reason == ""
+		}
+		structures = append(structures, detail)
+	}
+	return structures, nil
+}
+
+// excludedFromStructures は structures CSV のうち解析から除外したエントリを理由付きで返す
+func excludedFromStructures(structures []models.StructureDetail) []models.ExcludedStructure {
+	excluded := []models.ExcludedStructure{}
+	for _, st := range structures {
+		if !st.Included {
+			excluded = append(excluded, models.ExcludedStructure{PDBID: st.PDBID, Reason: st.Reason})
+		}
+	}
+	return excluded
+}
+
+// orderedPDBIDs は pdb_ids を指定したジョブの解析に使ったエントリを指定順に返す
+// used（atom_coord から分かったもの）が空なら、指定のうち excluded に入っていないものを使う
+func orderedPDBIDs(requested, used, excluded []string) []string {
+	contains := func(ids []string, id string) bool {
+		for _, v := range ids {
+			if strings.EqualFold(v, id) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ordered := []string{}
+	for _, id := range requested {
+		if len(used) > 0 && contains(used, id) || len(used) == 0 && !contains(excluded, id) {
+			ordered = append(ordered, id)
+		}
+	}
+	return ordered
+}
+
+// deriveStructures は structures CSV が無いジョブ向けに、解析に使ったエントリだけを返す
+// 除外されたエントリや分解能は分からないので含めない
+func (s *JobService) deriveStructures(jobID string, result *models.NotebookDSAResult) []models.StructureDetail {
+	chains, order, err := s.readJobTrimSequenceChains(jobID, s.layout.TrimSequence(result.UniProtID))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.logger.Warn("deriveStructures: failed to read trimsequence", "job_id", jobID, "error", err)
+	}
+
+	// atom_coord にだけあるエントリ（trimsequence が無い場合など）も拾う
+	for _, pdbID := range result.PDBIDs {
+		if _, ok := chains[pdbID]; !ok {
+			order = append(order, pdbID)
+		}
+	}
+
+	structures := make([]models.StructureDetail, 0, len(order))
+	for _, pdbID := range order {
+		structures = append(structures, models.StructureDetail{
+			PDBID:      pdbID,
+			Method:     result.Method,
+			ChainsUsed: chains[pdbID],
+			Included:   true,
+		})
+	}
+	return structures
+}
+
+// readJobTrimSequenceChains は trimsequence CSV の列名（"PDBID CHAIN"、先頭列は UniProt ID）から
+// PDB エントリごとの使用チェーン数と、エントリの出現順を返す
+func (s *JobService) readJobTrimSequenceChains(jobID, name string) (map[string]int, []string, error) {
+	chains := make(map[string]int)
+	file, err := s.storage.Open(jobID, name)
+	if err != nil {
+		return chains, nil, err
+	}
+	defer file.Close()
+
+	header, err := csv.NewReader(file).Read()
+	if err != nil {
+		return chains, nil, err
+	}
+
+	var order []string
+	for i, col := range header {
+		fields := strings.Fields(col)
+		if i == 0 || len(fields) == 0 {
+			continue
+		}
+		pdbID := strings.ToUpper(fields[0])
+		if chains[pdbID] == 0 {
+			order = append(order, pdbID)
+		}
+		chains[pdbID]++
+	}
+	return chains, order, nil
+}
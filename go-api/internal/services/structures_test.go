@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// runStructuresJob は files を出力する完了済みジョブを作る
+func runStructuresJob(t *testing.T, files map[string]string) (*JobService, string) {
+	t.Helper()
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: files}, nil)
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	if status := waitForStatus(t, s, job.JobID); status.Status != "completed" {
+		t.Fatalf("got status %q, want completed", status.Status)
+	}
+	return s, job.JobID
+}
+
+func TestGetJobStructuresFromEngine(t *testing.T) {
+	files := summaryFixture()
+	files["structures_P12345.csv"] = "pdb_id,method,resolution,num_chains,chains_used,mutation,included,reason\n" +
+		"1A00,X-ray,1.8,2,1,normal,True,\n" +
+		"2B00,X-ray,,1,0,chimera,False,chimera\n" +
+		"3c00,X-ray,2.5,1,0,,False,negative_pdbid\n"
+	s, jobID := runStructuresJob(t, files)
+
+	got, err := s.GetJobStructures(jobID)
+	if err != nil {
+		t.Fatalf("GetJobStructures: %v", err)
+	}
+	if got.Source != StructureSourceEngine || got.UniProtID != "P12345" || len(got.Structures) != 3 {
+		t.Fatalf("unexpected structures: %+v", got)
+	}
+
+	first := got.Structures[0]
+	if first.PDBID != "1A00" || !first.Included || first.Resolution == nil || *first.Resolution != 1.8 ||
+		first.NumChains != 2 || first.ChainsUsed != 1 || first.Mutation != "normal" {
+		t.Errorf("structures[0] = %+v", first)
+	}
+	if second := got.Structures[1]; second.Included || second.Reason != "chimera" || second.Resolution != nil {
+		t.Errorf("structures[1] = %+v", second)
+	}
+	if third := got.Structures[2]; third.PDBID != "3C00" || third.Reason != "negative_pdbid" {
+		t.Errorf("structures[2] = %+v", third)
+	}
+
+	// 除外したエントリは結果の excluded_pdbs にも入る
+	result, err := s.GetResult(jobID)
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+	if strings.Join(result.ExcludedPDBs, ",") != "2B00,3C00" {
+		t.Errorf("excluded_pdbs = %v, want [2B00 3C00]", result.ExcludedPDBs)
+	}
+	want := []models.ExcludedStructure{{PDBID: "2B00", Reason: "chimera"}, {PDBID: "3C00", Reason: "negative_pdbid"}}
+	if len(result.ExcludedStructures) != len(want) || result.ExcludedStructures[0] != want[0] || result.ExcludedStructures[1] != want[1] {
+		t.Errorf("excluded_structures = %+v, want %+v", result.ExcludedStructures, want)
+	}
+}
+
+func TestGetJobStructuresDerived(t *testing.T) {
+	files := summaryFixture()
+	files["trimsequence_P12345.csv"] = "P12345,1A00 A,1A00 B,2B00 A\nALA,ALA,ALA,ALA\n"
+	files["atom_coord/4d00.csv"] = ""
+	s, jobID := runStructuresJob(t, files)
+
+	got, err := s.GetJobStructures(jobID)
+	if err != nil {
+		t.Fatalf("GetJobStructures: %v", err)
+	}
+	if got.Source != StructureSourceDerived {
+		t.Errorf("source = %q, want %q", got.Source, StructureSourceDerived)
+	}
+
+	want := map[string]int{"1A00": 2, "2B00": 1, "4D00": 0}
+	if len(got.Structures) != len(want) {
+		t.Fatalf("got %d structures, want %d: %+v", len(got.Structures), len(want), got.Structures)
+	}
+	for _, st := range got.Structures {
+		chains, ok := want[st.PDBID]
+		if !ok || st.ChainsUsed != chains || !st.Included || st.Method != "X-ray" {
+			t.Errorf("unexpected structure %+v", st)
+		}
+	}
+}
+
+func TestGetJobStructuresNotFound(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{}, nil)
+	if _, err := s.GetJobStructures("missing-job"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("got %v, want ErrJobNotFound", err)
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+var cpuListPattern = regexp.MustCompile(`^[0-9]+(-[0-9]+)?(,[0-9]+(-[0-9]+)?)*$`)
+
+// SetSubprocessPriority はPythonサブプロセスのniceness（-20〜19）とCPUセット（例: "0-3,6"）を設定
+// nice=0 / cpus="" の場合はそれぞれ変更しない
+func (s *JobService) SetSubprocessPriority(nice int, cpus string) error {
+	if nice < -20 || nice > 19 {
+		return fmt.Errorf("nice must be between -20 and 19: %d", nice)
+	}
+	if cpus != "" && !cpuListPattern.MatchString(cpus) {
+		return fmt.Errorf("invalid cpu list: %s", cpus)
+	}
+	s.subprocessNice = nice
+	s.subprocessCPUs = cpus
+	return nil
+}
+
+// wrapCommand は設定に応じて argv を nice / taskset 経由の起動に書き換え、適用した設定を返す
+// nice / taskset が無い環境ではその設定をスキップする
+func (s *JobService) wrapCommand(argv []string) ([]string, models.JobMetadata) {
+	meta := models.JobMetadata{}
+
+	if s.subprocessCPUs != "" {
+		if taskset, err := exec.LookPath("taskset"); err == nil {
+			argv = append([]string{taskset, "-c", s.subprocessCPUs}, argv...)
+			meta.CPUs = s.subprocessCPUs
+		} else {
+			s.logger.Warn("taskset not available, skipping CPU pinning", "error", err)
+		}
+	}
+
+	if s.subprocessNice != 0 {
+		if nice, err := exec.LookPath("nice"); err == nil {
+			argv = append([]string{nice, "-n", strconv.Itoa(s.subprocessNice)}, argv...)
+			n := s.subprocessNice
+			meta.Nice = &n
+		} else {
+			s.logger.Warn("nice not available, skipping niceness", "error", err)
+		}
+	}
+
+	return argv, meta
+}
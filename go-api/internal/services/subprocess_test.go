@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// countingStorage は書き込まれたファイル名ごとの回数を数える Storage
+type countingStorage struct {
+	Storage
+
+	mu     sync.Mutex
+	writes map[string]int
+}
+
+func (c *countingStorage) WriteFile(jobID, name string, r io.Reader, size int64) error {
+	c.mu.Lock()
+	c.writes[name]++
+	c.mu.Unlock()
+	return c.Storage.WriteFile(jobID, name, r, size)
+}
+
+func TestSubprocessNiceWrapsEveryAttempt(t *testing.T) {
+	nice, err := exec.LookPath("nice")
+	if err != nil {
+		t.Skip("nice not available")
+	}
+
+	runner := &FakeRunner{
+		Stderr: "requests.exceptions.ConnectionError: Max retries exceeded with url: /download/1a00.cif\n",
+		Err:    errors.New("exit status 1"),
+	}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	storage := &countingStorage{Storage: NewLocalStorage(t.TempDir()), writes: map[string]int{}}
+	if err := s.SetStorage(storage); err != nil {
+		t.Fatalf("SetStorage: %v", err)
+	}
+	if err := s.SetSubprocessPriority(10, ""); err != nil {
+		t.Fatalf("SetSubprocessPriority: %v", err)
+	}
+	if err := s.SetDownloadRetries(2, time.Millisecond); err != nil {
+		t.Fatalf("SetDownloadRetries: %v", err)
+	}
+
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	if status := waitForStatus(t, s, job.JobID); status.Status != "failed" {
+		t.Fatalf("got status %q, want failed", status.Status)
+	}
+
+	// setpriority を起動後に呼ぶのではなく、exec の時点から nice で動かす
+	calls := runner.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("runner called %d times, want 3", len(calls))
+	}
+	for i, argv := range calls {
+		if len(argv) < 4 || argv[0] != nice || argv[1] != "-n" || argv[2] != "10" || argv[3] != "python3" {
+			t.Errorf("attempt %d: got argv %v, want %s -n 10 python3 ...", i+1, argv, nice)
+		}
+	}
+
+	// 再実行しても metadata.json はジョブにつき1回だけ書く
+	storage.mu.Lock()
+	writes := storage.writes["metadata.json"]
+	storage.mu.Unlock()
+	if writes != 1 {
+		t.Errorf("metadata.json written %d times, want 1", writes)
+	}
+	data, err := storage.ReadFile(job.JobID, "metadata.json")
+	if err != nil {
+		t.Fatalf("ReadFile metadata.json: %v", err)
+	}
+	var meta models.JobMetadata
+	if err := json.Unmarshal(data, &meta); err != nil || meta.Nice == nil || *meta.Nice != 10 {
+		t.Errorf("metadata.json: got %s, %v", data, err)
+	}
+}
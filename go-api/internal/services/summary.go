@@ -0,0 +1,27 @@
+package services
+
+import "github.com/yourusername/flex-api/internal/models"
+
+// GetResultSummary は結果からスカラー値のみを取り出したサマリーを返す
+func (s *JobService) GetResultSummary(jobID string) (*models.ResultSummary, error) {
+	result, err := s.GetResult(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ResultSummary{
+		JobID:                  jobID,
+		UniProtID:              result.UniProtID,
+		NumStructures:          result.NumStructures,
+		NumResidues:            result.NumResidues,
+		SeqRatio:               result.SeqRatio,
+		Method:                 result.Method,
+		UMF:                    result.UMF,
+		PairScoreMean:          result.PairScoreMean,
+		PairScoreStd:           result.PairScoreStd,
+		ResidueCoveragePercent: result.ResidueCoveragePercent,
+		NumChains:              result.NumChains,
+		CisNum:                 result.CisInfo.CisNum,
+		CisMix:                 result.CisInfo.Mix,
+	}, nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SummaryColumns は summary.csv の列名と結果の項目の対応（FileLayout.SummaryColumns）
+// 各項目の先頭が現在のエンジンの列名、以降はエンジンが過去に使った名前などの別名
+// 大文字小文字と前後の空白は区別しないので、エンジンが列名を変えたら別名を 1 つ足せばよい
+type SummaryColumns struct {
+	UniProtID     []string
+	SeqRatio      []string
+	Entries       []string // 解析に使った PDB エントリ数
+	Chains        []string // 解析に使ったチェーン数
+	Length        []string // 解析した残基数
+	LengthPercent []string // 全長に対する Length の割合（%）
+	Resolution    []string // 分解能の上位 5 件の平均
+	UMF           []string
+	MeanCisDist   []string
+	StdCisDist    []string
+	MeanCisScore  []string
+	Cis           []string // cis ペア数
+	Mix           []string // cis と trans が混在するペア数
+}
+
+// defaultSummaryColumns は現在の flex_analyzer notebook コマンドの summary.csv の列名
+var defaultSummaryColumns = SummaryColumns{
+	UniProtID:     []string{"uniprotid", "uniprot_id"},
+	SeqRatio:      []string{"seq_ratio", "seqratio"},
+	Entries:       []string{"Entries", "num_structures"},
+	Chains:        []string{"Chains", "num_chains"},
+	Length:        []string{"Length", "num_residues"},
+	LengthPercent: []string{"Length(%)", "length_percent"},
+	Resolution:    []string{"Resolution", "top5_resolution_mean"},
+	UMF:           []string{"UMF"},
+	MeanCisDist:   []string{"mean_cisDist", "mean_cis_dist"},
+	StdCisDist:    []string{"std_cisDist", "std_cis_dist"},
+	MeanCisScore:  []string{"mean_cisScore", "mean_cis_score"},
+	Cis:           []string{"cis", "cis_num"},
+	Mix:           []string{"mix"},
+}
+
+// summaryRow は summary.csv の 1 行を SummaryColumns の別名で引く
+// 見つからなかった列と数値として読めなかった列を記録し、呼び出し側でまとめて警告する
+type summaryRow struct {
+	index   map[string]int // 正規化した列名 → 列番号
+	data    []string
+	missing []string // 見つからなかった項目（先頭の列名）
+	invalid []string // 値が数値として読めなかった列
+}
+
+func newSummaryRow(headers, data []string) *summaryRow {
+	index := make(map[string]int, len(headers))
+	for i, h := range headers {
+		key := normalizeSummaryColumn(h)
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+	return &summaryRow{index: index, data: data}
+}
+
+// normalizeSummaryColumn は列名の表記揺れ（大文字小文字・前後の空白・BOM）を吸収する
+func normalizeSummaryColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+}
+
+// String は aliases のうち最初に見つかった列の値を返す（どれも無ければ空文字列）
+func (r *summaryRow) String(aliases []string) string {
+	for _, alias := range aliases {
+		if idx, ok := r.index[normalizeSummaryColumn(alias)]; ok {
+			if idx < len(r.data) {
+				return strings.TrimSpace(r.data[idx])
+			}
+			return ""
+		}
+	}
+	if len(aliases) > 0 {
+		r.missing = append(r.missing, aliases[0])
+	}
+	return ""
+}
+
+// Int は aliases の列を整数として返す（空や読めない値は 0）
+func (r *summaryRow) Int(aliases []string) int {
+	val := r.String(aliases)
+	if val == "" {
+		return 0
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		// pandas が整数列を "3.0" と書くことがある
+		if f, ferr := strconv.ParseFloat(val, 64); ferr == nil && f == float64(int(f)) {
+			return int(f)
+		}
+		r.invalid = append(r.invalid, aliases[0])
+		return 0
+	}
+	return i
+}
+
+// Float は aliases の列を小数として返す（空や読めない値は 0）
+func (r *summaryRow) Float(aliases []string) float64 {
+	val := r.String(aliases)
+	if val == "" {
+		return 0.0
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		r.invalid = append(r.invalid, aliases[0])
+		return 0.0
+	}
+	return f
+}
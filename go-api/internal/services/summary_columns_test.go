@@ -0,0 +1,60 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummaryRowAliases(t *testing.T) {
+	headers := []string{"\ufeffUniProtID", " SEQ_RATIO ", "num_structures", "Length", "UMF"}
+	row := newSummaryRow(headers, []string{"P12345", "0.2", "4.0", "abc", "1.5"})
+	cols := defaultSummaryColumns
+
+	if got := row.String(cols.UniProtID); got != "P12345" {
+		t.Errorf("uniprotid: got %q", got)
+	}
+	if got := row.Float(cols.SeqRatio); got != 0.2 {
+		t.Errorf("seq_ratio: got %v", got)
+	}
+	// 別名で見つかり、pandas の "4.0" も整数として読む
+	if got := row.Int(cols.Entries); got != 4 {
+		t.Errorf("entries: got %d", got)
+	}
+	if got := row.Int(cols.Length); got != 0 {
+		t.Errorf("length: got %d, want 0 for an unparsable value", got)
+	}
+	if got := row.Float(cols.MeanCisDist); got != 0 {
+		t.Errorf("mean_cisDist: got %v, want 0 for a missing column", got)
+	}
+
+	if strings.Join(row.missing, ",") != "mean_cisDist" {
+		t.Errorf("missing = %v, want [mean_cisDist]", row.missing)
+	}
+	if strings.Join(row.invalid, ",") != "Length" {
+		t.Errorf("invalid = %v, want [Length]", row.invalid)
+	}
+}
+
+func TestConvertSummaryCSVRenamedColumns(t *testing.T) {
+	jobDir := filepath.Join(t.TempDir(), "job")
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// エンジンが列名を snake_case・大文字に変えても同じ値を読む
+	writeFile(t, filepath.Join(jobDir, "summary.csv"), "UNIPROTID,Seq_Ratio,ENTRIES,num_chains,LENGTH,length(%),umf,Mean_CisDist,CIS\nP12345,0.2,3,5,3,50.0,1.5,3.1,2\n")
+
+	s := NewJobService(filepath.Dir(jobDir), "", "", nil, nil)
+	result, err := s.convertSummaryCSVToResult("job")
+	if err != nil {
+		t.Fatalf("convertSummaryCSVToResult: %v", err)
+	}
+	if result.UniProtID != "P12345" || result.NumStructures != 3 || result.NumChains != 5 || result.NumResidues != 3 || result.UMF != 1.5 {
+		t.Errorf("got uniprot_id %q, num_structures %d, num_chains %d, num_residues %d, umf %v",
+			result.UniProtID, result.NumStructures, result.NumChains, result.NumResidues, result.UMF)
+	}
+	if result.CisInfo.CisDistMean != 3.1 || result.CisInfo.CisNum != 2 || result.FullSequenceLength != 6 {
+		t.Errorf("got cis_dist_mean %v, cis_num %d, full_sequence_length %d", result.CisInfo.CisDistMean, result.CisInfo.CisNum, result.FullSequenceLength)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+const (
+	// heatmapThumbFile はヒートマップのサムネイルを保存するファイル名
+	heatmapThumbFile = "heatmap_thumb.png"
+	// heatmapThumbSize はサムネイルの長辺のピクセル数
+	heatmapThumbSize = 128
+)
+
+// OpenHeatmapThumbnail はヒートマップの縮小版を開く
+// 初回（またはヒートマップの方が新しい場合）に生成して heatmap_thumb.png として保存し、以降はそれを返す
+// ヒートマップが無ければ fs.ErrNotExist を満たすエラーを返す
+func (s *JobService) OpenHeatmapThumbnail(jobID string) (io.ReadCloser, FileInfo, error) {
+	source, sourceInfo, err := s.OpenHeatmapImage(jobID)
+	if err != nil {
+		return nil, FileInfo{}, err
+	}
+	defer source.Close()
+
+	if info, err := s.storage.Stat(jobID, heatmapThumbFile); err != nil || info.ModTime.Before(sourceInfo.ModTime) {
+		if err := s.writeHeatmapThumbnail(jobID, source); err != nil {
+			return nil, FileInfo{}, err
+		}
+		s.logger.Debug("OpenHeatmapThumbnail: generated thumbnail", "job_id", jobID, "source", sourceInfo.Name)
+	}
+
+	info, err := s.storage.Stat(jobID, heatmapThumbFile)
+	if err != nil {
+		return nil, FileInfo{}, err
+	}
+	file, err := s.storage.Open(jobID, heatmapThumbFile)
+	if err != nil {
+		return nil, FileInfo{}, err
+	}
+	return file, info, nil
+}
+
+// writeHeatmapThumbnail は source の PNG を縮小して heatmap_thumb.png に保存する
+func (s *JobService) writeHeatmapThumbnail(jobID string, source io.Reader) error {
+	img, err := png.Decode(source)
+	if err != nil {
+		return fmt.Errorf("failed to decode heatmap: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, resizeImage(img, heatmapThumbSize)); err != nil {
+		return fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	if err := writeBytes(s.storage, jobID, heatmapThumbFile, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+	return nil
+}
+
+// resizeImage は長辺が maxSize になるよう縦横比を保って縮小する（拡大はしない）
+// 出力の各ピクセルは対応する元画像の範囲の平均（ボックスフィルタ）
+func resizeImage(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dstW, dstH := srcW, srcH
+	if srcW >= srcH && srcW > maxSize {
+		dstW, dstH = maxSize, max(1, srcH*maxSize/srcW)
+	} else if srcH > srcW && srcH > maxSize {
+		dstW, dstH = max(1, srcW*maxSize/srcH), maxSize
+	}
+
+	// Pix を直接読めるよう RGBA（乗算済みアルファ）に揃える
+	rgba := image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	if dstW == srcW && dstH == srcH {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride+x0*4 : sy*rgba.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+
+			n := (y1 - y0) * (x1 - x0)
+			offset := y*dst.Stride + x*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[offset+c] = uint8((sum[c] + n/2) / n)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"testing"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestOpenHeatmapThumbnail(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{}, nil)
+	job, _, err := s.prepareJob(models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("prepareJob: %v", err)
+	}
+
+	if _, _, err := s.OpenHeatmapThumbnail(job.JobID); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("without heatmap: got %v, want fs.ErrNotExist", err)
+	}
+
+	// 左半分が赤、右半分が青の 400x200
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 200 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBytes(s.storage, job.JobID, "P12345_0.2_heatmap.png", buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	file, info, err := s.OpenHeatmapThumbnail(job.JobID)
+	if err != nil {
+		t.Fatalf("OpenHeatmapThumbnail: %v", err)
+	}
+	thumb, err := png.Decode(file)
+	file.Close()
+	if err != nil {
+		t.Fatalf("thumbnail is not a PNG: %v", err)
+	}
+	if info.Name != heatmapThumbFile {
+		t.Errorf("info.Name = %q, want %q", info.Name, heatmapThumbFile)
+	}
+	if got := thumb.Bounds().Size(); got != image.Pt(128, 64) {
+		t.Errorf("thumbnail size = %v, want 128x64", got)
+	}
+	if r, _, b, _ := thumb.At(10, 10).RGBA(); r>>8 != 255 || b != 0 {
+		t.Errorf("left pixel = %v, want red", thumb.At(10, 10))
+	}
+	if r, _, b, _ := thumb.At(120, 10).RGBA(); r != 0 || b>>8 != 255 {
+		t.Errorf("right pixel = %v, want blue", thumb.At(120, 10))
+	}
+
+	// 2回目は保存済みのサムネイルを返す
+	_, second, err := s.OpenHeatmapThumbnail(job.JobID)
+	if err != nil {
+		t.Fatalf("second OpenHeatmapThumbnail: %v", err)
+	}
+	if !second.ModTime.Equal(info.ModTime) {
+		t.Error("thumbnail was regenerated")
+	}
+}
+
+func TestResizeImageDoesNotUpscale(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 50, 80))
+	if got := resizeImage(src, 128).Bounds().Size(); got != image.Pt(50, 80) {
+		t.Errorf("size = %v, want 50x80", got)
+	}
+	tall := image.NewRGBA(image.Rect(0, 0, 100, 1000))
+	if got := resizeImage(tall, 128).Bounds().Size(); got != image.Pt(12, 128) {
+		t.Errorf("size = %v, want 12x128", got)
+	}
+}
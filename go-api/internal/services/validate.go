@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// レポートに含める問題の最大件数
+const maxValidationIssues = 100
+
+// NotebookDSAResult の必須フィールド
+var requiredResultFields = []string{
+	"uniprot_id", "num_structures", "num_residues", "pdb_ids", "seq_ratio",
+	"umf", "pair_score_mean", "pair_score_std", "pair_scores", "per_residue_scores",
+	"heatmap", "cis_info",
+}
+
+// null（NaN）を許容しない float フィールド
+var nonNullableFloatFields = []string{
+	"seq_ratio", "residue_coverage_percent", "umf", "pair_score_mean", "pair_score_std",
+}
+
+type resultValidator struct {
+	report models.ValidationReport
+}
+
+func (v *resultValidator) addf(field, format string, args ...interface{}) {
+	if len(v.report.Issues) >= maxValidationIssues {
+		v.report.Truncated = true
+		return
+	}
+	v.report.Issues = append(v.report.Issues, models.ValidationIssue{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// ValidateResult は外部で生成された result.json を NotebookDSAResult のスキーマに照らして検証
+// ジョブは作成せず、問題点の一覧を返す
+func ValidateResult(data []byte) *models.ValidationReport {
+	v := &resultValidator{}
+
+	// Python の json.dumps が出力する NaN/Infinity は null として扱い、後段で検出する
+	sanitized := replaceNonFiniteLiterals(data)
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(sanitized, &raw); err != nil {
+		v.addf("", "invalid JSON: %v", err)
+		return v.finish()
+	}
+
+	for _, field := range requiredResultFields {
+		if _, ok := raw[field]; !ok {
+			v.addf(field, "required field is missing")
+		}
+	}
+	for _, field := range nonNullableFloatFields {
+		if val, ok := raw[field]; ok && val == nil {
+			v.addf(field, "must be a finite number (got null/NaN)")
+		}
+	}
+	v.checkNullFloats(raw, "pair_scores", "distance_mean", "distance_std", "score")
+	v.checkNullFloats(raw, "per_residue_scores", "score")
+
+	var result models.NotebookDSAResult
+	if err := json.Unmarshal(sanitized, &result); err != nil {
+		v.addf("", "does not match result schema: %v", err)
+		return v.finish()
+	}
+
+	if result.SchemaVersion > models.ResultSchemaVersion {
+		v.addf("schema_version", "%d is newer than the supported version %d", result.SchemaVersion, models.ResultSchemaVersion)
+	}
+
+	if result.Heatmap != nil {
+		if len(result.Heatmap.Values) != result.Heatmap.Size {
+			v.addf("heatmap.values", "has %d rows but heatmap.size is %d", len(result.Heatmap.Values), result.Heatmap.Size)
+		}
+		for i, row := range result.Heatmap.Values {
+			if len(row) != result.Heatmap.Size {
+				v.addf(fmt.Sprintf("heatmap.values[%d]", i), "has %d columns but heatmap.size is %d", len(row), result.Heatmap.Size)
+			}
+		}
+		if result.NumResidues > 0 && result.Heatmap.Size != result.NumResidues {
+			v.addf("heatmap.size", "is %d but num_residues is %d", result.Heatmap.Size, result.NumResidues)
+		}
+	}
+
+	if result.NumResidues > 0 && len(result.PerResidueScores) > 0 && len(result.PerResidueScores) != result.NumResidues {
+		v.addf("per_residue_scores", "has %d entries but num_residues is %d", len(result.PerResidueScores), result.NumResidues)
+	}
+
+	for idx, ps := range result.PairScores {
+		if ps.I < 1 || ps.J < 1 || (result.NumResidues > 0 && (ps.I > result.NumResidues || ps.J > result.NumResidues)) {
+			v.addf(fmt.Sprintf("pair_scores[%d]", idx), "pair (%d, %d) is out of range 1..%d", ps.I, ps.J, result.NumResidues)
+		}
+	}
+
+	return v.finish()
+}
+
+// checkNullFloats は配列フィールドの各要素で null（NaN）になっている float を検出
+func (v *resultValidator) checkNullFloats(raw map[string]interface{}, field string, keys ...string) {
+	items, ok := raw[field].([]interface{})
+	if !ok {
+		return
+	}
+	for idx, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			if val, ok := obj[key]; ok && val == nil {
+				v.addf(fmt.Sprintf("%s[%d].%s", field, idx, key), "must be a finite number (got null/NaN)")
+			}
+		}
+	}
+}
+
+func (v *resultValidator) finish() *models.ValidationReport {
+	v.report.Valid = len(v.report.Issues) == 0
+	if v.report.Issues == nil {
+		v.report.Issues = []models.ValidationIssue{}
+	}
+	return &v.report
+}
+
+// replaceNonFiniteLiterals は文字列外の NaN / Infinity / -Infinity を null に置き換える
+func replaceNonFiniteLiterals(data []byte) []byte {
+	var out bytes.Buffer
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+			out.WriteByte(c)
+			continue
+		}
+		replaced := false
+		for _, lit := range []string{"-Infinity", "Infinity", "NaN"} {
+			if bytes.HasPrefix(data[i:], []byte(lit)) {
+				out.WriteString("null")
+				i += len(lit) - 1
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out.WriteByte(c)
+		}
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+const (
+	// webhookTimeout は1回の通知リクエストのタイムアウト
+	webhookTimeout = 10 * time.Second
+	// webhookAttempts は通知の最大試行回数
+	webhookAttempts = 3
+	// defaultWebhookBackoff は最初の再試行までの待ち時間（以降は倍々）
+	defaultWebhookBackoff = time.Second
+)
+
+// notifyWebhook は callback_url が指定されたジョブの終了を通知する
+// 通知の失敗はログに残すだけで、ジョブのステータスには影響させない
+func (s *JobService) notifyWebhook(status models.JobStatus) {
+	params, err := s.GetJobParams(status.JobID)
+	if err != nil || params.CallbackURL == nil || *params.CallbackURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(models.WebhookPayload{
+		JobID:   status.JobID,
+		Status:  status.Status,
+		Message: status.Message,
+	})
+	if err != nil {
+		s.logger.Error("notifyWebhook: failed to marshal payload", "job_id", status.JobID, "error", err)
+		return
+	}
+
+	backoff := s.webhookBackoff
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		err = s.postWebhook(*params.CallbackURL, body)
+		if err == nil {
+			s.logger.Info("notifyWebhook: delivered", "job_id", status.JobID, "status", status.Status, "attempt", attempt)
+			return
+		}
+		s.logger.Warn("notifyWebhook: delivery failed", "job_id", status.JobID, "attempt", attempt, "error", err)
+		if attempt < webhookAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	s.logger.Error("notifyWebhook: giving up", "job_id", status.JobID, "attempts", webhookAttempts)
+}
+
+// postWebhook は JSON 本文を1回 POST する（2xx 以外はエラー）
+func (s *JobService) postWebhook(callbackURL string, body []byte) error {
+	resp, err := s.webhookClient.Post(callbackURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
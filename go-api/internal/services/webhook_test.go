@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestWebhookRetriesUntilDelivered(t *testing.T) {
+	var attempts int32
+	delivered := make(chan models.WebhookPayload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 1回目は失敗させて再試行を確認する
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var payload models.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+		}
+		delivered <- payload
+	}))
+	defer srv.Close()
+
+	s := NewJobService(t.TempDir(), "python3", "", &FakeRunner{Files: summaryFixture()}, nil)
+	s.webhookBackoff = time.Millisecond
+
+	callbackURL := srv.URL
+	job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345", CallbackURL: &callbackURL})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	waitForStatus(t, s, job.JobID)
+
+	select {
+	case payload := <-delivered:
+		if payload.JobID != job.JobID || payload.Status != "completed" {
+			t.Errorf("unexpected payload: %+v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	if n := atomic.LoadInt32(&attempts); n != 2 {
+		t.Errorf("got %d attempts, want 2", n)
+	}
+}
+
+func TestValidateRejectsNonHTTPCallbackURL(t *testing.T) {
+	for _, u := range []string{"ftp://example.com/hook", "example.com/hook", "http://"} {
+		callbackURL := u
+		params := models.AnalysisParams{UniProtIDs: "P12345", CallbackURL: &callbackURL}
+		if err := params.Validate(); err == nil {
+			t.Errorf("Validate accepted callback_url %q", u)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+// 同時に実行できる Python 解析数のデフォルト
+const defaultMaxConcurrent = 4
+
+// 待ち行列が同時実行数のこの倍数に達したら overloaded とみなす
+const queueOverloadFactor = 4
+
+// priorityAgingInterval は待ち行列のジョブの優先度を 1 段引き上げる待ち時間
+// low のジョブも high が途切れなければ永遠に待つことにならないよう、待つほど前に出す
+const priorityAgingInterval = 5 * time.Minute
+
+// 待ち行列での優先度（大きいほど先に実行枠を渡す）
+const (
+	priorityLow = iota
+	priorityNormal
+	priorityHigh
+)
+
+// priorityRank は AnalysisParams.Priority を待ち行列での優先度にする（未指定は normal）
+func priorityRank(priority *string) int {
+	if priority == nil {
+		return priorityNormal
+	}
+	switch *priority {
+	case models.PriorityLow:
+		return priorityLow
+	case models.PriorityHigh:
+		return priorityHigh
+	default:
+		return priorityNormal
+	}
+}
+
+// queuedJob は実行枠の空き待ちのジョブ
+type queuedJob struct {
+	jobID      string
+	priority   int
+	enqueuedAt time.Time
+	ready      chan struct{} // 実行枠を渡されたら閉じる
+	granted    bool          // 実行枠を渡されたか（s.mu で保護）
+}
+
+// effectivePriority は待ち時間で引き上げた優先度（priorityAgingInterval ごとに 1 段、high まで）
+func (q *queuedJob) effectivePriority(now time.Time) int {
+	return min(q.priority+int(now.Sub(q.enqueuedAt)/priorityAgingInterval), priorityHigh)
+}
+
+// SetMaxConcurrent は同時に実行できる Python 解析数を設定（起動時にのみ呼ぶ）
+func (s *JobService) SetMaxConcurrent(n int) error {
+	if n < 1 {
+		return fmt.Errorf("max concurrent must be >= 1: %d", n)
+	}
+	s.workers = make(chan struct{}, n)
+	return nil
+}
+
+// QueueDepth は実行枠の空き待ちジョブ数を返す
+func (s *JobService) QueueDepth() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.waiting)
+}
+
+// acquireWorker は実行枠を確保する。空きが無ければ "pending" のまま待ち行列に入る
+// 待ち行列からは優先度の高い順（同じなら先に並んだ順）に実行枠を渡す
+// 待機中にキャンセルされた場合は false を返す
+func (s *JobService) acquireWorker(jobID string, priority *string) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.registerCancel(jobID, cancel)
+	defer s.unregisterCancel(jobID)
+	// 開始時の確認から登録までの間にキャンセルされていれば並ばない
+	if status, err := s.GetJobStatus(jobID); err == nil && IsTerminalStatus(status.Status) {
+		return false
+	}
+
+	s.mu.Lock()
+	// 待っているジョブがあれば空きを横取りしない（解放時に待ち行列の先頭へ直接渡される）
+	if len(s.waiting) == 0 {
+		select {
+		case s.workers <- struct{}{}:
+			s.mu.Unlock()
+			return true
+		default:
+		}
+	}
+	job := &queuedJob{jobID: jobID, priority: priorityRank(priority), enqueuedAt: time.Now(), ready: make(chan struct{})}
+	s.waiting = append(s.waiting, job)
+	s.mu.Unlock()
+	s.refreshQueueMessages()
+
+	select {
+	case <-job.ready:
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	acquired := job.granted
+	if !acquired {
+		s.removeQueuedJob(job)
+	}
+	s.mu.Unlock()
+
+	if !acquired {
+		s.refreshQueueMessages()
+		return false
+	}
+	// 実行枠を渡されたのと同時にキャンセルされた場合は次のジョブに回す
+	if ctx.Err() != nil {
+		s.releaseWorker()
+		return false
+	}
+	return true
+}
+
+// releaseWorker は実行枠を解放する。待っているジョブがあれば、優先度の一番高いジョブにそのまま渡す
+func (s *JobService) releaseWorker() {
+	s.mu.Lock()
+	if order := s.queueOrder(time.Now()); len(order) > 0 {
+		next := order[0]
+		s.removeQueuedJob(next)
+		next.granted = true
+		close(next.ready)
+		s.mu.Unlock()
+		s.refreshQueueMessages()
+		return
+	}
+	<-s.workers
+	s.mu.Unlock()
+}
+
+// removeQueuedJob は待ち行列から job を外す（呼び出し側で s.mu を保持すること）
+func (s *JobService) removeQueuedJob(job *queuedJob) {
+	for i, q := range s.waiting {
+		if q == job {
+			s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+// queueOrder は待ち行列を実行枠を渡す順に並べたコピーを返す（呼び出し側で s.mu を保持すること）
+func (s *JobService) queueOrder(now time.Time) []*queuedJob {
+	order := append([]*queuedJob(nil), s.waiting...)
+	sort.SliceStable(order, func(i, j int) bool {
+		pi, pj := order[i].effectivePriority(now), order[j].effectivePriority(now)
+		if pi != pj {
+			return pi > pj
+		}
+		return order[i].enqueuedAt.Before(order[j].enqueuedAt)
+	})
+	return order
+}
+
+// setQueuePosition は待ち行列にいるジョブの status に順番（1 なら次に実行される）を入れる（呼び出し側で s.mu を保持すること）
+func (s *JobService) setQueuePosition(status *models.JobStatus) {
+	status.QueuePosition = nil
+	if status.Status != "pending" {
+		return
+	}
+	for i, q := range s.queueOrder(time.Now()) {
+		if q.jobID == status.JobID {
+			position := i + 1
+			status.QueuePosition = &position
+			return
+		}
+	}
+}
+
+// refreshQueueMessages は待ち行列の全ジョブの順番表示を更新する
+// 読んでから書くまでの間にキャンセルされたジョブは writeJobStatus が書き換えを拒む
+func (s *JobService) refreshQueueMessages() {
+	s.mu.RLock()
+	order := s.queueOrder(time.Now())
+	s.mu.RUnlock()
+
+	for ahead, q := range order {
+		if status, err := s.GetJobStatus(q.jobID); err == nil && status.Status == "pending" && status.Message != queueMessage(ahead) {
+			s.updateJobStatus(q.jobID, "pending", 0, queueMessage(ahead))
+		}
+	}
+}
+
+// queueMessage は待ち行列のジョブのステータスメッセージ
+func queueMessage(ahead int) string {
+	return fmt.Sprintf("queued, %d ahead", ahead)
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/flex-api/internal/models"
+)
+
+func TestWorkerPoolRunsHigherPriorityFirst(t *testing.T) {
+	runner := &FakeRunner{Block: true}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	if err := s.SetMaxConcurrent(1); err != nil {
+		t.Fatalf("SetMaxConcurrent: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+
+	create := func(uniprotID, priority string) string {
+		t.Helper()
+		job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: uniprotID, Priority: &priority})
+		if err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+		return job.JobID
+	}
+
+	first := create("P12345", models.PriorityNormal)
+	for len(runner.Calls()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	low := create("Q11111", models.PriorityLow)
+	for s.QueueDepth() < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	high := create("Q22222", models.PriorityHigh)
+	for s.QueueDepth() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 後から来た high が low より前に並ぶ
+	for jobID, want := range map[string]int{high: 1, low: 2} {
+		status, err := s.GetJobStatus(jobID)
+		if err != nil {
+			t.Fatalf("GetJobStatus: %v", err)
+		}
+		if status.QueuePosition == nil || *status.QueuePosition != want {
+			t.Errorf("job %s: got queue_position %v, want %d", jobID, status.QueuePosition, want)
+		}
+	}
+	status, err := s.GetJobStatus(first)
+	if err != nil {
+		t.Fatalf("GetJobStatus: %v", err)
+	}
+	if status.QueuePosition != nil {
+		t.Errorf("running job: got queue_position %d, want none", *status.QueuePosition)
+	}
+
+	if err := s.CancelJob(first); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	for len(runner.Calls()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if calls := runner.Calls(); len(calls) != 2 || argValue(calls[1], "--uniprot-ids") != "Q22222" {
+		t.Fatalf("second run: got %v, want Q22222", calls)
+	}
+
+	for _, jobID := range []string{low, high} {
+		if err := s.CancelJob(jobID); err != nil {
+			t.Fatalf("CancelJob: %v", err)
+		}
+		waitForStatus(t, s, jobID)
+	}
+}
+
+func TestQueueOrderAgesWaitingJobs(t *testing.T) {
+	s := NewJobService(t.TempDir(), "python3", "", nil, nil)
+	now := time.Now()
+	low := &queuedJob{jobID: "low", priority: priorityLow, enqueuedAt: now.Add(-2 * priorityAgingInterval)}
+	high := &queuedJob{jobID: "high", priority: priorityHigh, enqueuedAt: now.Add(-time.Minute)}
+	normal := &queuedJob{jobID: "normal", priority: priorityNormal, enqueuedAt: now}
+	s.waiting = []*queuedJob{normal, high, low}
+
+	// low は 2 段引き上げられて high と並び、先に並んだ分だけ前に出る
+	want := []string{"low", "high", "normal"}
+	order := s.queueOrder(now)
+	for i, q := range order {
+		if q.jobID != want[i] {
+			t.Fatalf("got order %v at %d, want %v", q.jobID, i, want)
+		}
+	}
+}
+
+func TestCancelQueuedJobStaysCancelled(t *testing.T) {
+	runner := &FakeRunner{Block: true}
+	s := NewJobService(t.TempDir(), "python3", "", runner, nil)
+	if err := s.SetMaxConcurrent(1); err != nil {
+		t.Fatalf("SetMaxConcurrent: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+
+	first, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+	if err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	for len(runner.Calls()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 待ち行列に入ったそばからキャンセルし、後続ジョブの順番表示の更新と競合させる
+	const n = 20
+	var wg sync.WaitGroup
+	queued := make([]string, n)
+	for i := range queued {
+		job, err := s.CreateJob(context.Background(), models.AnalysisParams{UniProtIDs: "P12345"})
+		if err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+		queued[i] = job.JobID
+		wg.Add(1)
+		go func(jobID string) {
+			defer wg.Done()
+			if err := s.CancelJob(jobID); err != nil {
+				t.Errorf("CancelJob: %v", err)
+			}
+		}(job.JobID)
+	}
+	wg.Wait()
+	for s.QueueDepth() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, jobID := range queued {
+		status, err := s.GetJobStatus(jobID)
+		if err != nil {
+			t.Fatalf("GetJobStatus: %v", err)
+		}
+		if status.Status != "cancelled" {
+			t.Errorf("job %s: got %q (%s), want cancelled", jobID, status.Status, status.Message)
+		}
+	}
+
+	if err := s.CancelJob(first.JobID); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	waitForStatus(t, s, first.JobID)
+	if got := len(runner.Calls()); got != 1 {
+		t.Errorf("runner called %d times, want 1 (cancelled jobs must not run)", got)
+	}
+}
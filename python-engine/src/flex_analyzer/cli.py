@@ -6,7 +6,12 @@ import click
 from pathlib import Path
 
 from .pipelines import run_dsa_pipeline
-from .notebook_dsa_pipeline import run_notebook_dsa_analysis
+from .notebook_dsa_pipeline import (
+    InputTooLargeError,
+    NoSuitableStructuresError,
+    check_structures,
+    run_notebook_dsa_analysis,
+)
 
 
 @click.command()
@@ -160,6 +165,11 @@ def main(
     default="",
     help="PDB IDs to exclude (space or comma separated)",
 )
+@click.option(
+    "--pdb-ids",
+    default="",
+    help="Analyze exactly these PDB IDs instead of selecting structures from UniProt (space or comma separated)",
+)
 @click.option(
     "--cis-threshold",
     default=3.3,
@@ -176,7 +186,7 @@ def main(
     "--pdb-dir",
     default="pdb_files",
     type=click.Path(),
-    help="Directory to store PDB files (default: pdb_files)",
+    help="Directory to store downloaded mmCIF files (default: pdb_files)",
 )
 @click.option(
     "--export/--no-export",
@@ -198,6 +208,18 @@ def main(
     default=True,
     help="Overwrite existing data (default: True)",
 )
+@click.option(
+    "--max-residues",
+    default=0,
+    type=int,
+    help="Reject UniProt entries longer than this many residues (default: 0 = no limit)",
+)
+@click.option(
+    "--max-structures",
+    default=0,
+    type=int,
+    help="Reject UniProt entries with more PDB entries than this (default: 0 = no limit)",
+)
 @click.option(
     "--verbose/--no-verbose",
     default=True,
@@ -208,6 +230,7 @@ def notebook_main(
     method: str,
     seq_ratio: float,
     negative_pdbid: str,
+    pdb_ids: str,
     cis_threshold: float,
     output_dir: str,
     pdb_dir: str,
@@ -215,6 +238,8 @@ def notebook_main(
     heatmap: bool,
     proc_cis: bool,
     overwrite: bool,
+    max_residues: int,
+    max_structures: int,
     verbose: bool,
 ):
     """
@@ -240,12 +265,15 @@ def notebook_main(
         click.echo(f"  Seq ratio: {seq_ratio}")
         click.echo(f"  Cis threshold: {cis_threshold} A")
         click.echo(f"  Negative PDB IDs: {negative_pdbid if negative_pdbid else '(none)'}")
+        click.echo(f"  PDB IDs: {pdb_ids if pdb_ids else '(selected from UniProt)'}")
         click.echo(f"  Output directory: {output_dir}")
         click.echo(f"  PDB directory: {pdb_dir}")
         click.echo(f"  Export CSV: {export}")
         click.echo(f"  Generate heatmap: {heatmap}")
         click.echo(f"  Process cis: {proc_cis}")
         click.echo(f"  Overwrite: {overwrite}")
+        click.echo(f"  Max residues: {max_residues if max_residues else '(no limit)'}")
+        click.echo(f"  Max structures: {max_structures if max_structures else '(no limit)'}")
         click.echo()
 
     try:
@@ -262,11 +290,23 @@ def notebook_main(
             overwrite=overwrite,
             output_dir=Path(output_dir),
             pdb_dir=Path(pdb_dir),
+            max_residues=max_residues,
+            max_structures=max_structures,
+            pdb_ids=pdb_ids,
         )
 
         if verbose:
             click.echo("\n✅ Analysis completed successfully.")
 
+    except NoSuitableStructuresError as e:
+        # 入力の問題なのでトレースバックは出さず、判別用の例外名を付けて出力する
+        click.echo(f"\nNoSuitableStructuresError: {e}", err=True)
+        raise click.Abort()
+
+    except InputTooLargeError as e:
+        click.echo(f"\nInputTooLargeError: {e}", err=True)
+        raise click.Abort()
+
     except Exception as e:
         click.echo(f"\nError: {str(e)}", err=True)
         if verbose:
@@ -277,6 +317,34 @@ def notebook_main(
         raise click.Abort()
 
 
+@click.command()
+@click.option("--uniprot-ids", required=True, help="UniProt ID(s) (comma or space separated)")
+@click.option(
+    "--method",
+    default="X-ray",
+    help="PDB method filter: X-ray, NMR, EM (default: X-ray)",
+)
+@click.option(
+    "--negative-pdbid",
+    default="",
+    help="PDB IDs to exclude (space or comma separated)",
+)
+@click.option(
+    "--pdb-ids",
+    default="",
+    help="Analyze exactly these PDB IDs instead of selecting structures from UniProt (space or comma separated)",
+)
+def check_main(uniprot_ids: str, method: str, negative_pdbid: str, pdb_ids: str):
+    """
+    構造の取得・解析をせずに、各UniProt IDで使えるPDBエントリを調べる（dry run 用）
+
+    結果は UniProt ID ごとのオブジェクトの JSON 配列として標準出力の最終行に出力する
+    """
+    import json
+
+    click.echo(json.dumps(check_structures(uniprot_ids, method, negative_pdbid, pdb_ids)))
+
+
 if __name__ == "__main__":
     import sys
 
@@ -284,5 +352,8 @@ if __name__ == "__main__":
     if len(sys.argv) > 1 and sys.argv[1] == "notebook":
         sys.argv = sys.argv[1:]  # "notebook"を削除
         notebook_main()
+    elif len(sys.argv) > 1 and sys.argv[1] == "check":
+        sys.argv = sys.argv[1:]  # "check"を削除
+        check_main()
     else:
         main()
@@ -49,6 +49,42 @@ from .pipelines.dsa_pipeline import save_distance_score_plot
 PDB_THRESHOLD = 1
 CHAIN_THRESHOLD = 3  # 標準偏差を出すため、最低でも3つのChainが必要
 
+# structures_{uniprotid}.csv の列（API の GET /api/dsa/jobs/:job_id/structures が読む）
+STRUCTURE_FIELDS = [
+    "pdb_id",
+    "method",
+    "resolution",
+    "num_chains",
+    "chains_used",
+    "mutation",
+    "included",
+    "reason",
+]
+
+
+class NoSuitableStructuresError(Exception):
+    """解析に使える構造（PDBエントリ・Chain）が足りず、どのUniProt IDも解析できなかった"""
+
+
+class InputTooLargeError(Exception):
+    """残基数・PDBエントリ数が上限を超え、どのUniProt IDも解析しなかった"""
+
+
+def input_size_exceeded(
+    num_residues: int, num_structures: int, max_residues: int = 0, max_structures: int = 0
+) -> str:
+    """
+    残基数・PDBエントリ数が上限（0 は無制限）を超えているか
+
+    Returns:
+        超えていればその説明、超えていなければ空文字
+    """
+    if max_residues > 0 and num_residues > max_residues:
+        return f"{num_residues} residues (max {max_residues})"
+    if max_structures > 0 and num_structures > max_structures:
+        return f"{num_structures} PDB entries (max {max_structures})"
+    return ""
+
 
 def filter_pdb_list(pdblist: List[str], negative_pdbid: str) -> List[str]:
     """
@@ -75,7 +111,74 @@ def filter_pdb_list(pdblist: List[str], negative_pdbid: str) -> List[str]:
     return filtered
 
 
-def count_pdb(uniprotid: str, method: str, negative_pdbid: str = "") -> bool:
+def split_pdb_ids(pdb_ids: str) -> List[str]:
+    """
+    スペースまたはカンマ区切りのPDB IDを、大文字にして重複を除いたリストにする（指定順を保つ）
+
+    Args:
+        pdb_ids: PDB ID（スペースまたはカンマ区切り）
+
+    Returns:
+        PDB IDのリスト
+    """
+    ids: List[str] = []
+    for pdbid in re.split(r"[,\s]+", pdb_ids.strip()):
+        if pdbid and pdbid.upper() not in ids:
+            ids.append(pdbid.upper())
+    return ids
+
+
+def select_pdb_list(
+    unidata: UniprotData, method: str, negative_pdbid: str = "", pdb_ids: str = ""
+) -> List[str]:
+    """
+    解析対象になるPDBエントリ（negative_pdbidを除いたもの）を選ぶ
+
+    pdb_ids を指定した場合は UniProt からの自動選択（method による絞り込み）をせず、
+    指定したエントリのうち UniProt エントリに載っているもの（配列上の位置が分かるもの）を指定順に使う
+
+    Args:
+        unidata: UniprotData
+        method: 構造決定手法（"X-ray", "NMR", "EM"など）
+        negative_pdbid: 除外するPDB ID
+        pdb_ids: 解析するPDB ID（スペースまたはカンマ区切り、空なら自動選択）
+
+    Returns:
+        PDB IDのリスト
+    """
+    requested = split_pdb_ids(pdb_ids)
+    if requested:
+        # 手法で絞り込まずに読み、位置・分解能を引けるようにしておく
+        pdbdata = unidata.getpdbdata(None)
+        available = {str(pdbid).upper(): pdbid for pdbid in pdbdata.columns}
+        pdblist = [available[pdbid] for pdbid in requested if pdbid in available]
+    else:
+        # methodの正規化
+        if method == "X-ray diffraction":
+            method = "X-ray"
+        pdblist = unidata.pdblist(method)
+    return filter_pdb_list(pdblist, negative_pdbid)
+
+
+def available_pdb_list(
+    uniprotid: str, method: str, negative_pdbid: str = "", pdb_ids: str = ""
+) -> List[str]:
+    """
+    解析対象になるPDBエントリ（negative_pdbidを除いたもの）を取得
+
+    Args:
+        uniprotid: UniProt ID
+        method: 構造決定手法（"X-ray", "NMR", "EM"など）
+        negative_pdbid: 除外するPDB ID
+        pdb_ids: 解析するPDB ID（空なら自動選択）
+
+    Returns:
+        PDB IDのリスト
+    """
+    return select_pdb_list(UniprotData(uniprotid), method, negative_pdbid, pdb_ids)
+
+
+def count_pdb(uniprotid: str, method: str, negative_pdbid: str = "", pdb_ids: str = "") -> bool:
     """
     PDBエントリ数が閾値以上かチェック
 
@@ -83,18 +186,41 @@ def count_pdb(uniprotid: str, method: str, negative_pdbid: str = "") -> bool:
         uniprotid: UniProt ID
         method: 構造決定手法（"X-ray", "NMR", "EM"など）
         negative_pdbid: 除外するPDB ID
+        pdb_ids: 解析するPDB ID（空なら自動選択）
 
     Returns:
         PDBエントリ数が閾値以上ならTrue
     """
-    unidata = UniprotData(uniprotid)
-    # methodの正規化
-    if method == "X-ray diffraction":
-        method = "X-ray"
-    pdblist = unidata.pdblist(method)
-    pdblist = filter_pdb_list(pdblist, negative_pdbid)
+    return len(available_pdb_list(uniprotid, method, negative_pdbid, pdb_ids)) >= PDB_THRESHOLD
+
+
+def check_structures(
+    uniprot_ids: str, method: str, negative_pdbid: str = "", pdb_ids: str = ""
+) -> List[Dict[str, Any]]:
+    """
+    構造のダウンロードや解析をせずに、各UniProt IDで使えるPDBエントリを調べる（dry run 用）
+    seq_ratio によるChainの絞り込みは解析時にしか分からないので、ここではエントリ数のみ
+
+    Args:
+        uniprot_ids: UniProt ID（カンマまたはスペース区切り）
+        method: 構造決定手法
+        negative_pdbid: 除外するPDB ID
+        pdb_ids: 解析するPDB ID（空なら自動選択）
 
-    return len(pdblist) >= PDB_THRESHOLD
+    Returns:
+        UniProt IDごとの {"uniprot_id", "pdb_ids", "sufficient", "error"}
+    """
+    results = []
+    for uniprotid in [x.strip() for x in re.split(r"[,\s]+", uniprot_ids.strip()) if x.strip()]:
+        entry: Dict[str, Any] = {"uniprot_id": uniprotid, "pdb_ids": [], "sufficient": False}
+        try:
+            pdblist = available_pdb_list(uniprotid, method, negative_pdbid, pdb_ids)
+            entry["pdb_ids"] = [str(pdbid) for pdbid in pdblist]
+            entry["sufficient"] = len(pdblist) >= PDB_THRESHOLD
+        except Exception as e:
+            entry["error"] = str(e)
+        results.append(entry)
+    return results
 
 
 def prep(
@@ -103,6 +229,8 @@ def prep(
     negative_pdbid: str = "",
     pdb_dir: Path = Path("pdb_files"),
     verbose: bool = True,
+    judges: Optional[Dict[str, str]] = None,
+    pdb_ids: str = "",
 ) -> Tuple[pd.DataFrame, List[List[str]]]:
     """
     データ準備（Notebookのprep関数を再現）
@@ -113,6 +241,8 @@ def prep(
         negative_pdbid: 除外するPDB ID
         pdb_dir: PDBファイル保存ディレクトリ
         verbose: ログ出力
+        judges: 指定すると PDB ID ごとの判定結果（mutationjudge の値、失敗時は "error"）を記録する
+        pdb_ids: 解析するPDB ID（空なら UniProt から自動選択）
 
     Returns:
         (seqdata, all_pdblist)
@@ -127,12 +257,7 @@ def prep(
     seqdata = pd.DataFrame(sequence, columns=[id_str])
     len_seqdata = len(seqdata)
 
-    # methodの正規化
-    method_normalized = method
-    if method == "X-ray diffraction":
-        method_normalized = "X-ray"
-    pdblist = unidata.pdblist(method_normalized)
-    pdblist = filter_pdb_list(pdblist, negative_pdbid)
+    pdblist = select_pdb_list(unidata, method, negative_pdbid, pdb_ids)
 
     if verbose:
         print(f"  Processing {len(pdblist)} PDB entries ...")
@@ -146,6 +271,8 @@ def prep(
         try:
             cifdata = CifData(pdbid, pdir=str(pdb_dir))
             mut_judge = cifdata.mutationjudge(uniprotids, pdbid)
+            if judges is not None:
+                judges[pdbid] = str(mut_judge)
 
             if verbose:
                 print(f" ({n+1}/{len(pdblist)}) judge: {pdbid} {mut_judge}")
@@ -186,6 +313,8 @@ def prep(
                     print(f"  WARNING: {pdbid} の配列が取得できませんでした")
 
         except Exception as e:
+            if judges is not None:
+                judges[pdbid] = "error"
             if verbose:
                 print(f"  ERROR processing {pdbid}: {e}")
             continue
@@ -206,6 +335,111 @@ def prep(
     return seqdata, all_pdblist
 
 
+def parse_resolution(value: Any) -> Optional[float]:
+    """
+    UniProt の resolution プロパティ（例: "1.80 A"）を数値にする（NMR など値が無い場合は None）
+    """
+    if value is None or (isinstance(value, float) and np.isnan(value)):
+        return None
+    try:
+        return float(str(value).split(" ")[0])
+    except ValueError:
+        return None
+
+
+def count_chains(position: Any) -> int:
+    """
+    UniProt の chains プロパティ（例: "A/B=1-76, C=5-70"）に含まれるチェーン数を数える
+    """
+    if position is None or (isinstance(position, float) and np.isnan(position)):
+        return 0
+    count = 0
+    for chain_info in str(position).split(","):
+        chain_ids = chain_info.split("=")[0].strip()
+        if chain_ids:
+            count += len(chain_ids.split("/"))
+    return count
+
+
+def structure_details(
+    pdbdata: pd.DataFrame,
+    negative_pdbid: str,
+    judges: Dict[str, str],
+    trimsequence: pd.DataFrame,
+    pdb_ids: str = "",
+) -> List[Dict[str, Any]]:
+    """
+    PDBエントリごとの手法・分解能・チェーン数と、解析に使ったか（使わなかった理由）をまとめる
+
+    Args:
+        pdbdata: UniprotData.pdbdata（手法で絞り込み済み、pdb_ids 指定時は絞り込みなし）
+        negative_pdbid: 除外するPDB ID
+        judges: prep が記録した判定結果
+        trimsequence: seq_ratio で絞り込んだ後の配列（列名は "pdbid chain"）
+        pdb_ids: 解析するPDB ID（指定時は指定したエントリだけを指定順にまとめる）
+
+    Returns:
+        PDB IDごとの {"pdb_id", "method", "resolution", "num_chains", "chains_used",
+        "mutation", "included", "reason"}
+        reason は negative_pdbid / not_in_uniprot / error / chimera / delins / unclassified / seq_ratio のいずれか
+    """
+    chains_used: Dict[str, int] = {}
+    for col in trimsequence.columns.values[1:]:
+        pdbid = str(col).split(" ")[0]
+        chains_used[pdbid] = chains_used.get(pdbid, 0) + 1
+
+    negative = {neg.upper() for neg in re.split(r"[,\s]+", negative_pdbid.strip()) if neg}
+
+    details = []
+    pdbids = list(pdbdata.columns)
+    requested = split_pdb_ids(pdb_ids)
+    if requested:
+        available = {str(pdbid).upper(): pdbid for pdbid in pdbids}
+        pdbids = [available[pdbid] for pdbid in requested if pdbid in available]
+        # UniProt エントリに載っていない指定は、配列上の位置が分からないので使えない
+        for pdbid in requested:
+            if pdbid not in available:
+                details.append(
+                    {
+                        "pdb_id": pdbid,
+                        "method": "",
+                        "resolution": "",
+                        "num_chains": 0,
+                        "chains_used": 0,
+                        "mutation": "",
+                        "included": False,
+                        "reason": "not_in_uniprot",
+                    }
+                )
+    for pdbid in pdbids:
+        judge = judges.get(pdbid, "")
+        used = chains_used.get(pdbid, 0)
+        if pdbid.upper() in negative:
+            reason = "negative_pdbid"
+        elif judge in ("error", "chimera", "delins"):
+            reason = judge
+        elif judge not in ("normal", "substitution"):
+            reason = "unclassified"
+        elif used == 0:
+            reason = "seq_ratio"
+        else:
+            reason = ""
+        resolution = parse_resolution(pdbdata.at["resolution", pdbid])
+        details.append(
+            {
+                "pdb_id": pdbid,
+                "method": pdbdata.at["method", pdbid] or "",
+                "resolution": "" if resolution is None else resolution,
+                "num_chains": count_chains(pdbdata.at["position", pdbid]),
+                "chains_used": used,
+                "mutation": judge if judge != "error" else "",
+                "included": reason == "",
+                "reason": reason,
+            }
+        )
+    return details
+
+
 def generate_log_content(
     pdbdata: pd.DataFrame,
     len_sequence: int,
@@ -584,6 +818,9 @@ def run_notebook_dsa_analysis(
     overwrite: bool = True,
     output_dir: Path = Path("output"),
     pdb_dir: Path = Path("pdb_files"),
+    max_residues: int = 0,
+    max_structures: int = 0,
+    pdb_ids: str = "",
 ) -> None:
     """
     Notebook DSA解析のメイン関数（Colabコードを完全再現）
@@ -601,6 +838,10 @@ def run_notebook_dsa_analysis(
         overwrite: 上書きするか
         output_dir: 出力ディレクトリ
         pdb_dir: PDBファイル保存ディレクトリ
+        max_residues: 残基数の上限（超えるUniProt IDは解析しない、0 は無制限）
+        max_structures: PDBエントリ数の上限（超えるUniProt IDは解析しない、0 は無制限）
+        pdb_ids: 解析するPDB ID（スペースまたはカンマ区切り）。指定すると UniProt からの自動選択をせず、
+            指定したエントリだけを解析する（公開済みの解析の再現用）
     """
     # 出力ディレクトリ設定
     output_dir.mkdir(parents=True, exist_ok=True)
@@ -651,6 +892,12 @@ def run_notebook_dsa_analysis(
     # UniProt IDの分割
     ids = [x.strip() for x in re.split(r"[,\s]+", uniprot_ids.strip())]
 
+    # 構造不足でスキップしたUniProt IDと、解析できたIDの数
+    insufficient_ids = []
+    analyzed = 0
+    # 上限を超えてスキップしたUniProt ID（"ID (理由)"）
+    oversized_ids = []
+
     # 各UniProt IDを処理
     for i, uniprotid in enumerate(ids):
         try:
@@ -676,14 +923,28 @@ def run_notebook_dsa_analysis(
             if method == "X-ray diffraction":
                 method_normalized = "X-ray"
 
-            if not count_pdb(uniprotid, method_normalized, negative_pdbid):
+            if len(select_pdb_list(unidata, method_normalized, negative_pdbid, pdb_ids)) < PDB_THRESHOLD:
                 print("Less than 3 PDB entries")
+                insufficient_ids.append(uniprotid)
                 if verbose:
                     print("###############################################")
                 continue
 
+            # 大きすぎる入力は構造のダウンロード・解析の前に断る（N×N の距離行列でメモリを使い切らないように）
+            exceeded = input_size_exceeded(
+                len(unidata.fasta()),
+                len(select_pdb_list(unidata, method_normalized, negative_pdbid, pdb_ids)),
+                max_residues,
+                max_structures,
+            )
+            if exceeded:
+                print(f"Too large: {exceeded}")
+                oversized_ids.append(f"{uniprotid} ({exceeded})")
+                continue
+
+            judges: Dict[str, str] = {}
             seqdata, all_pdblist = prep(
-                uniprotid, method_normalized, negative_pdbid, pdb_dir, verbose
+                uniprotid, method_normalized, negative_pdbid, pdb_dir, verbose, judges, pdb_ids
             )
             seqdata1 = seqdata.filter(like=uniprotid)
 
@@ -746,9 +1007,24 @@ def run_notebook_dsa_analysis(
                 method_normalized,
             )
 
+            # PDBエントリごとの採否（Chain不足で終わる場合も理由が分かるようにパース前に書く）
+            if export:
+                if getattr(unidata, "pdbdata", None) is None:
+                    unidata.getpdbdata(method_normalized)
+                trimsequence = sort_sequence(str(unidata.get_id()), norsub_seqdata, seq_ratio)
+                details = structure_details(
+                    unidata.pdbdata, negative_pdbid, judges, trimsequence, pdb_ids
+                )
+                with open(output_dir / f"structures_{uniprotid}.csv", "w", newline="") as f:
+                    writer = csv.DictWriter(f, fieldnames=STRUCTURE_FIELDS)
+                    writer.writeheader()
+                    writer.writerows(details)
+
             # log_allをパース
             lines = log_all.strip().split("\n")
             if len(lines) == 1:
+                print("Less than 3 chains")
+                insufficient_ids.append(uniprotid)
                 continue
 
             data_log_all_columns = lines[0].split()
@@ -808,6 +1084,7 @@ def run_notebook_dsa_analysis(
                             break
                 if not found:
                     existing_data.append(new_entry)
+            analyzed += 1
 
             # ヒートマップ生成
             if heatmap:
@@ -839,5 +1116,17 @@ def run_notebook_dsa_analysis(
 
     if verbose:
         print(f"Update '{filename}'")
+
+    # 1件も解析できず、その原因が構造不足なら専用の例外で知らせる（呼び出し側で理由を判別できるように）
+    if analyzed == 0 and oversized_ids:
+        raise InputTooLargeError(", ".join(oversized_ids))
+
+    if analyzed == 0 and insufficient_ids:
+        raise NoSuitableStructuresError(
+            f"No suitable structures for {', '.join(insufficient_ids)} "
+            f"(method={method}, seq_ratio={seq_ratio})"
+        )
+
+    if verbose:
         print("Job Completed")
 
@@ -0,0 +1,36 @@
+"""入力サイズの上限（-max-residues / -max-structures）のユニットテスト"""
+
+from flex_analyzer.notebook_dsa_pipeline import InputTooLargeError, input_size_exceeded
+
+
+def test_zero_means_unlimited():
+    """上限 0 は無制限"""
+    assert input_size_exceeded(100000, 5000) == ""
+    assert input_size_exceeded(100000, 5000, max_residues=0, max_structures=0) == ""
+
+
+def test_within_limits():
+    """上限ちょうどは超えていない"""
+    assert input_size_exceeded(500, 20, max_residues=500, max_structures=20) == ""
+
+
+def test_residue_limit_is_checked_first():
+    """両方超えている場合は残基数を報告する"""
+    assert input_size_exceeded(600, 30, max_residues=500, max_structures=20) == "600 residues (max 500)"
+
+
+def test_structure_limit():
+    assert input_size_exceeded(400, 30, max_residues=500, max_structures=20) == "30 PDB entries (max 20)"
+    assert input_size_exceeded(400, 30, max_structures=20) == "30 PDB entries (max 20)"
+
+
+def test_error_message_format():
+    """Go 側（failure_reason.go の inputTooLargePattern）が読むメッセージの形式"""
+    ids = [
+        f"P12345 ({input_size_exceeded(600, 3, max_residues=500)})",
+        f"Q67890 ({input_size_exceeded(100, 30, max_structures=20)})",
+    ]
+    err = InputTooLargeError(", ".join(ids))
+    assert f"InputTooLargeError: {err}" == (
+        "InputTooLargeError: P12345 (600 residues (max 500)), Q67890 (30 PDB entries (max 20))"
+    )